@@ -1,9 +1,22 @@
 package app
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/diff"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/variables"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func TestResolveApp(t *testing.T) {
@@ -48,3 +61,210 @@ func TestResolveApp(t *testing.T) {
 		})
 	}
 }
+
+func statusResponse(health ...applicationv1.DeploymentHealthStatus) *applicationv1.GetApplicationStatusResponse {
+	resp := &applicationv1.GetApplicationStatusResponse{}
+	for i, h := range health {
+		resp.Environments = append(resp.Environments, &applicationv1.EnvironmentStatus{
+			Environment:  fmt.Sprintf("env-%d", i),
+			Cluster:      "prod-us-east-1",
+			Revision:     "r1",
+			HealthStatus: h,
+			Message:      "image pull backoff",
+		})
+	}
+	return resp
+}
+
+func TestStatusWatcher(t *testing.T) {
+	const (
+		progressing = applicationv1.DeploymentHealthStatus_DEPLOYMENT_HEALTH_STATUS_PROGRESSING
+		healthy     = applicationv1.DeploymentHealthStatus_DEPLOYMENT_HEALTH_STATUS_HEALTHY
+		failed      = applicationv1.DeploymentHealthStatus_DEPLOYMENT_HEALTH_STATUS_FAILED
+	)
+
+	// polls serves the responses in order; the last one repeats.
+	polls := func(resps ...*applicationv1.GetApplicationStatusResponse) (func(context.Context) (*applicationv1.GetApplicationStatusResponse, error), *int) {
+		n := 0
+		return func(context.Context) (*applicationv1.GetApplicationStatusResponse, error) {
+			r := resps[min(n, len(resps)-1)]
+			n++
+			return r, nil
+		}, &n
+	}
+
+	t.Run("wait for healthy", func(t *testing.T) {
+		fetch, n := polls(statusResponse(progressing, healthy), statusResponse(healthy, healthy))
+		var out bytes.Buffer
+		w := &statusWatcher{out: &out, format: output.FormatTable, interval: time.Millisecond, waitFor: waitForHealthy, fetch: fetch}
+
+		require.NoError(t, w.run(context.Background()))
+		require.Equal(t, 2, *n)
+		// Without --watch only the final status is printed.
+		require.Equal(t, 1, strings.Count(out.String(), "ENVIRONMENT"))
+		require.Contains(t, out.String(), "Healthy")
+	})
+
+	t.Run("watch prints every poll", func(t *testing.T) {
+		fetch, _ := polls(statusResponse(progressing), statusResponse(progressing), statusResponse(healthy))
+		var out bytes.Buffer
+		w := &statusWatcher{out: &out, format: output.FormatTable, watch: true, interval: time.Millisecond, waitFor: waitForHealthy, fetch: fetch}
+
+		require.NoError(t, w.run(context.Background()))
+		require.Equal(t, 3, strings.Count(out.String(), "ENVIRONMENT"))
+		require.NotContains(t, out.String(), "\033[")
+	})
+
+	t.Run("failed deployment", func(t *testing.T) {
+		fetch, _ := polls(statusResponse(progressing, failed))
+		w := &statusWatcher{out: io.Discard, format: output.FormatTable, interval: time.Millisecond, waitFor: waitForHealthy, fetch: fetch}
+
+		require.EqualError(t, w.run(context.Background()), "deployment to env-1 failed: image pull backoff")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fetch, _ := polls(statusResponse(progressing))
+		w := &statusWatcher{out: io.Discard, format: output.FormatTable, interval: time.Millisecond, waitFor: waitForHealthy, fetch: fetch}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, w.run(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("no environments is not healthy", func(t *testing.T) {
+		w := &statusWatcher{waitFor: waitForHealthy}
+		done, err := w.evaluate(statusResponse())
+		require.NoError(t, err)
+		require.False(t, done)
+	})
+}
+
+func TestPlanClone(t *testing.T) {
+	appEnv := func(key, value string, sensitive bool) *variablev1.Variable {
+		return &variablev1.Variable{Key: key, Value: value, Sensitive: sensitive, Scope: variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV}
+	}
+
+	src := &cloneSide{
+		name: "staging",
+		env:  &environmentv1.Environment{SourceRef: "v1.4.0"},
+		vars: []*variablev1.Variable{
+			appEnv("REPLICAS", "3", false),
+			appEnv("LOG_LEVEL", "debug", false),
+			appEnv("API_TOKEN", "s3cr3t", true),
+			appEnv("FEATURE_X", "on", false),
+			appEnv("REGION", "us-east-1", false),
+		},
+	}
+	dst := &cloneSide{
+		name: "production",
+		env:  &environmentv1.Environment{SourceRef: "v1.3.2"},
+		vars: []*variablev1.Variable{
+			appEnv("REPLICAS", "6", false),
+			appEnv("REGION", "us-east-1", false),
+		},
+	}
+
+	actions := func(plan *clonePlan) map[string]string {
+		m := map[string]string{}
+		for _, item := range plan.Items {
+			m[item.Name] = item.Action
+		}
+		return m
+	}
+
+	t.Run("skip conflicts", func(t *testing.T) {
+		plan, err := planClone("billing-api", src, dst, cloneOptions{
+			includeVariables: true,
+			exclude:          []string{"FEATURE_X"},
+			onConflict:       onConflictSkip,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"source_ref": cloneSkip,
+			"API_TOKEN":  cloneSkip,
+			"FEATURE_X":  cloneSkip,
+			"LOG_LEVEL":  cloneCreate,
+			"REPLICAS":   cloneSkip,
+		}, actions(plan))
+		require.Equal(t, 1, plan.changes())
+	})
+
+	t.Run("overwrite with sensitive", func(t *testing.T) {
+		plan, err := planClone("billing-api", src, dst, cloneOptions{
+			includeVariables: true,
+			includeSensitive: true,
+			onConflict:       onConflictOverwrite,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"source_ref": cloneOverwrite,
+			"API_TOKEN":  cloneCreate,
+			"FEATURE_X":  cloneCreate,
+			"LOG_LEVEL":  cloneCreate,
+			"REPLICAS":   cloneOverwrite,
+		}, actions(plan))
+
+		// Sensitive values are masked in the plan but copied verbatim.
+		for _, item := range plan.Items {
+			if item.Name == "API_TOKEN" {
+				require.Equal(t, variables.MaskedValue, item.New)
+				require.Equal(t, "s3cr3t", item.variable.Value)
+			}
+		}
+	})
+
+	t.Run("fail on conflict", func(t *testing.T) {
+		_, err := planClone("billing-api", src, dst, cloneOptions{
+			includeVariables: true,
+			onConflict:       onConflictFail,
+		})
+		require.ErrorContains(t, err, "2 conflicting value(s) in production: source_ref, REPLICAS")
+	})
+
+	t.Run("constraint drift", func(t *testing.T) {
+		typed := appEnv("REGION", "us-east-1", false)
+		typed.Pattern = `^[a-z]+-[a-z]+-\d$`
+		plan, err := planClone("billing-api", &cloneSide{name: "staging", env: &environmentv1.Environment{}, vars: []*variablev1.Variable{typed}}, dst, cloneOptions{
+			includeVariables: true,
+			onConflict:       onConflictOverwrite,
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"REGION": cloneOverwrite}, actions(plan))
+		require.Equal(t, "us-east-1 (string)", plan.Items[0].Current)
+		require.Equal(t, `us-east-1 (string, pattern ^[a-z]+-[a-z]+-\d$)`, plan.Items[0].New)
+	})
+
+	t.Run("settings only", func(t *testing.T) {
+		plan, err := planClone("billing-api", src, &cloneSide{name: "production", env: &environmentv1.Environment{}}, cloneOptions{onConflict: onConflictFail})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"source_ref": cloneCreate}, actions(plan))
+	})
+}
+
+func TestMaskSensitive(t *testing.T) {
+	token := diff.Pointer("variables", "API_TOKEN")
+	level := diff.Pointer("variables", "LOG_LEVEL")
+	from := diff.Document{token: "s3cr3t", level: "info"}
+	to := diff.Document{token: "r0tated", level: "info"}
+	sensitive := map[string]bool{token: true}
+
+	// Only the sensitive value differs, and the difference is still found.
+	patch := diff.Compare(from, to)
+	require.Equal(t, []diff.Operation{{Op: diff.OpReplace, Path: token, Value: "r0tated"}}, patch)
+
+	maskedFrom, maskedTo, maskedPatch := maskSensitive(from, to, patch, sensitive)
+	require.Equal(t, []diff.Operation{{Op: diff.OpReplace, Path: token, Value: variables.MaskedValue}}, maskedPatch)
+	require.Equal(t, "r0tated", patch[0].Value)
+
+	var buf bytes.Buffer
+	diff.WriteUnified(&buf, "staging", "production", maskedFrom, maskedTo, false)
+	require.Contains(t, buf.String(), "-variables.API_TOKEN: "+variables.MaskedValue)
+	require.Contains(t, buf.String(), "+variables.API_TOKEN: "+changedMaskedValue)
+	require.NotContains(t, buf.String(), "s3cr3t")
+	require.NotContains(t, buf.String(), "r0tated")
+
+	// An unchanged sensitive value is masked the same on both sides.
+	to[token] = "s3cr3t"
+	maskedFrom, maskedTo, _ = maskSensitive(from, to, nil, sensitive)
+	require.Equal(t, maskedFrom, maskedTo)
+}
@@ -1,14 +1,43 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// Conflict policies for --on-conflict.
+const (
+	onConflictSkip      = "skip"
+	onConflictOverwrite = "overwrite"
+	onConflictFail      = "fail"
+)
+
+// Clone plan actions.
+const (
+	cloneCreate    = "create"
+	cloneOverwrite = "overwrite"
+	cloneSkip      = "skip"
+)
+
+// Kinds of items in a clone plan.
+const (
+	cloneKindSetting  = "setting"
+	cloneKindVariable = "variable"
 )
 
 func newCloneCmd(opts *factory.Options) *cobra.Command {
@@ -17,6 +46,9 @@ func newCloneCmd(opts *factory.Options) *cobra.Command {
 		to               string
 		includeVariables bool
 		excludeVariable  []string
+		includeSensitive bool
+		onConflict       string
+		confirm          bool
 	)
 
 	cmd := &cobra.Command{
@@ -27,7 +59,20 @@ func newCloneCmd(opts *factory.Options) *cobra.Command {
 The app can be provided as a positional argument or resolved from the
 active context set via 'admiral use <app>'.
 
-Both --from and --to are required.`,
+Both --from and --to are required.
+
+The source ref is always cloned. With --include-variables, variables set
+on the source environment are copied too; global and app-wide variables
+already apply to both environments and are left alone. Sensitive variables
+are never copied unless --include-sensitive is given.
+
+Before anything is changed, a plan of what will be created, overwritten or
+skipped in the target environment is printed and you are asked to confirm.
+Use --confirm to skip the prompt.
+
+--on-conflict decides what happens when the target already has a different
+value: skip keeps it (the default), overwrite replaces it, and fail aborts
+the clone without changing anything.`,
 		Example: `  # Clone staging to production
   admiral app clone billing-api --from staging --to production
 
@@ -37,6 +82,9 @@ Both --from and --to are required.`,
   # Clone but exclude specific variables
   admiral app clone billing-api --from staging --to production --include-variables --exclude-variable SECRET_KEY
 
+  # Overwrite existing values without prompting
+  admiral app clone billing-api --from staging --to production --include-variables --on-conflict overwrite --confirm
+
   # Use the active app context
   admiral use billing-api
   admiral app clone --from staging --to production`,
@@ -47,6 +95,15 @@ Both --from and --to are required.`,
 				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
 				return fmt.Errorf("both --from and --to are required")
 			}
+			if from == to {
+				return fmt.Errorf("--from and --to must be different environments")
+			}
+
+			switch onConflict {
+			case onConflictSkip, onConflictOverwrite, onConflictFail:
+			default:
+				return fmt.Errorf("invalid --on-conflict %q: must be one of skip, overwrite, fail", onConflict)
+			}
 
 			var appArg string
 			if len(args) == 1 {
@@ -63,25 +120,63 @@ Both --from and --to are required.`,
 				return err
 			}
 
-			flags := map[string]any{
-				"from": from,
-				"to":   to,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			src, err := fetchCloneSide(cmd.Context(), c, app, from, includeVariables, includeSensitive)
+			if err != nil {
+				return err
+			}
+			dst, err := fetchCloneSide(cmd.Context(), c, app, to, includeVariables, includeSensitive)
+			if err != nil {
+				return err
+			}
+
+			plan, err := planClone(app, src, dst, cloneOptions{
+				includeVariables: includeVariables,
+				includeSensitive: includeSensitive,
+				exclude:          excludeVariable,
+				onConflict:       onConflict,
+			})
+			if err != nil {
+				return err
 			}
-			if includeVariables {
-				flags["include-variables"] = true
+
+			p := output.NewPrinter(opts.OutputFormat)
+			p.Out = cmd.OutOrStdout()
+			if err := p.PrintObject(plan, func(w *tabwriter.Writer) {
+				printClonePlan(w, plan)
+			}); err != nil {
+				return err
 			}
-			if len(excludeVariable) > 0 {
-				flags["exclude-variable"] = excludeVariable
+
+			if plan.changes() == 0 {
+				return nil
 			}
 
-			stub := cmdutil.StubResult{
-				Command: "app clone",
-				App:     app,
-				Flags:   flags,
-				Status:  cmdutil.StubStatus,
+			if !confirm {
+				ok, err := cmdutil.ConfirmPrompt(
+					cmd.InOrStdin(), cmd.ErrOrStderr(),
+					fmt.Sprintf("Apply this plan to %s?", to),
+				)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					cmdutil.Writef(cmd.ErrOrStderr(), "Aborted.\n")
+					return &cmdutil.ExitError{Code: 1}
+				}
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			if err := applyClone(cmd.Context(), c, plan); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.ErrOrStderr(), "Cloned %s from %s to %s.\n", app, from, to)
+			return nil
 		},
 	}
 
@@ -89,6 +184,251 @@ Both --from and --to are required.`,
 	cmd.Flags().StringVar(&to, "to", "", "target environment (required)")
 	cmd.Flags().BoolVar(&includeVariables, "include-variables", false, "include variables in the clone")
 	cmd.Flags().StringArrayVar(&excludeVariable, "exclude-variable", nil, "variable keys to exclude (repeatable)")
+	cmd.Flags().BoolVar(&includeSensitive, "include-sensitive", false, "also copy variables marked sensitive")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", onConflictSkip, "what to do when the target has a different value: skip, overwrite or fail")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "apply the plan without prompting")
 
 	return cmd
 }
+
+// cloneSide is the configuration of one environment taking part in a clone.
+type cloneSide struct {
+	name string
+	env  *environmentv1.Environment
+	vars []*variablev1.Variable
+}
+
+// fetchCloneSide fetches env and, if requested, the variables set directly
+// on it. Sensitive values are only fetched when they may be copied.
+func fetchCloneSide(ctx context.Context, c client.AdmiralClient, app, env string, withVars, reveal bool) (*cloneSide, error) {
+	resp, err := c.Environment().GetEnvironment(ctx, &environmentv1.GetEnvironmentRequest{
+		ApplicationId: app,
+		EnvironmentId: env,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment %q: %w", env, err)
+	}
+
+	side := &cloneSide{name: env, env: resp.Environment}
+	if withVars {
+		side.vars, err = variables.ListAll(ctx, c.Variable(), &variablev1.ListVariablesRequest{
+			Scope:           variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV,
+			ApplicationId:   app,
+			EnvironmentId:   env,
+			RevealSensitive: reveal,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables for %q: %w", env, err)
+		}
+	}
+	return side, nil
+}
+
+type cloneOptions struct {
+	includeVariables bool
+	includeSensitive bool
+	exclude          []string
+	onConflict       string
+}
+
+// clonePlan lists what a clone will change in the target environment.
+type clonePlan struct {
+	App   string          `json:"app" yaml:"app"`
+	From  string          `json:"from" yaml:"from"`
+	To    string          `json:"to" yaml:"to"`
+	Items []clonePlanItem `json:"items" yaml:"items"`
+}
+
+// clonePlanItem is a single setting or variable in a clone plan. Current and
+// New are display values; sensitive values are masked.
+type clonePlanItem struct {
+	Action  string `json:"action" yaml:"action"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Name    string `json:"name" yaml:"name"`
+	Current string `json:"current,omitempty" yaml:"current,omitempty"`
+	New     string `json:"new,omitempty" yaml:"new,omitempty"`
+	Reason  string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// variable is the source variable to copy; nil for settings.
+	variable *variablev1.Variable
+	// sourceRef is the value to set for the source_ref setting.
+	sourceRef string
+}
+
+// changes returns the number of items that will be written.
+func (p *clonePlan) changes() int {
+	n := 0
+	for _, item := range p.Items {
+		if item.Action != cloneSkip {
+			n++
+		}
+	}
+	return n
+}
+
+// planClone computes the clone plan. Items whose value already matches the
+// target are left out. With --on-conflict=fail, any conflicting item is an
+// error.
+func planClone(app string, src, dst *cloneSide, o cloneOptions) (*clonePlan, error) {
+	plan := &clonePlan{App: app, From: src.name, To: dst.name, Items: []clonePlanItem{}}
+	var conflicts []string
+
+	// resolve records an item whose target already holds a different value.
+	resolve := func(item clonePlanItem) {
+		switch o.onConflict {
+		case onConflictOverwrite:
+			item.Action = cloneOverwrite
+		case onConflictFail:
+			conflicts = append(conflicts, item.Name)
+			return
+		default:
+			item.Action = cloneSkip
+			item.Reason = "exists in " + plan.To
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	if ref := src.env.SourceRef; ref != "" && ref != dst.env.SourceRef {
+		item := clonePlanItem{Kind: cloneKindSetting, Name: "source_ref", Current: dst.env.SourceRef, New: ref, sourceRef: ref}
+		if dst.env.SourceRef == "" {
+			item.Action = cloneCreate
+			plan.Items = append(plan.Items, item)
+		} else {
+			resolve(item)
+		}
+	}
+
+	if o.includeVariables {
+		existing := make(map[string]*variablev1.Variable, len(dst.vars))
+		for _, v := range dst.vars {
+			existing[v.Key] = v
+		}
+
+		sorted := slices.Clone(src.vars)
+		slices.SortFunc(sorted, func(a, b *variablev1.Variable) int {
+			return strings.Compare(a.Key, b.Key)
+		})
+
+		for _, v := range sorted {
+			item := clonePlanItem{Kind: cloneKindVariable, Name: v.Key, New: variables.DisplayValue(v, false), variable: v}
+
+			switch {
+			case slices.Contains(o.exclude, v.Key):
+				item.Action = cloneSkip
+				item.Reason = "excluded"
+				plan.Items = append(plan.Items, item)
+				continue
+			case v.Sensitive && !o.includeSensitive:
+				item.Action = cloneSkip
+				item.Reason = "sensitive (use --include-sensitive)"
+				plan.Items = append(plan.Items, item)
+				continue
+			}
+
+			cur, ok := existing[v.Key]
+			switch {
+			case !ok:
+				item.Action = cloneCreate
+				plan.Items = append(plan.Items, item)
+			case !sameConstraints(cur, v):
+				item.Current = withConstraints(cur)
+				item.New = withConstraints(v)
+				resolve(item)
+			case cur.Value != v.Value || cur.Sensitive != v.Sensitive:
+				item.Current = variables.DisplayValue(cur, false)
+				resolve(item)
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%d conflicting value(s) in %s: %s (use --on-conflict=skip or --on-conflict=overwrite)",
+			len(conflicts), plan.To, strings.Join(conflicts, ", "))
+	}
+
+	return plan, nil
+}
+
+// sameConstraints reports whether a and b have the same type and pattern.
+// Untyped variables are plain strings.
+func sameConstraints(a, b *variablev1.Variable) bool {
+	return variables.FormatType(a.Type) == variables.FormatType(b.Type) && a.Pattern == b.Pattern
+}
+
+// withConstraints returns the display value of v followed by its type and
+// pattern, for plan items whose constraints differ.
+func withConstraints(v *variablev1.Variable) string {
+	s := fmt.Sprintf("%s (%s", variables.DisplayValue(v, false), variables.FormatType(v.Type))
+	if v.Pattern != "" {
+		s += ", pattern " + v.Pattern
+	}
+	return s + ")"
+}
+
+func printClonePlan(w *tabwriter.Writer, plan *clonePlan) {
+	if len(plan.Items) == 0 {
+		output.Writef(w, "Nothing to clone: %s already matches %s.\n", plan.To, plan.From)
+		return
+	}
+
+	output.Writef(w, "Plan: clone %s from %s to %s\n\n", plan.App, plan.From, plan.To)
+	output.Writeln(w, "ACTION\tKIND\tNAME\tDETAIL")
+
+	counts := map[string]int{}
+	for _, item := range plan.Items {
+		counts[item.Action]++
+
+		detail := item.Reason
+		switch item.Action {
+		case cloneCreate:
+			detail = item.New
+		case cloneOverwrite:
+			detail = item.Current + " -> " + item.New
+		}
+		output.Writef(w, "%s\t%s\t%s\t%s\n", item.Action, item.Kind, item.Name, detail)
+	}
+
+	output.Writef(w, "\n%d to create, %d to overwrite, %d to skip.\n",
+		counts[cloneCreate], counts[cloneOverwrite], counts[cloneSkip])
+}
+
+// applyClone writes the plan's create and overwrite items to the target
+// environment.
+func applyClone(ctx context.Context, c client.AdmiralClient, plan *clonePlan) error {
+	for _, item := range plan.Items {
+		if item.Action == cloneSkip {
+			continue
+		}
+
+		switch item.Kind {
+		case cloneKindSetting:
+			_, err := c.Environment().UpdateEnvironment(ctx, &environmentv1.UpdateEnvironmentRequest{
+				Environment: &environmentv1.Environment{
+					Id:            plan.To,
+					ApplicationId: plan.App,
+					SourceRef:     item.sourceRef,
+				},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{item.Name}},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set %s on %s: %w", item.Name, plan.To, err)
+			}
+		case cloneKindVariable:
+			v := item.variable
+			_, err := c.Variable().SetVariable(ctx, &variablev1.SetVariableRequest{
+				Scope:         variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV,
+				ApplicationId: plan.App,
+				EnvironmentId: plan.To,
+				Key:           v.Key,
+				Value:         v.Value,
+				Sensitive:     v.Sensitive,
+				Type:          v.Type,
+				Pattern:       v.Pattern,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set variable %s on %s: %w", v.Key, plan.To, err)
+			}
+		}
+	}
+	return nil
+}
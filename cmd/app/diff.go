@@ -1,20 +1,28 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"maps"
+	"slices"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/diff"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newDiffCmd(opts *factory.Options) *cobra.Command {
 	var (
-		from string
-		to   string
+		from   string
+		to     string
+		reveal bool
 	)
 
 	cmd := &cobra.Command{
@@ -25,10 +33,25 @@ func newDiffCmd(opts *factory.Options) *cobra.Command {
 The app can be provided as a positional argument or resolved from the
 active context set via 'admiral use <app>'.
 
-Both --from and --to are required.`,
+Both --from and --to are required.
+
+The comparison covers each environment's effective configuration: cluster
+binding, source ref, promotion order, and variables after scope resolution
+(global, then app, then app+environment). Sensitive values are compared
+too, but printed only with --reveal; otherwise they are masked, and one that
+differs is shown as <sensitive, changed> on the --to side.
+
+The table output is a unified diff, colored when writing to a terminal.
+-o json|yaml prints a JSON Patch (RFC 6902) that turns --from into --to.
+
+Like 'git diff --exit-code', the command exits with status 1 when the
+environments differ and 0 when they match.`,
 		Example: `  # Compare staging and production
   admiral app diff billing-api --from staging --to production
 
+  # Print the differences as a JSON Patch
+  admiral app diff billing-api --from staging --to production -o json
+
   # Use the active app context
   admiral use billing-api
   admiral app diff --from staging --to production`,
@@ -55,22 +78,120 @@ Both --from and --to are required.`,
 				return err
 			}
 
-			stub := cmdutil.StubResult{
-				Command: "app diff",
-				App:     app,
-				Flags: map[string]any{
-					"from": from,
-					"to":   to,
-				},
-				Status: cmdutil.StubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			fromDoc, sensitive, err := effectiveConfig(cmd.Context(), c, app, from)
+			if err != nil {
+				return err
+			}
+			toDoc, toSensitive, err := effectiveConfig(cmd.Context(), c, app, to)
+			if err != nil {
+				return err
+			}
+			for path := range toSensitive {
+				sensitive[path] = true
+			}
+
+			patch := diff.Compare(fromDoc, toDoc)
+			if !reveal {
+				fromDoc, toDoc, patch = maskSensitive(fromDoc, toDoc, patch, sensitive)
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.OutputFormat.IsTable() {
+				if len(patch) == 0 {
+					output.Writef(out, "No differences between %s and %s.\n", from, to)
+				}
+				diff.WriteUnified(out, from, to, fromDoc, toDoc, output.ColorEnabled(out))
+			} else {
+				p := output.NewPrinter(opts.OutputFormat)
+				p.Out = out
+				if err := p.PrintObject(patch, nil); err != nil {
+					return err
+				}
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			if len(patch) > 0 {
+				return &cmdutil.ExitError{Code: 1}
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&from, "from", "", "source environment (required)")
 	cmd.Flags().StringVar(&to, "to", "", "target environment (required)")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "show sensitive variable values")
 
 	return cmd
 }
+
+// changedMaskedValue replaces a sensitive value that differs between the
+// environments on the --to side, so the change shows without the value.
+const changedMaskedValue = "<sensitive, changed>"
+
+// effectiveConfig fetches the configuration that applies to app in env as a
+// flat document for comparison, with sensitive values revealed, and the
+// paths of the sensitive values.
+func effectiveConfig(ctx context.Context, c client.AdmiralClient, app, env string) (diff.Document, map[string]bool, error) {
+	resp, err := c.Environment().GetEnvironment(ctx, &environmentv1.GetEnvironmentRequest{
+		ApplicationId: app,
+		EnvironmentId: env,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get environment %q: %w", env, err)
+	}
+
+	vars, err := variables.Effective(ctx, c.Variable(), app, env, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve variables for %q: %w", env, err)
+	}
+
+	e := resp.Environment
+	doc := diff.Document{
+		diff.Pointer("cluster"):         e.Cluster,
+		diff.Pointer("source_ref"):      e.SourceRef,
+		diff.Pointer("promotion_order"): e.PromotionOrder,
+	}
+	sensitive := map[string]bool{}
+	for _, v := range vars {
+		path := diff.Pointer("variables", v.Key)
+		doc[path] = v.Value
+		if v.Sensitive {
+			sensitive[path] = true
+		}
+	}
+	return doc, sensitive, nil
+}
+
+// maskSensitive returns copies of from, to and patch with the values at the
+// sensitive paths masked. A value that differs is masked differently on the
+// to side so the rendered diff still shows the change.
+func maskSensitive(from, to diff.Document, patch []diff.Operation, sensitive map[string]bool) (diff.Document, diff.Document, []diff.Operation) {
+	maskedFrom := maps.Clone(from)
+	maskedTo := maps.Clone(to)
+	for path := range sensitive {
+		fromValue, inFrom := from[path]
+		toValue, inTo := to[path]
+		if inFrom {
+			maskedFrom[path] = variables.MaskedValue
+		}
+		if inTo {
+			maskedTo[path] = variables.MaskedValue
+			if inFrom && fromValue != toValue {
+				maskedTo[path] = changedMaskedValue
+			}
+		}
+	}
+
+	maskedPatch := slices.Clone(patch)
+	for i, op := range maskedPatch {
+		if sensitive[op.Path] && op.Value != nil {
+			maskedPatch[i].Value = variables.MaskedValue
+		}
+	}
+	return maskedFrom, maskedTo, maskedPatch
+}
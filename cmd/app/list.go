@@ -1,9 +1,11 @@
 package app
 
 import (
+	"context"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
@@ -13,29 +15,51 @@ import (
 
 func newListCmd(opts *factory.Options) *cobra.Command {
 	var (
-		pageSize  int32
-		pageToken string
+		pager     cmdutil.Pager
 		labelStrs []string
+		selector  string
+		rawFilter string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List applications",
-		Long:  `List all applications visible to the current user.`,
+		Long: `List all applications visible to the current user.
+
+One page of results is returned by default, and the token for the next
+page is printed to stderr. Use --all to fetch every page, or --limit to
+fetch pages until that many applications are returned. Table output is
+printed a page at a time; json and yaml output is a single list of every
+application.
+
+Filter with --label (exact key=value matches), --selector (kubectl-style
+label selectors) or --filter (a raw API filter expression). When several
+are given, an application must match all of them.`,
 		Example: `  # List all applications
   admiral app list
 
   # List with label filter
   admiral app list --label team=platform
 
+  # List with a label selector
+  admiral app list -l 'tier in (web,api),team!=core,!deprecated'
+
   # Paginated listing
-  admiral app list --page-size 10`,
+  admiral app list --page-size 10
+
+  # Every application as one JSON document
+  admiral app list --all -o json`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			filter, err := cmdutil.BuildLabelFilter(labelStrs)
+			labelFilter, err := cmdutil.BuildLabelFilter(labelStrs)
+			if err != nil {
+				return err
+			}
+			selectorFilter, err := cmdutil.ParseSelector(selector)
 			if err != nil {
 				return err
 			}
+			filter := cmdutil.JoinFilters(labelFilter, selectorFilter, rawFilter)
 
 			c, err := factory.CreateClient(cmd.Context(), opts)
 			if err != nil {
@@ -43,20 +67,29 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 			}
 			defer c.Close() //nolint:errcheck // best-effort cleanup
 
-			resp, err := c.Application().ListApplications(cmd.Context(), &applicationv1.ListApplicationsRequest{
-				PageSize:  pageSize,
-				PageToken: pageToken,
-				Filter:    filter,
-			})
-			if err != nil {
-				return err
+			fetch := func(ctx context.Context, pageSize int32, pageToken string) ([]*applicationv1.Application, string, error) {
+				resp, err := c.Application().ListApplications(ctx, &applicationv1.ListApplicationsRequest{
+					PageSize:  pageSize,
+					PageToken: pageToken,
+					Filter:    filter,
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Applications, resp.NextPageToken, nil
 			}
 
-			p := output.NewPrinter(opts.OutputFormat)
-			if err := p.PrintResource(resp, func(w *tabwriter.Writer) {
-				if opts.OutputFormat == output.FormatWide {
-					output.Writeln(w, "NAME\tDESCRIPTION\tAGE\tLABELS\tCREATED\tUPDATED")
-					for _, app := range resp.Applications {
+			wide := opts.OutputFormat == output.FormatWide
+			return cmdutil.PrintList(cmd, opts.OutputFormat, &pager, fetch, cmdutil.ListOutput[*applicationv1.Application]{
+				Header: func(w *tabwriter.Writer) {
+					if wide {
+						output.Writeln(w, "NAME\tDESCRIPTION\tAGE\tLABELS\tCREATED\tUPDATED")
+					} else {
+						output.Writeln(w, "NAME\tDESCRIPTION\tAGE")
+					}
+				},
+				Row: func(w *tabwriter.Writer, app *applicationv1.Application) {
+					if wide {
 						output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
 							app.Name,
 							app.Description,
@@ -65,32 +98,25 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 							output.FormatTimestamp(app.CreatedAt),
 							output.FormatTimestamp(app.UpdatedAt),
 						)
-					}
-				} else {
-					output.Writeln(w, "NAME\tDESCRIPTION\tAGE")
-					for _, app := range resp.Applications {
+					} else {
 						output.Writef(w, "%s\t%s\t%s\n",
 							app.Name,
 							app.Description,
 							output.FormatAge(app.CreatedAt),
 						)
 					}
-				}
-			}); err != nil {
-				return err
-			}
-
-			if resp.NextPageToken != "" {
-				output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", resp.NextPageToken)
-			}
-
-			return nil
+				},
+				Merge: func(items []*applicationv1.Application) proto.Message {
+					return &applicationv1.ListApplicationsResponse{Applications: items}
+				},
+			})
 		},
 	}
 
-	cmd.Flags().Int32Var(&pageSize, "page-size", 50, "maximum number of results per page")
-	cmd.Flags().StringVar(&pageToken, "page-token", "", "pagination token from a previous response")
+	cmdutil.AddPagerFlags(cmd, &pager, "applications")
 	cmdutil.AddLabelFlag(cmd, &labelStrs, "filter by label (key=value, repeatable)")
+	cmdutil.AddSelectorFlag(cmd, &selector)
+	cmd.Flags().StringVar(&rawFilter, "filter", "", "raw API filter expression, e.g. 'labels.env = \"prod\" OR labels.env = \"staging\"'")
 
 	return cmd
 }
@@ -1,17 +1,34 @@
 package app
 
 import (
-	"os"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
 )
 
+// waitForHealthy is the only condition accepted by --wait-for.
+const waitForHealthy = "healthy"
+
 func newStatusCmd(opts *factory.Options) *cobra.Command {
-	var envFlag string
+	var (
+		envFlag  string
+		watch    bool
+		interval time.Duration
+		waitFor  string
+		timeout  time.Duration
+	)
 
 	cmd := &cobra.Command{
 		Use:   "status [app]",
@@ -21,18 +38,36 @@ func newStatusCmd(opts *factory.Options) *cobra.Command {
 The app can be provided as a positional argument or resolved from the
 active context set via 'admiral use <app>'.
 
-Use -e/--env to filter to a specific environment.`,
+Use -e/--env to filter to a specific environment.
+
+Use --watch to keep polling and re-render the table as the deployment
+progresses. Use --wait-for healthy to block until every environment reports
+healthy; the command exits non-zero if a deployment fails or --timeout
+elapses first.`,
 		Example: `  # Show status across all environments
   admiral app status billing-api
 
   # Show status for a specific environment
   admiral app status billing-api -e staging
 
+  # Watch a rollout
+  admiral app status billing-api -e staging --watch
+
+  # Gate a deploy script on the rollout becoming healthy
+  admiral app status billing-api -e production --wait-for healthy --timeout 10m
+
   # Use the active app context
   admiral use billing-api
   admiral app status -e production`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if waitFor != "" && waitFor != waitForHealthy {
+				return fmt.Errorf("invalid --wait-for %q: must be %q", waitFor, waitForHealthy)
+			}
+			if interval <= 0 {
+				return errors.New("--interval must be positive")
+			}
+
 			var appArg string
 			if len(args) == 1 {
 				appArg = args[0]
@@ -48,24 +83,178 @@ Use -e/--env to filter to a specific environment.`,
 				return err
 			}
 
-			flags := map[string]any{}
-			if envFlag != "" {
-				flags["env"] = envFlag
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			fetch := func(ctx context.Context) (*applicationv1.GetApplicationStatusResponse, error) {
+				return c.Application().GetApplicationStatus(ctx, &applicationv1.GetApplicationStatusRequest{
+					ApplicationId: app,
+					EnvironmentId: envFlag,
+				})
 			}
 
-			stub := cmdutil.StubResult{
-				Command:     "app status",
-				App:         app,
-				Environment: envFlag,
-				Flags:       flags,
-				Status:      cmdutil.StubStatus,
+			if !watch && waitFor == "" {
+				resp, err := fetch(cmd.Context())
+				if err != nil {
+					return err
+				}
+				return printStatus(cmd.OutOrStdout(), opts.OutputFormat, resp)
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			ctx := cmd.Context()
+			if waitFor != "" && timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			w := &statusWatcher{
+				out:      cmd.OutOrStdout(),
+				format:   opts.OutputFormat,
+				watch:    watch,
+				interval: interval,
+				waitFor:  waitFor,
+				fetch:    fetch,
+			}
+			err = w.run(ctx)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("timed out after %s waiting for %s to become %s", timeout, app, waitFor)
+			}
+			return err
 		},
 	}
 
 	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "filter to a specific environment")
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "keep polling and re-render as the deployment progresses")
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "polling interval for --watch and --wait-for")
+	cmd.Flags().StringVar(&waitFor, "wait-for", "", "block until the deployment reaches a condition (healthy)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "maximum time to wait with --wait-for (0 waits forever)")
 
 	return cmd
 }
+
+// statusWatcher polls deployment status until the wait condition is met or
+// the context ends. In watch mode every poll is printed; on a terminal the
+// table is redrawn in place rather than appended.
+type statusWatcher struct {
+	out      io.Writer
+	format   output.Format
+	watch    bool
+	interval time.Duration
+	waitFor  string
+	fetch    func(context.Context) (*applicationv1.GetApplicationStatusResponse, error)
+
+	// drawn is the number of lines of the last table drawn in place.
+	drawn int
+}
+
+func (s *statusWatcher) run(ctx context.Context) error {
+	for {
+		resp, err := s.fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		done, waitErr := s.evaluate(resp)
+		if s.watch || done || waitErr != nil {
+			if err := s.render(resp); err != nil {
+				return err
+			}
+		}
+		if waitErr != nil {
+			return waitErr
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.interval):
+		}
+	}
+}
+
+// evaluate reports whether the wait condition is met. A failed deployment
+// ends the wait with an error since it won't recover on its own.
+func (s *statusWatcher) evaluate(resp *applicationv1.GetApplicationStatusResponse) (bool, error) {
+	if s.waitFor == "" {
+		return false, nil
+	}
+
+	if len(resp.Environments) == 0 {
+		return false, nil
+	}
+
+	healthy := true
+	for _, e := range resp.Environments {
+		switch e.HealthStatus {
+		case applicationv1.DeploymentHealthStatus_DEPLOYMENT_HEALTH_STATUS_HEALTHY:
+		case applicationv1.DeploymentHealthStatus_DEPLOYMENT_HEALTH_STATUS_FAILED:
+			if e.Message != "" {
+				return false, fmt.Errorf("deployment to %s failed: %s", e.Environment, e.Message)
+			}
+			return false, fmt.Errorf("deployment to %s failed", e.Environment)
+		default:
+			healthy = false
+		}
+	}
+	return healthy, nil
+}
+
+func (s *statusWatcher) render(resp *applicationv1.GetApplicationStatusResponse) error {
+	inPlace := s.format.IsTable() && output.IsTerminal(s.out)
+	if !inPlace {
+		return printStatus(s.out, s.format, resp)
+	}
+
+	var buf bytes.Buffer
+	if err := printStatus(&buf, s.format, resp); err != nil {
+		return err
+	}
+
+	if s.drawn > 0 {
+		// Move the cursor to the start of the previous table and clear
+		// everything below it.
+		output.Writef(s.out, "\033[%dA\033[J", s.drawn)
+	}
+	s.drawn = strings.Count(buf.String(), "\n")
+	_, err := s.out.Write(buf.Bytes())
+	return err
+}
+
+func printStatus(out io.Writer, format output.Format, resp *applicationv1.GetApplicationStatusResponse) error {
+	p := output.NewPrinter(format)
+	p.Out = out
+	return p.PrintResource(resp, func(w *tabwriter.Writer) {
+		if format == output.FormatWide {
+			output.Writeln(w, "ENVIRONMENT\tCLUSTER\tREVISION\tHEALTH\tAGE\tDEPLOYED\tMESSAGE")
+			for _, e := range resp.Environments {
+				output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					e.Environment,
+					e.Cluster,
+					e.Revision,
+					output.FormatEnum(e.HealthStatus.String(), "DEPLOYMENT_HEALTH_STATUS_"),
+					output.FormatAge(e.DeployedAt),
+					output.FormatTimestamp(e.DeployedAt),
+					e.Message,
+				)
+			}
+		} else {
+			output.Writeln(w, "ENVIRONMENT\tCLUSTER\tREVISION\tHEALTH\tAGE")
+			for _, e := range resp.Environments {
+				output.Writef(w, "%s\t%s\t%s\t%s\t%s\n",
+					e.Environment,
+					e.Cluster,
+					e.Revision,
+					output.FormatEnum(e.HealthStatus.String(), "DEPLOYMENT_HEALTH_STATUS_"),
+					output.FormatAge(e.DeployedAt),
+				)
+			}
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/manifest"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newApplyCmd(opts *factory.Options) *cobra.Command {
+	var (
+		files   []string
+		set     string
+		prune   bool
+		dryRun  bool
+		confirm bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply -f PATH",
+		Short: "Create or update resources from manifests",
+		Long: `Create or update clusters, applications, environments and variables from
+YAML manifests.
+
+Each YAML document declares one resource:
+
+  apiVersion: admiral.io/v1
+  kind: Application           # Application, Cluster, Environment or VariableSet
+  metadata:
+    name: billing-api
+    labels:
+      team: payments
+  spec:
+    description: Handles billing
+
+An Environment names its application in metadata.application and sets
+cluster, promotionOrder, lifecycle, parent, sourceRef and ttl in its spec. A
+VariableSet holds the variables of one scope: global, or the application
+and environment in its metadata.
+
+Live state is compared with the manifests and the resulting plan is printed
+before anything is applied: resources to create, to update (with the fields
+that change) and to delete. Changes are applied in dependency order:
+clusters, then applications, then environments, then variables. Use
+--dry-run to print the plan without applying it, or -o json|yaml to print it
+as a document.
+
+With --set, every application, cluster and environment is labelled
+admiral.io/manifest-set=SET. --prune then deletes resources carrying that
+label that are no longer declared, and variables missing from a declared
+VariableSet. Deletes of either prompt for confirmation unless --confirm is
+given. Without a terminal to prompt on, e.g. in CI or with -f -, --prune
+requires --confirm.
+
+A variable can read its value from an environment variable instead of the
+manifest, so secrets stay out of git:
+
+  - key: DB_PASSWORD
+    sensitive: true
+    valueFrom:
+      env: BILLING_API_DB_PASSWORD
+
+If the environment variable is unset, the live value is kept. Sensitive
+values aren't returned by the API, so sensitive variables with a value are
+always set again. The plan never shows sensitive values, in any format.
+
+'admiral export' writes manifests in this format.`,
+		Example: `  # Preview the changes in a directory of manifests
+  admiral apply -f platform/ --dry-run
+
+  # Apply them
+  admiral apply -f platform/
+
+  # Apply as the "platform" set, deleting resources removed from it
+  admiral apply -f platform/ --set platform --prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(files) == 0 {
+				_ = cmd.Help()
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return fmt.Errorf("-f is required")
+			}
+			if prune && set == "" {
+				return fmt.Errorf("--prune requires --set")
+			}
+			if prune && !confirm && !dryRun &&
+				(slices.Contains(files, "-") || !cmdutil.CanPrompt(cmd.InOrStdin())) {
+				return fmt.Errorf("--prune requires --confirm when stdin is not a terminal or is read with -f -")
+			}
+
+			manifests, err := manifest.Load(cmd.InOrStdin(), files)
+			if err != nil {
+				return err
+			}
+			if err := manifest.ResolveValues(manifests, os.LookupEnv); err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			live, err := manifest.FetchLive(cmd.Context(), c, manifests, set)
+			if err != nil {
+				return err
+			}
+
+			plan, err := manifest.NewPlan(manifests, live, manifest.Options{Set: set, Prune: prune})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.OutputFormat.IsTable() {
+				manifest.PrintPlan(out, plan)
+			} else {
+				p := output.NewPrinter(opts.OutputFormat)
+				p.Out = out
+				if err := p.PrintObject(plan, nil); err != nil {
+					return err
+				}
+			}
+
+			if dryRun || plan.Empty() {
+				return nil
+			}
+
+			deleted, removed := plan.Count(manifest.ActionDelete), plan.RemovedVariables()
+			if (deleted > 0 || removed > 0) && !confirm {
+				ok, err := cmdutil.ConfirmPrompt(
+					cmd.InOrStdin(), cmd.ErrOrStderr(),
+					fmt.Sprintf("This deletes %d resources and %d variables. Continue?", deleted, removed),
+				)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					cmdutil.Writef(cmd.ErrOrStderr(), "Aborted.\n")
+					return &cmdutil.ExitError{Code: 1}
+				}
+			}
+
+			if err := manifest.Apply(cmd.Context(), c, plan, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.ErrOrStderr(), "Applied %d created, %d updated, %d deleted, %d variables removed.\n",
+				plan.Count(manifest.ActionCreate), plan.Count(manifest.ActionUpdate), deleted, removed)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&files, "filename", "f", nil, "manifest file or directory, or - for stdin (repeatable)")
+	cmd.Flags().StringVar(&set, "set", "", "name of the manifest set, labelled on every applied resource")
+	cmd.Flags().BoolVar(&prune, "prune", false, "delete resources of the --set that are no longer declared")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without applying it")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "skip the confirmation prompt for deletes")
+
+	return cmd
+}
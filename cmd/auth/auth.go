@@ -26,6 +26,7 @@ func NewAuthCmd(opts *factory.Options) *AuthCmd {
 	cmd.AddCommand(
 		NewLoginCmd(opts),
 		NewLogoutCmd(opts),
+		NewStatusCmd(opts),
 	)
 
 	root.Cmd = cmd
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -12,17 +14,63 @@ import (
 
 // NewLoginCmd creates the login command.
 func NewLoginCmd(opts *factory.Options) *cobra.Command {
-	return &cobra.Command{
+	var (
+		device                bool
+		clientCredentials     bool
+		clientSecret          string
+		clientCredentialsFile string
+		federatedTokenFile    string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Log in to Admiral",
-		Args:  cobra.NoArgs,
+		Long: `Log in to Admiral.
+
+By default a browser is opened to complete the login. Use --device on hosts
+without a browser (SSH sessions, containers): a URL and code are printed to
+enter on any other device.
+
+Use --client-credentials to log in as a service account, e.g. in CI. The
+client ID and secret are read from --client-id/--client-secret, then
+$ADMIRAL_CLIENT_ID/$ADMIRAL_CLIENT_SECRET, then --client-credentials-file
+(a JSON file with client_id and client_secret). Expired tokens are re-minted
+automatically. With the secret-service credential store the secret is stored
+with the session; the file store doesn't write it to disk, so re-minting
+reads $ADMIRAL_CLIENT_SECRET and you must log in again without it.
+
+Use --federated-token-file to exchange an OIDC token issued by a CI provider
+(GitHub Actions, GitLab, Buildkite) for an Admiral token. The file is re-read
+whenever the session expires. Alternatively, set $ADMIRAL_FEDERATED_TOKEN or
+$ADMIRAL_FEDERATED_TOKEN_FILE and every command exchanges the token on
+demand, without a login step.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := internalauth.Login(context.Background(), internalauth.LoginOptions{
-				Issuer:    opts.Issuer,
-				ClientID:  opts.ClientID,
-				Scopes:    opts.Scopes,
-				ConfigDir: opts.ConfigDir,
-			})
+			loginOpts := internalauth.LoginOptions{
+				Issuer:        opts.Issuer,
+				ClientID:      opts.ClientID,
+				Scopes:        opts.Scopes,
+				ConfigDir:     opts.ConfigDir,
+				CredentialKey: opts.CredentialKey(),
+				Out:           cmd.ErrOrStderr(),
+			}
+
+			var err error
+			switch {
+			case clientCredentials:
+				loginOpts.ClientID, loginOpts.ClientSecret, err = resolveClientCredentials(cmd, opts.ClientID, clientSecret, clientCredentialsFile)
+				if err != nil {
+					return err
+				}
+				err = internalauth.ClientCredentialsLogin(context.Background(), loginOpts)
+			case federatedTokenFile != "":
+				loginOpts.FederatedTokenFile = federatedTokenFile
+				err = internalauth.FederatedLogin(context.Background(), loginOpts)
+			case device:
+				err = internalauth.DeviceLogin(context.Background(), loginOpts)
+			default:
+				err = internalauth.Login(context.Background(), loginOpts)
+			}
 			if err != nil {
 				return err
 			}
@@ -31,4 +79,52 @@ func NewLoginCmd(opts *factory.Options) *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&device, "device", false, "log in with a code entered on another device instead of a local browser")
+	cmd.Flags().BoolVar(&clientCredentials, "client-credentials", false, "log in as a service account with a client ID and secret")
+	cmd.Flags().StringVar(&clientSecret, "client-secret", "", "service account client secret (visible in process listings; prefer $ADMIRAL_CLIENT_SECRET)")
+	cmd.Flags().StringVar(&clientCredentialsFile, "client-credentials-file", "", "path to a JSON file with client_id and client_secret")
+	cmd.Flags().StringVar(&federatedTokenFile, "federated-token-file", "", "path to a CI-issued OIDC token to exchange for an Admiral token")
+	cmd.MarkFlagsMutuallyExclusive("device", "client-credentials", "federated-token-file")
+
+	return cmd
+}
+
+// resolveClientCredentials picks the service account client ID and secret
+// from flags, then the environment, then the credentials file.
+func resolveClientCredentials(cmd *cobra.Command, flagClientID, flagSecret, file string) (string, string, error) {
+	var clientID string
+	if cmd.Flags().Changed("client-id") {
+		clientID = flagClientID
+	}
+	secret := flagSecret
+
+	if clientID == "" {
+		clientID = os.Getenv(internalauth.EnvClientID)
+	}
+	if secret == "" {
+		secret = os.Getenv(internalauth.EnvClientSecret)
+	}
+
+	if file != "" && (clientID == "" || secret == "") {
+		f, err := internalauth.ReadClientCredentialsFile(file)
+		if err != nil {
+			return "", "", err
+		}
+		if clientID == "" {
+			clientID = f.ClientID
+		}
+		if secret == "" {
+			secret = f.ClientSecret
+		}
+	}
+
+	if clientID == "" {
+		return "", "", errors.New("no client ID: set --client-id, $ADMIRAL_CLIENT_ID or --client-credentials-file")
+	}
+	if secret == "" {
+		return "", "", errors.New("no client secret: set $ADMIRAL_CLIENT_SECRET, --client-secret or --client-credentials-file")
+	}
+
+	return clientID, secret, nil
 }
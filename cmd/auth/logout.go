@@ -2,32 +2,112 @@ package auth
 
 import (
 	"context"
+	"io"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 
 	internalauth "go.admiral.io/cli/internal/auth"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/kubeconfig"
 	"go.admiral.io/cli/internal/output"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
 )
 
 // NewLogoutCmd creates the logout command.
 func NewLogoutCmd(opts *factory.Options) *cobra.Command {
-	return &cobra.Command{
+	var all bool
+
+	cmd := &cobra.Command{
 		Use:   "logout",
 		Short: "Log out from Admiral",
-		Args:  cobra.NoArgs,
+		Long: `Log out from the current server.
+
+Sessions for other servers are kept. Use --all to log out of every
+stored session.
+
+The cluster tokens cached by 'admiral cluster credential' are revoked
+before the session ends, and removed from the cache. Revocation is
+best-effort: a token that can't be revoked is reported, left active on the
+cluster and kept in the cache, so the next logout tries again.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Cached Kubernetes credentials of the session go with it.
+			server := opts.CredentialKey()
+			if all {
+				server = ""
+			}
+			revokeCachedTokens(cmd.Context(), opts, cmd.ErrOrStderr(), server)
+
 			err := internalauth.Logout(context.Background(), internalauth.LogoutOptions{
-				Issuer:    opts.Issuer,
-				ClientID:  opts.ClientID,
-				ConfigDir: opts.ConfigDir,
+				Issuer:        opts.Issuer,
+				ClientID:      opts.ClientID,
+				ConfigDir:     opts.ConfigDir,
+				CredentialKey: opts.CredentialKey(),
+				All:           all,
 			})
 			if err != nil {
 				return err
 			}
 
+			if all {
+				output.Writeln(cmd.OutOrStdout(), "Successfully logged out of all sessions.")
+				return nil
+			}
+
 			output.Writeln(cmd.OutOrStdout(), "Successfully logged out.")
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "log out of every stored session")
+
+	return cmd
+}
+
+// revokeCachedTokens revokes the cluster tokens of the exec credentials
+// cached for server, or for every server if server is empty, while the
+// sessions needed to revoke them still exist, and removes their cache files.
+// A token that can't be revoked is reported on errOut and its cache file is
+// kept, so logging out always succeeds without losing track of it.
+func revokeCachedTokens(ctx context.Context, opts *factory.Options, errOut io.Writer, server string) {
+	byServer := map[string][]*kubeconfig.CachedCredential{}
+	for _, cached := range kubeconfig.ReadCaches(opts.ConfigDir, server) {
+		if cached.Server == "" || cached.TokenID == "" {
+			// Nothing identifies a token to revoke.
+			removeCache(cached.Path)
+			continue
+		}
+		byServer[cached.Server] = append(byServer[cached.Server], cached)
+	}
+
+	for srv, cached := range byServer {
+		serverOpts := *opts
+		serverOpts.ServerAddr = srv
+		c, err := factory.CreateClient(ctx, &serverOpts)
+		if err != nil {
+			for _, cc := range cached {
+				output.Writef(errOut, "Warning: failed to revoke cluster token %s of cluster %s: %v\n", cc.TokenID, cc.ClusterID, err)
+			}
+			continue
+		}
+		for _, cc := range cached {
+			if _, err := c.Cluster().RevokeClusterToken(ctx, &clusterv1.RevokeClusterTokenRequest{
+				ClusterId: cc.ClusterID,
+				TokenId:   cc.TokenID,
+			}); err != nil {
+				output.Writef(errOut, "Warning: failed to revoke cluster token %s of cluster %s: %v\n", cc.TokenID, cc.ClusterID, err)
+				continue
+			}
+			removeCache(cc.Path)
+		}
+		_ = c.Close()
+	}
+}
+
+// removeCache removes the cache file at path, logging a failure.
+func removeCache(path string) {
+	if err := kubeconfig.RemoveCache(path); err != nil {
+		slog.Debug("failed to remove cached credential", "error", err)
+	}
 }
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/credentials"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+// NewStatusCmd creates the status command.
+func NewStatusCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List stored sessions",
+		Long: `List every stored session with its expiry.
+
+The session for the current server is marked with an asterisk.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := credentials.ListSessions(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			p := output.NewPrinter(opts.OutputFormat)
+			p.Out = cmd.OutOrStdout()
+			return p.PrintObject(sessions, func(w *tabwriter.Writer) {
+				if len(sessions) == 0 {
+					output.Writeln(w, "No stored sessions. Run 'admiral auth login' to log in.")
+					return
+				}
+
+				if opts.OutputFormat == output.FormatWide {
+					output.Writeln(w, "CURRENT\tSERVER\tEXPIRES\tREFRESHABLE\tISSUER\tCLIENT-ID")
+				} else {
+					output.Writeln(w, "CURRENT\tSERVER\tEXPIRES")
+				}
+				for _, s := range sessions {
+					current := ""
+					if s.Key == opts.CredentialKey() {
+						current = "*"
+					}
+
+					if opts.OutputFormat == output.FormatWide {
+						output.Writef(w, "%s\t%s\t%s\t%t\t%s\t%s\n",
+							current,
+							s.Key,
+							output.FormatExpiry(s.Expiry),
+							s.Refreshable,
+							s.Issuer,
+							s.ClientID,
+						)
+					} else {
+						output.Writef(w, "%s\t%s\t%s\n",
+							current,
+							s.Key,
+							output.FormatExpiry(s.Expiry),
+						)
+					}
+				}
+			})
+		},
+	}
+}
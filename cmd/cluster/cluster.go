@@ -33,6 +33,8 @@ func NewClusterCmd(opts *factory.Options) *ClusterCmd {
 		newUpdateCmd(opts),
 		newDeleteCmd(opts),
 		newStatusCmd(opts),
+		newKubeconfigCmd(opts),
+		newCredentialCmd(opts),
 		tokenCmd,
 	)
 
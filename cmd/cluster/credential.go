@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/credentials"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/kubeconfig"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
+)
+
+const (
+	// credentialLifetime is the longest an exec credential is handed out
+	// for before its token is replaced.
+	credentialLifetime = time.Hour
+
+	// defaultCredentialTokenName names the cluster tokens created for exec
+	// credentials, apart from the static tokens of kubeconfigs.
+	defaultCredentialTokenName = "exec-credential"
+)
+
+func newCredentialCmd(opts *factory.Options) *cobra.Command {
+	var tokenName string
+
+	cmd := &cobra.Command{
+		Use:   "credential <cluster>",
+		Short: "Print a Kubernetes exec credential for a cluster",
+		Long: `Print a client.authentication.k8s.io/v1 ExecCredential holding a cluster
+token, for use as a client-go exec credential plugin. 'admiral cluster
+kubeconfig --exec' writes kubeconfigs that run it.
+
+The credential expires after at most an hour. It is cached under the config
+directory and printed again until it is about to expire; then a new token
+named --token-name is created and the previous one revoked.
+
+Every run checks your Admiral login first, so kubectl access ends when you
+log out or your session can't be renewed. Logging out also revokes the
+cached tokens of that server and removes them from the cache.
+
+Admiral can't create cluster tokens that expire, so a token stays valid on
+the cluster until it is revoked. Revocation is best-effort: a token that
+can't be revoked, e.g. because the server is unreachable, is reported with
+its ID and left active. Find such tokens with 'admiral cluster token list'
+and revoke them with 'admiral cluster token revoke'.`,
+		Example: `  # Print a credential
+  admiral cluster credential prod-us-east`,
+		Args: cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterID := args[0]
+
+			// The cache is only used while the Admiral session is valid.
+			if _, err := credentials.ResolveToken(cmd.Context(), opts.ConfigDir, opts.CredentialKey(), opts.Federation()); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			path := kubeconfig.CachePath(opts.ConfigDir, opts.CredentialKey(), clusterID, tokenName)
+			cached := kubeconfig.ReadCache(path)
+			if cached != nil && cached.Fresh(now) {
+				return cached.Credential.Encode(cmd.OutOrStdout())
+			}
+
+			// kubectl may run this in parallel: refresh under the lock, and
+			// use the credential another run cached while waiting for it.
+			if unlock, err := kubeconfig.LockCache(path); err != nil {
+				slog.Debug("refreshing credential without a lock", "error", err)
+			} else {
+				defer unlock()
+				cached = kubeconfig.ReadCache(path)
+				if cached != nil && cached.Fresh(now) {
+					return cached.Credential.Encode(cmd.OutOrStdout())
+				}
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Cluster().CreateClusterToken(cmd.Context(), &clusterv1.CreateClusterTokenRequest{
+				ClusterId: clusterID,
+				Name:      tokenName,
+			})
+			if err != nil {
+				return err
+			}
+
+			expires := now.Add(credentialLifetime)
+			if t := resp.AccessToken.GetExpiresAt(); t != nil && t.AsTime().Before(expires) {
+				expires = t.AsTime()
+			}
+			cred := kubeconfig.NewExecCredential(resp.PlainTextToken, expires)
+
+			if cached != nil && cached.TokenID != "" {
+				revokeReplaced(cmd.Context(), c, cmd.ErrOrStderr(), cached.ClusterID, cached.TokenID)
+			}
+
+			if err := kubeconfig.WriteCache(path, &kubeconfig.CachedCredential{
+				Server:     opts.CredentialKey(),
+				ClusterID:  clusterID,
+				TokenID:    resp.AccessToken.GetId(),
+				Credential: cred,
+			}); err != nil {
+				slog.Debug("failed to cache credential", "error", err)
+			}
+
+			return cred.Encode(cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVar(&tokenName, "token-name", defaultCredentialTokenName, "name of the cluster tokens to create")
+
+	return cmd
+}
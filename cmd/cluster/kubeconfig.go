@@ -0,0 +1,272 @@
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/kubeconfig"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/sdk/client"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
+)
+
+// defaultTokenName names the cluster tokens created for kubeconfigs.
+const defaultTokenName = "kubeconfig"
+
+func newKubeconfigCmd(opts *factory.Options) *cobra.Command {
+	var (
+		tokenName   string
+		merge       string
+		contextName string
+		apiServer   string
+		caFile      string
+		exec        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "kubeconfig <cluster>",
+		Short: "Write a kubeconfig for a cluster",
+		Long: `Write a kubeconfig entry for a cluster: its cluster, user and context, all
+named after --context-name (default admiral-<cluster>).
+
+Admiral doesn't store the Kubernetes API endpoint or CA of a cluster, so
+--api-server and --certificate-authority set them. When merging into a
+kubeconfig that already has the entry, they default to its current values.
+
+The user authenticates with a cluster token named --token-name. The token's
+ID is recorded in the user's admiral.io/cluster-token extension. A new token
+is created, unless the kubeconfig being merged into already holds one for
+the entry and the token with the recorded ID is still active, so merging
+again leaves the file unchanged. A recorded token that is replaced, by a new
+token or by --exec, is revoked. With --exec no token is written: kubectl runs
+'admiral cluster credential' as a client-go exec credential plugin, which
+creates short-lived tokens, named --token-name if given, and follows your
+Admiral login.
+
+Without --merge the kubeconfig is written to stdout. With --merge it is
+added to the given file, replacing only entries of the same name; other
+clusters, users and contexts are kept, as is the current context unless
+none is set.`,
+		Example: `  # Add a context for a cluster to your kubeconfig
+  admiral cluster kubeconfig prod-us-east --api-server https://10.0.0.1:6443 \
+    --certificate-authority ca.crt --merge ~/.kube/config
+
+  # Authenticate through admiral instead of a static token
+  admiral cluster kubeconfig prod-us-east --exec --merge ~/.kube/config
+
+  # Print a standalone kubeconfig
+  admiral cluster kubeconfig prod-us-east --api-server https://10.0.0.1:6443 > prod.kubeconfig`,
+		Args: cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := kubeconfig.New()
+			path := ""
+			if merge != "" {
+				var err error
+				if path, err = expandHome(merge); err != nil {
+					return err
+				}
+				if cfg, err = kubeconfig.Load(path); err != nil {
+					return err
+				}
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Cluster().GetCluster(cmd.Context(), &clusterv1.GetClusterRequest{
+				ClusterId: args[0],
+			})
+			if err != nil {
+				return err
+			}
+			cl := resp.Cluster
+
+			name := contextName
+			if name == "" {
+				name = "admiral-" + cl.Name
+			}
+
+			entry, _ := cfg.Cluster(name)
+			if apiServer != "" {
+				entry.Server = apiServer
+			}
+			if caFile != "" {
+				ca, err := os.ReadFile(caFile) //nolint:gosec // path is chosen by the user
+				if err != nil {
+					return fmt.Errorf("failed to read certificate authority: %w", err)
+				}
+				entry.CertificateAuthorityData = base64.StdEncoding.EncodeToString(ca)
+			}
+			if entry.Server == "" {
+				return fmt.Errorf("--api-server is required; Admiral doesn't store the API endpoint of cluster %s", cl.Name)
+			}
+
+			existing, _ := cfg.User(name)
+			var user kubeconfig.User
+			if exec {
+				user.Exec = execConfig(opts, cl.Name)
+				if cmd.Flags().Changed("token-name") {
+					user.Exec.Args = append(user.Exec.Args, "--token-name", tokenName)
+				}
+			} else {
+				user.Token, user.TokenID, err = clusterToken(cmd.Context(), c, cl.Id, tokenName, existing)
+				if err != nil {
+					return err
+				}
+			}
+			if existing.TokenID != "" && existing.TokenID != user.TokenID {
+				revokeReplaced(cmd.Context(), c, cmd.ErrOrStderr(), cl.Id, existing.TokenID)
+			}
+
+			cfg.SetCluster(name, entry)
+			cfg.SetUser(name, &user)
+			cfg.SetContext(name, &kubeconfig.Context{Cluster: name, User: name})
+
+			if path == "" {
+				cfg.CurrentContext = name
+				return cfg.Encode(cmd.OutOrStdout())
+			}
+
+			if cfg.CurrentContext == "" {
+				cfg.CurrentContext = name
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.ErrOrStderr(), "Context %s written to %s.\n", name, path)
+			if cfg.CurrentContext != name {
+				output.Writef(cmd.ErrOrStderr(), "Switch to it with 'kubectl config use-context %s'.\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tokenName, "token-name", defaultTokenName, "name of the cluster token to create or reuse")
+	cmd.Flags().StringVar(&merge, "merge", "", "kubeconfig file to merge the entry into, e.g. ~/.kube/config")
+	cmd.Flags().StringVar(&contextName, "context-name", "", "name of the context, cluster and user entries (default admiral-<cluster>)")
+	cmd.Flags().StringVar(&apiServer, "api-server", "", "URL of the cluster's Kubernetes API server")
+	cmd.Flags().StringVar(&caFile, "certificate-authority", "", "path to the PEM CA of the API server, embedded in the kubeconfig")
+	cmd.Flags().BoolVar(&exec, "exec", false, "authenticate with 'admiral cluster credential' instead of a static token")
+
+	return cmd
+}
+
+// clusterToken returns the token of existing if the cluster token whose ID
+// it records is still active, or creates a new token called name. It
+// returns the token and its ID.
+func clusterToken(ctx context.Context, c client.AdmiralClient, clusterID, name string, existing *kubeconfig.User) (string, string, error) {
+	if existing.Token != "" && existing.TokenID != "" {
+		t, err := activeToken(ctx, c, clusterID, existing.TokenID)
+		if err != nil {
+			return "", "", err
+		}
+		if t != nil {
+			return existing.Token, existing.TokenID, nil
+		}
+	}
+
+	resp, err := c.Cluster().CreateClusterToken(ctx, &clusterv1.CreateClusterTokenRequest{
+		ClusterId: clusterID,
+		Name:      name,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create cluster token: %w", err)
+	}
+	return resp.PlainTextToken, resp.GetAccessToken().GetId(), nil
+}
+
+// revokeReplaced revokes the cluster token with ID id, which a new
+// credential replaces. Revocation is best-effort: a failure is reported on
+// errOut with the token's ID, so it can be revoked by hand.
+func revokeReplaced(ctx context.Context, c client.AdmiralClient, errOut io.Writer, clusterID, id string) {
+	_, err := c.Cluster().RevokeClusterToken(ctx, &clusterv1.RevokeClusterTokenRequest{
+		ClusterId: clusterID,
+		TokenId:   id,
+	})
+	if err != nil {
+		output.Writef(errOut, "Warning: failed to revoke cluster token %s of cluster %s: %v\n", id, clusterID, err)
+	}
+}
+
+// activeToken returns the active, unexpired token of the cluster with ID
+// id, or nil if there is none.
+func activeToken(ctx context.Context, c client.AdmiralClient, clusterID, id string) (*clusterv1.AccessToken, error) {
+	fetch := func(ctx context.Context, pageSize int32, pageToken string) ([]*clusterv1.AccessToken, string, error) {
+		resp, err := c.Cluster().ListClusterTokens(ctx, &clusterv1.ListClusterTokensRequest{
+			ClusterId: clusterID,
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list cluster tokens: %w", err)
+		}
+		return resp.AccessTokens, resp.NextPageToken, nil
+	}
+
+	var found *clusterv1.AccessToken
+	_, err := cmdutil.Each(ctx, &cmdutil.Pager{PageSize: 100, All: true}, fetch, func(tokens []*clusterv1.AccessToken) error {
+		for _, t := range tokens {
+			if found == nil && t.Id == id && tokenActive(t, time.Now()) {
+				found = t
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// tokenActive reports whether t is active and unexpired at now.
+func tokenActive(t *clusterv1.AccessToken, now time.Time) bool {
+	if t.Status != clusterv1.AccessTokenStatus_ACCESS_TOKEN_STATUS_ACTIVE {
+		return false
+	}
+	return t.ExpiresAt == nil || t.ExpiresAt.AsTime().After(now)
+}
+
+// execConfig returns the exec credential plugin that runs 'admiral cluster
+// credential' against the same server, profile and connection settings.
+func execConfig(opts *factory.Options, cluster string) *kubeconfig.ExecConfig {
+	args := []string{"cluster", "credential", cluster, "--server", opts.ServerAddr}
+	if opts.Profile != "" {
+		args = append(args, "--profile", opts.Profile)
+	}
+	if opts.PlainText {
+		args = append(args, "--plaintext")
+	}
+	if opts.Insecure {
+		args = append(args, "--insecure")
+	}
+	return &kubeconfig.ExecConfig{
+		APIVersion:      kubeconfig.ExecAPIVersion,
+		Command:         "admiral",
+		Args:            args,
+		InteractiveMode: "Never",
+		InstallHint:     "Install the Admiral CLI and log in with 'admiral auth login'.",
+	}
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -1,9 +1,11 @@
 package cluster
 
 import (
+	"context"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
@@ -13,20 +15,47 @@ import (
 
 func newListCmd(opts *factory.Options) *cobra.Command {
 	var (
-		pageSize  int32
-		pageToken string
+		pager     cmdutil.Pager
 		labelStrs []string
+		selector  string
+		rawFilter string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List clusters",
-		Args:  cobra.NoArgs,
+		Long: `List clusters visible to the current user.
+
+One page of results is returned by default, and the token for the next
+page is printed to stderr. Use --all to fetch every page, or --limit to
+fetch pages until that many clusters are returned. Table output is printed
+a page at a time; json and yaml output is a single list of every cluster.
+
+Filter with --label (exact key=value matches), --selector (kubectl-style
+label selectors) or --filter (a raw API filter expression). When several
+are given, a cluster must match all of them.`,
+		Example: `  # List the first page of clusters
+  admiral cluster list
+
+  # List every cluster as one JSON document
+  admiral cluster list --all -o json
+
+  # List at most 200 clusters labelled env=production
+  admiral cluster list --label env=production --limit 200
+
+  # List production and staging clusters that aren't being retired
+  admiral cluster list -l 'env in (production,staging),!retiring'`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			filter, err := cmdutil.BuildLabelFilter(labelStrs)
+			labelFilter, err := cmdutil.BuildLabelFilter(labelStrs)
 			if err != nil {
 				return err
 			}
+			selectorFilter, err := cmdutil.ParseSelector(selector)
+			if err != nil {
+				return err
+			}
+			filter := cmdutil.JoinFilters(labelFilter, selectorFilter, rawFilter)
 
 			c, err := factory.CreateClient(cmd.Context(), opts)
 			if err != nil {
@@ -34,20 +63,29 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 			}
 			defer c.Close() //nolint:errcheck // best-effort cleanup
 
-			resp, err := c.Cluster().ListClusters(cmd.Context(), &clusterv1.ListClustersRequest{
-				PageSize:  pageSize,
-				PageToken: pageToken,
-				Filter:    filter,
-			})
-			if err != nil {
-				return err
+			fetch := func(ctx context.Context, pageSize int32, pageToken string) ([]*clusterv1.Cluster, string, error) {
+				resp, err := c.Cluster().ListClusters(ctx, &clusterv1.ListClustersRequest{
+					PageSize:  pageSize,
+					PageToken: pageToken,
+					Filter:    filter,
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.Clusters, resp.NextPageToken, nil
 			}
 
-			p := output.NewPrinter(opts.OutputFormat)
-			if err := p.PrintResource(resp, func(w *tabwriter.Writer) {
-				if opts.OutputFormat == output.FormatWide {
-					output.Writeln(w, "NAME\tHEALTH\tAGE\tCLUSTER-UID\tLABELS\tCREATED\tUPDATED")
-					for _, cl := range resp.Clusters {
+			wide := opts.OutputFormat == output.FormatWide
+			return cmdutil.PrintList(cmd, opts.OutputFormat, &pager, fetch, cmdutil.ListOutput[*clusterv1.Cluster]{
+				Header: func(w *tabwriter.Writer) {
+					if wide {
+						output.Writeln(w, "NAME\tHEALTH\tAGE\tCLUSTER-UID\tLABELS\tCREATED\tUPDATED")
+					} else {
+						output.Writeln(w, "NAME\tHEALTH\tAGE")
+					}
+				},
+				Row: func(w *tabwriter.Writer, cl *clusterv1.Cluster) {
+					if wide {
 						output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 							cl.Name,
 							output.FormatEnum(cl.HealthStatus.String(), "CLUSTER_HEALTH_STATUS_"),
@@ -57,32 +95,25 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 							output.FormatTimestamp(cl.CreatedAt),
 							output.FormatTimestamp(cl.UpdatedAt),
 						)
-					}
-				} else {
-					output.Writeln(w, "NAME\tHEALTH\tAGE")
-					for _, cl := range resp.Clusters {
+					} else {
 						output.Writef(w, "%s\t%s\t%s\n",
 							cl.Name,
 							output.FormatEnum(cl.HealthStatus.String(), "CLUSTER_HEALTH_STATUS_"),
 							output.FormatAge(cl.CreatedAt),
 						)
 					}
-				}
-			}); err != nil {
-				return err
-			}
-
-			if resp.NextPageToken != "" {
-				output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", resp.NextPageToken)
-			}
-
-			return nil
+				},
+				Merge: func(items []*clusterv1.Cluster) proto.Message {
+					return &clusterv1.ListClustersResponse{Clusters: items}
+				},
+			})
 		},
 	}
 
-	cmd.Flags().Int32Var(&pageSize, "page-size", 50, "number of clusters to return per page")
-	cmd.Flags().StringVar(&pageToken, "page-token", "", "token for the next page of results")
+	cmdutil.AddPagerFlags(cmd, &pager, "clusters")
 	cmdutil.AddLabelFlag(cmd, &labelStrs, "filter by label (key=value, can be repeated)")
+	cmdutil.AddSelectorFlag(cmd, &selector)
+	cmd.Flags().StringVar(&rawFilter, "filter", "", "raw API filter expression, e.g. 'labels.env = \"prod\" OR labels.env = \"staging\"'")
 
 	return cmd
 }
@@ -1,9 +1,11 @@
 package cluster
 
 import (
+	"context"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
@@ -12,15 +14,16 @@ import (
 )
 
 func newTokenListCmd(opts *factory.Options) *cobra.Command {
-	var (
-		pageSize  int32
-		pageToken string
-	)
+	var pager cmdutil.Pager
 
 	cmd := &cobra.Command{
 		Use:   "list <cluster>",
 		Short: "List cluster tokens",
-		Args:  cmdutil.ExactArgs(1),
+		Long: `List the access tokens of a cluster.
+
+One page of results is returned by default. Use --all to fetch every page,
+or --limit to fetch pages until that many tokens are returned.`,
+		Args: cmdutil.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clusterID := args[0]
 
@@ -30,40 +33,38 @@ func newTokenListCmd(opts *factory.Options) *cobra.Command {
 			}
 			defer c.Close() //nolint:errcheck // best-effort cleanup
 
-			resp, err := c.Cluster().ListClusterTokens(cmd.Context(), &clusterv1.ListClusterTokensRequest{
-				ClusterId: clusterID,
-				PageSize:  pageSize,
-				PageToken: pageToken,
-			})
-			if err != nil {
-				return err
+			fetch := func(ctx context.Context, pageSize int32, pageToken string) ([]*clusterv1.AccessToken, string, error) {
+				resp, err := c.Cluster().ListClusterTokens(ctx, &clusterv1.ListClusterTokensRequest{
+					ClusterId: clusterID,
+					PageSize:  pageSize,
+					PageToken: pageToken,
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				return resp.AccessTokens, resp.NextPageToken, nil
 			}
 
-			p := output.NewPrinter(opts.OutputFormat)
-			if err := p.PrintResource(resp, func(w *tabwriter.Writer) {
-				output.Writeln(w, "ID\tNAME\tSTATUS\tCREATED")
-				for _, t := range resp.AccessTokens {
+			return cmdutil.PrintList(cmd, opts.OutputFormat, &pager, fetch, cmdutil.ListOutput[*clusterv1.AccessToken]{
+				Header: func(w *tabwriter.Writer) {
+					output.Writeln(w, "ID\tNAME\tSTATUS\tCREATED")
+				},
+				Row: func(w *tabwriter.Writer, t *clusterv1.AccessToken) {
 					output.Writef(w, "%s\t%s\t%s\t%s\n",
 						t.Id,
 						t.Name,
 						output.FormatEnum(t.Status.String(), "ACCESS_TOKEN_STATUS_"),
 						output.FormatTimestamp(t.CreatedAt),
 					)
-				}
-			}); err != nil {
-				return err
-			}
-
-			if resp.NextPageToken != "" {
-				output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", resp.NextPageToken)
-			}
-
-			return nil
+				},
+				Merge: func(items []*clusterv1.AccessToken) proto.Message {
+					return &clusterv1.ListClusterTokensResponse{AccessTokens: items}
+				},
+			})
 		},
 	}
 
-	cmd.Flags().Int32Var(&pageSize, "page-size", 50, "number of tokens to return per page")
-	cmd.Flags().StringVar(&pageToken, "page-token", "", "token for the next page of results")
+	cmdutil.AddPagerFlags(cmd, &pager, "tokens")
 
 	return cmd
 }
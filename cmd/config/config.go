@@ -0,0 +1,47 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/factory"
+)
+
+// ConfigCmd is the parent command for CLI configuration operations.
+type ConfigCmd struct {
+	Cmd *cobra.Command
+}
+
+// NewConfigCmd creates the config command tree.
+func NewConfigCmd(opts *factory.Options) *ConfigCmd {
+	root := &ConfigCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage CLI configuration",
+		Long: `Manage CLI configuration stored in the config directory.
+
+Profiles let you switch between Admiral control planes (e.g. prod, staging,
+a local dev server) without repeating connection flags on every command.
+
+The active profile is resolved in this order:
+  1. The --profile flag
+  2. The ADMIRAL_PROFILE environment variable
+  3. The current profile set via 'admiral config profile use'
+
+Flags passed on the command line always override profile values.
+
+Views save custom-columns specs by name, so 'admiral cluster list -o view=ops'
+prints the same columns for everyone sharing the config file.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newProfileCmd(opts),
+		newViewCmd(opts),
+	)
+
+	root.Cmd = cmd
+	return root
+}
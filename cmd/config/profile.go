@@ -0,0 +1,25 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/factory"
+)
+
+func newProfileCmd(opts *factory.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Short:   "Manage named server profiles",
+		Aliases: []string{"profiles"},
+		Args:    cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newProfileListCmd(opts),
+		newProfileAddCmd(opts),
+		newProfileRemoveCmd(opts),
+		newProfileUseCmd(opts),
+	)
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newProfileAddCmd(opts *factory.Options) *cobra.Command {
+	var (
+		defaultOutput string
+		use           bool
+		overwrite     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a profile",
+		Long: `Add a named profile to the config file.
+
+The profile captures the connection flags passed on the command line
+(--server, --plaintext, --insecure, --issuer, --client-id, --scopes).
+Flags that are not passed are left unset and fall back to their defaults.`,
+		Example: `  # Add a staging profile
+  admiral config profile add staging --server api.staging.admiral.io:443
+
+  # Add a local dev profile and make it active
+  admiral config profile add dev --server localhost:8080 --plaintext \
+    --issuer http://localhost:5556 --client-id admiral-dev --use
+
+  # Default to JSON output for a profile
+  admiral config profile add ci --server api.admiral.io:443 --default-output json`,
+		Args: cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			if _, exists := cfg.Profiles[name]; exists && !overwrite {
+				return fmt.Errorf("profile %q already exists; use --overwrite to replace it", name)
+			}
+
+			if defaultOutput != "" {
+				if _, err := output.ParseFormat(defaultOutput); err != nil {
+					return err
+				}
+			}
+
+			flags := cmd.Flags()
+			prof := &internalconfig.Profile{Output: defaultOutput}
+			if flags.Changed("server") {
+				prof.Server = opts.ServerAddr
+			}
+			if flags.Changed("plaintext") {
+				prof.PlainText = opts.PlainText
+			}
+			if flags.Changed("insecure") {
+				prof.Insecure = opts.Insecure
+			}
+			if flags.Changed("issuer") {
+				prof.Issuer = opts.Issuer
+			}
+			if flags.Changed("client-id") {
+				prof.ClientID = opts.ClientID
+			}
+			if flags.Changed("scopes") {
+				prof.Scopes = opts.Scopes
+			}
+
+			cfg.Profiles[name] = prof
+			if use {
+				cfg.CurrentProfile = name
+			}
+
+			if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.OutOrStdout(), "Profile %q added.\n", name)
+			if use {
+				output.Writef(cmd.OutOrStdout(), "Active profile set to %q.\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&defaultOutput, "default-output", "", "default output format for this profile: "+output.FormatHelp)
+	cmd.Flags().BoolVar(&use, "use", false, "make this the active profile")
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "replace an existing profile with the same name")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newProfileListCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			p := output.NewPrinter(opts.OutputFormat)
+			p.Out = cmd.OutOrStdout()
+			return p.PrintObject(cfg, func(w *tabwriter.Writer) {
+				if opts.OutputFormat == output.FormatWide {
+					output.Writeln(w, "CURRENT\tNAME\tSERVER\tISSUER\tCLIENT-ID\tOUTPUT")
+				} else {
+					output.Writeln(w, "CURRENT\tNAME\tSERVER")
+				}
+				for _, name := range cfg.ProfileNames() {
+					prof := cfg.Profiles[name]
+					if prof == nil {
+						prof = &internalconfig.Profile{}
+					}
+
+					current := ""
+					if name == opts.Profile {
+						current = "*"
+					}
+
+					if opts.OutputFormat == output.FormatWide {
+						output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+							current,
+							name,
+							valueOrDefault(prof.Server),
+							valueOrDefault(prof.Issuer),
+							valueOrDefault(prof.ClientID),
+							valueOrDefault(prof.Output),
+						)
+					} else {
+						output.Writef(w, "%s\t%s\t%s\n",
+							current,
+							name,
+							valueOrDefault(prof.Server),
+						)
+					}
+				}
+			})
+		},
+	}
+}
+
+// valueOrDefault returns s, or a placeholder indicating the flag default
+// applies when the profile leaves the field unset.
+func valueOrDefault(s string) string {
+	if s == "" {
+		return "<default>"
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newProfileRemoveCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <name>",
+		Short:   "Remove a profile",
+		Aliases: []string{"rm", "delete"},
+		Args:    cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			if _, exists := cfg.Profiles[name]; !exists {
+				return fmt.Errorf("profile %q not found", name)
+			}
+
+			delete(cfg.Profiles, name)
+			if cfg.CurrentProfile == name {
+				cfg.CurrentProfile = ""
+			}
+
+			if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.OutOrStdout(), "Profile %q removed.\n", name)
+			return nil
+		},
+	}
+}
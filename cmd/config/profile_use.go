@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newProfileUseCmd(opts *factory.Options) *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "Set the active profile",
+		Long: `Set the profile used when neither --profile nor ADMIRAL_PROFILE is set.
+
+  admiral config profile use staging   Set the active profile
+  admiral config profile use           Show the active profile
+  admiral config profile use --clear   Clear the active profile`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			if clear {
+				cfg.CurrentProfile = ""
+				if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+					return err
+				}
+				output.Writef(cmd.OutOrStdout(), "Active profile cleared.\n")
+				return nil
+			}
+
+			if len(args) == 1 {
+				name := args[0]
+				if _, exists := cfg.Profiles[name]; !exists {
+					return fmt.Errorf("profile %q not found; run 'admiral config profile list' to see available profiles", name)
+				}
+
+				cfg.CurrentProfile = name
+				if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+					return err
+				}
+				output.Writef(cmd.OutOrStdout(), "Active profile set to %q.\n", name)
+				return nil
+			}
+
+			// No args, no --clear: show the effective profile.
+			if opts.Profile == "" {
+				output.Writef(cmd.OutOrStdout(), "No active profile. Use 'admiral config profile use <name>' to set one.\n")
+				return nil
+			}
+
+			output.Writef(cmd.OutOrStdout(), "%s\n", opts.Profile)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "clear the active profile")
+
+	return cmd
+}
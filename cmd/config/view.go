@@ -0,0 +1,31 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/factory"
+)
+
+func newViewCmd(opts *factory.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Manage named custom-columns views",
+		Long: `Manage named custom-columns views.
+
+A view saves a custom-columns spec under a name for one resource type, so
+'-o view=NAME' prints the same columns for everyone sharing the config file.
+The resource type is the command group a command belongs to, e.g. "cluster"
+for 'admiral cluster list' and 'admiral cluster get', or "cluster-token" for
+'admiral cluster token list'.`,
+		Aliases: []string{"views"},
+		Args:    cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newViewListCmd(opts),
+		newViewSetCmd(opts),
+		newViewRemoveCmd(opts),
+	)
+
+	return cmd
+}
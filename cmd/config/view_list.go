@@ -0,0 +1,43 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newViewListCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List views",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			views := cfg.Views
+			if views == nil {
+				views = map[string]map[string]string{}
+			}
+
+			p := output.NewPrinter(opts.OutputFormat)
+			p.Out = cmd.OutOrStdout()
+			return p.PrintObject(views, func(w *tabwriter.Writer) {
+				output.Writeln(w, "RESOURCE\tNAME\tCOLUMNS")
+				for _, resource := range slices.Sorted(maps.Keys(views)) {
+					for _, name := range slices.Sorted(maps.Keys(views[resource])) {
+						output.Writef(w, "%s\t%s\t%s\n", resource, name, views[resource][name])
+					}
+				}
+			})
+		},
+	}
+}
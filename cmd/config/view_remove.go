@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newViewRemoveCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <resource> <name>",
+		Short:   "Remove a view",
+		Aliases: []string{"rm", "delete"},
+		Args:    cmdutil.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource, name := args[0], args[1]
+
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			if _, exists := cfg.Views[resource][name]; !exists {
+				return fmt.Errorf("view %q not found for %s", name, resource)
+			}
+
+			delete(cfg.Views[resource], name)
+			if len(cfg.Views[resource]) == 0 {
+				delete(cfg.Views, resource)
+			}
+
+			if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.OutOrStdout(), "View %q removed from %s.\n", name, resource)
+			return nil
+		},
+	}
+}
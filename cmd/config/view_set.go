@@ -0,0 +1,54 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	internalconfig "go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newViewSetCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <resource> <name> <spec>",
+		Short: "Add or replace a view",
+		Long: `Add or replace a named view for a resource type.
+
+The spec uses the -o custom-columns syntax: comma-separated HEADER:PATH
+columns, where PATH is a JSONPath into the resource as printed by -o json.`,
+		Example: `  # Save an "ops" view for clusters
+  admiral config view set cluster ops NAME:.name,UID:.clusterUid,TEAM:.labels.team
+
+  # Use it
+  admiral cluster list -o view=ops`,
+		Args: cmdutil.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource, name, spec := args[0], args[1], args[2]
+
+			if _, err := output.ParseCustomColumns(spec); err != nil {
+				return err
+			}
+
+			cfg, err := internalconfig.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			if cfg.Views == nil {
+				cfg.Views = map[string]map[string]string{}
+			}
+			if cfg.Views[resource] == nil {
+				cfg.Views[resource] = map[string]string{}
+			}
+			cfg.Views[resource][name] = spec
+
+			if err := internalconfig.Save(opts.ConfigDir, cfg); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.OutOrStdout(), "View %q saved for %s.\n", name, resource)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/manifest"
+	"go.admiral.io/cli/internal/output"
+)
+
+func newDiffCmd(opts *factory.Options) *cobra.Command {
+	var (
+		files []string
+		set   string
+		prune bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff -f PATH",
+		Short: "Compare manifests with live resources",
+		Long: `Compare manifests with the live resources they declare, without changing
+anything.
+
+Manifests are read and compared the same way as 'admiral apply', which
+--set and --prune mirror. Fields are named as in 'get' output, e.g.
+promotion_order. Sensitive values can't be read back from the API, so they
+are masked on both sides and not compared. The environment variables that
+valueFrom reads need not be set: a variable that doesn't exist yet shows up
+with a masked value.
+
+The table output is a unified diff per resource, from its live state to its
+manifest, colored when writing to a terminal. -o json|yaml prints the plan
+apply would make, with a JSON Patch (RFC 6902) per resource.
+
+Like 'git diff --exit-code', the command exits with status 1 when live
+resources differ from their manifests and 0 when they match.`,
+		Example: `  # Show drift in a directory of manifests
+  admiral diff -f platform/
+
+  # Print the changes as JSON, e.g. for a pull request comment
+  admiral diff -f platform/ -o json
+
+  # Include resources of the "platform" set that apply --prune would delete
+  admiral diff -f platform/ --set platform --prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(files) == 0 {
+				_ = cmd.Help()
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return fmt.Errorf("-f is required")
+			}
+			if prune && set == "" {
+				return fmt.Errorf("--prune requires --set")
+			}
+
+			manifests, err := manifest.Load(cmd.InOrStdin(), files)
+			if err != nil {
+				return err
+			}
+			if err := manifest.ResolveValues(manifests, os.LookupEnv); err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			live, err := manifest.FetchLive(cmd.Context(), c, manifests, set)
+			if err != nil {
+				return err
+			}
+
+			plan, err := manifest.NewPlan(manifests, live, manifest.Options{Set: set, Prune: prune, Redact: true})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if opts.OutputFormat.IsTable() {
+				if plan.Empty() {
+					output.Writef(out, "No differences. %d unchanged.\n", plan.Count(manifest.ActionUnchanged))
+				}
+				manifest.WriteDiff(out, plan, output.ColorEnabled(out))
+			} else {
+				p := output.NewPrinter(opts.OutputFormat)
+				p.Out = out
+				if err := p.PrintObject(plan, nil); err != nil {
+					return err
+				}
+			}
+
+			if !plan.Empty() {
+				return &cmdutil.ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&files, "filename", "f", nil, "manifest file or directory, or - for stdin (repeatable)")
+	cmd.Flags().StringVar(&set, "set", "", "name of the manifest set, as given to apply")
+	cmd.Flags().BoolVar(&prune, "prune", false, "include resources of the --set that apply --prune would delete")
+
+	return cmd
+}
@@ -2,13 +2,16 @@ package env
 
 import (
 	"fmt"
-	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newCreateCmd(opts *factory.Options) *cobra.Command {
@@ -19,6 +22,7 @@ func newCreateCmd(opts *factory.Options) *cobra.Command {
 		parent         string
 		sourceRef      string
 		ttl            time.Duration
+		labelStrs      []string
 	)
 
 	cmd := &cobra.Command{
@@ -51,34 +55,45 @@ func newCreateCmd(opts *factory.Options) *cobra.Command {
 				return fmt.Errorf("--ttl can only be used with --lifecycle ephemeral")
 			}
 
-			flags := map[string]any{
-				"cluster": cluster,
-			}
-			if promotionOrder != 0 {
-				flags["promotion-order"] = promotionOrder
-			}
-			if lifecycle != "" {
-				flags["lifecycle"] = lifecycle
+			lc, err := parseLifecycle(lifecycle)
+			if err != nil {
+				return err
 			}
-			if parent != "" {
-				flags["parent"] = parent
+
+			labels, err := cmdutil.ParseLabels(labelStrs)
+			if err != nil {
+				return err
 			}
-			if sourceRef != "" {
-				flags["source-ref"] = sourceRef
+
+			req := &environmentv1.CreateEnvironmentRequest{
+				ApplicationId:  app,
+				Name:           name,
+				Cluster:        cluster,
+				PromotionOrder: promotionOrder,
+				Lifecycle:      lc,
+				Parent:         parent,
+				SourceRef:      sourceRef,
+				Labels:         labels,
 			}
 			if ttl > 0 {
-				flags["ttl"] = ttl.String()
+				req.Ttl = durationpb.New(ttl)
 			}
 
-			stub := cmdutil.StubResult{
-				Command:     "env create",
-				App:         app,
-				Environment: name,
-				Flags:       flags,
-				Status:      cmdutil.StubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Environment().CreateEnvironment(cmd.Context(), req)
+			if err != nil {
+				return err
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(resp, func(w *tabwriter.Writer) {
+				printEnvironmentRow(w, resp.Environment)
+			})
 		},
 	}
 
@@ -88,6 +103,7 @@ func newCreateCmd(opts *factory.Options) *cobra.Command {
 	cmd.Flags().StringVar(&parent, "parent", "", "parent environment name")
 	cmd.Flags().StringVar(&sourceRef, "source-ref", "", "source reference (branch, tag, or commit)")
 	cmd.Flags().DurationVar(&ttl, "ttl", 0, "time-to-live for ephemeral environments (e.g. 24h)")
+	cmdutil.AddLabelFlag(cmd, &labelStrs, "set a label (key=value, repeatable)")
 
 	return cmd
 }
@@ -2,12 +2,14 @@ package env
 
 import (
 	"fmt"
-	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newDeleteCmd(opts *factory.Options) *cobra.Command {
@@ -32,17 +34,27 @@ Requires --confirm to prevent accidental deletion.`,
 			}
 
 			if !confirm {
-				return fmt.Errorf("--confirm is required to delete an environment")
+				return fmt.Errorf("use --confirm to delete environment %s", slug)
 			}
 
-			stub := cmdutil.StubResult{
-				Command:     "env delete",
-				App:         app,
-				Environment: slug,
-				Status:      cmdutil.StubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Environment().DeleteEnvironment(cmd.Context(), &environmentv1.DeleteEnvironmentRequest{
+				ApplicationId: app,
+				EnvironmentId: slug,
+			})
+			if err != nil {
+				return err
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(resp, func(w *tabwriter.Writer) {
+				output.Writef(w, "Environment %s deleted\n", slug)
+			})
 		},
 	}
 
@@ -2,11 +2,15 @@ package env
 
 import (
 	"fmt"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 // EnvCmd is the parent command for environment operations.
@@ -62,3 +66,47 @@ func resolveAppForEnv(configDir string) (string, error) {
 	}
 	return props.App, nil
 }
+
+// parseLifecycle converts a --lifecycle value to its API enum. An empty
+// value leaves the lifecycle to the server default.
+func parseLifecycle(s string) (environmentv1.EnvironmentLifecycle, error) {
+	switch s {
+	case "":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_UNSPECIFIED, nil
+	case "permanent":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_PERMANENT, nil
+	case "ephemeral":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_EPHEMERAL, nil
+	default:
+		return 0, fmt.Errorf("invalid lifecycle %q: must be permanent or ephemeral", s)
+	}
+}
+
+// formatTTL returns a human-readable TTL, or "<none>" if unset.
+func formatTTL(ttl *durationpb.Duration) string {
+	if ttl == nil {
+		return "<none>"
+	}
+	return ttl.AsDuration().String()
+}
+
+// formatOptional returns s, or "<none>" if it is empty.
+func formatOptional(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// printEnvironmentRow writes the header and a single row for env, as shown
+// after create and update.
+func printEnvironmentRow(w *tabwriter.Writer, env *environmentv1.Environment) {
+	output.Writeln(w, "NAME\tCLUSTER\tLIFECYCLE\tPROMOTION\tAGE")
+	output.Writef(w, "%s\t%s\t%s\t%d\t%s\n",
+		env.Name,
+		env.Cluster,
+		output.FormatEnum(env.Lifecycle.String(), "ENVIRONMENT_LIFECYCLE_"),
+		env.PromotionOrder,
+		output.FormatAge(env.CreatedAt),
+	)
+}
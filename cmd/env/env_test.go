@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func TestResolveAppForEnv(t *testing.T) {
@@ -41,3 +43,28 @@ func writeProperties(t *testing.T, dir, app string) {
 	require.NoError(t, err)
 	require.NoError(t, os.WriteFile(filepath.Join(dir, "properties.json"), data, 0600))
 }
+
+func TestParseLifecycle(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    environmentv1.EnvironmentLifecycle
+		wantErr string
+	}{
+		{in: "", want: environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_UNSPECIFIED},
+		{in: "permanent", want: environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_PERMANENT},
+		{in: "ephemeral", want: environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_EPHEMERAL},
+		{in: "temporary", wantErr: "invalid lifecycle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseLifecycle(tt.in)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
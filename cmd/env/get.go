@@ -1,12 +1,14 @@
 package env
 
 import (
-	"os"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newGetCmd(opts *factory.Options) *cobra.Command {
@@ -26,14 +28,43 @@ func newGetCmd(opts *factory.Options) *cobra.Command {
 				return err
 			}
 
-			stub := cmdutil.StubResult{
-				Command:     "env get",
-				App:         app,
-				Environment: slug,
-				Status:      cmdutil.StubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Environment().GetEnvironment(cmd.Context(), &environmentv1.GetEnvironmentRequest{
+				ApplicationId: app,
+				EnvironmentId: slug,
+			})
+			if err != nil {
+				return err
+			}
+
+			env := resp.Environment
+			p := output.NewPrinter(opts.OutputFormat)
+
+			sections := []output.Section{
+				{
+					Details: []output.Detail{
+						{Key: "Name", Value: env.Name},
+						{Key: "Application", Value: env.ApplicationId},
+						{Key: "Cluster", Value: env.Cluster},
+						{Key: "Lifecycle", Value: output.FormatEnum(env.Lifecycle.String(), "ENVIRONMENT_LIFECYCLE_")},
+						{Key: "Promotion Order", Value: fmt.Sprintf("%d", env.PromotionOrder)},
+						{Key: "Parent", Value: formatOptional(env.Parent)},
+						{Key: "Source Ref", Value: formatOptional(env.SourceRef)},
+						{Key: "TTL", Value: formatTTL(env.Ttl)},
+						{Key: "Labels", Value: output.FormatLabels(env.Labels)},
+						{Key: "Created", Value: output.FormatTimestamp(env.CreatedAt)},
+						{Key: "Updated", Value: output.FormatTimestamp(env.UpdatedAt)},
+						{Key: "Age", Value: output.FormatAge(env.CreatedAt)},
+					},
+				},
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			return p.PrintDetail(resp, sections)
 		},
 	}
 
@@ -1,18 +1,22 @@
 package env
 
 import (
-	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newListCmd(opts *factory.Options) *cobra.Command {
 	var (
 		pageSize  int32
 		pageToken string
+		labelStrs []string
+		selector  string
 	)
 
 	cmd := &cobra.Command{
@@ -23,6 +27,12 @@ func newListCmd(opts *factory.Options) *cobra.Command {
   admiral use billing-api
   admiral env list
 
+  # List with label filter
+  admiral env list --label tier=frontend
+
+  # List with a label selector
+  admiral env list -l 'tier notin (batch),!deprecated'
+
   # Paginated listing
   admiral env list --page-size 10`,
 		Args: cobra.NoArgs,
@@ -32,27 +42,79 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 				return err
 			}
 
-			flags := map[string]any{}
-			if pageSize > 0 {
-				flags["page-size"] = pageSize
+			labelFilter, err := cmdutil.BuildLabelFilter(labelStrs)
+			if err != nil {
+				return err
 			}
-			if pageToken != "" {
-				flags["page-token"] = pageToken
+			selectorFilter, err := cmdutil.ParseSelector(selector)
+			if err != nil {
+				return err
+			}
+			filter := cmdutil.JoinFilters(labelFilter, selectorFilter)
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Environment().ListEnvironments(cmd.Context(), &environmentv1.ListEnvironmentsRequest{
+				ApplicationId: app,
+				PageSize:      pageSize,
+				PageToken:     pageToken,
+				Filter:        filter,
+			})
+			if err != nil {
+				return err
+			}
+
+			p := output.NewPrinter(opts.OutputFormat)
+			if err := p.PrintResource(resp, func(w *tabwriter.Writer) {
+				if opts.OutputFormat == output.FormatWide {
+					output.Writeln(w, "NAME\tCLUSTER\tLIFECYCLE\tPROMOTION\tAGE\tPARENT\tSOURCE-REF\tTTL\tLABELS\tCREATED\tUPDATED")
+					for _, env := range resp.Environments {
+						output.Writef(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+							env.Name,
+							env.Cluster,
+							output.FormatEnum(env.Lifecycle.String(), "ENVIRONMENT_LIFECYCLE_"),
+							env.PromotionOrder,
+							output.FormatAge(env.CreatedAt),
+							formatOptional(env.Parent),
+							formatOptional(env.SourceRef),
+							formatTTL(env.Ttl),
+							output.FormatLabels(env.Labels),
+							output.FormatTimestamp(env.CreatedAt),
+							output.FormatTimestamp(env.UpdatedAt),
+						)
+					}
+				} else {
+					output.Writeln(w, "NAME\tCLUSTER\tLIFECYCLE\tPROMOTION\tAGE")
+					for _, env := range resp.Environments {
+						output.Writef(w, "%s\t%s\t%s\t%d\t%s\n",
+							env.Name,
+							env.Cluster,
+							output.FormatEnum(env.Lifecycle.String(), "ENVIRONMENT_LIFECYCLE_"),
+							env.PromotionOrder,
+							output.FormatAge(env.CreatedAt),
+						)
+					}
+				}
+			}); err != nil {
+				return err
 			}
 
-			stub := cmdutil.StubResult{
-				Command: "env list",
-				App:     app,
-				Flags:   flags,
-				Status:  cmdutil.StubStatus,
+			if resp.NextPageToken != "" {
+				output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", resp.NextPageToken)
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			return nil
 		},
 	}
 
-	cmd.Flags().Int32Var(&pageSize, "page-size", 0, "maximum number of results per page")
+	cmd.Flags().Int32Var(&pageSize, "page-size", 50, "maximum number of results per page")
 	cmd.Flags().StringVar(&pageToken, "page-token", "", "pagination token from a previous response")
+	cmdutil.AddLabelFlag(cmd, &labelStrs, "filter by label (key=value, repeatable)")
+	cmdutil.AddSelectorFlag(cmd, &selector)
 
 	return cmd
 }
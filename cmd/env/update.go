@@ -1,19 +1,26 @@
 package env
 
 import (
-	"os"
+	"fmt"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
 )
 
 func newUpdateCmd(opts *factory.Options) *cobra.Command {
 	var (
 		promotionOrder int32
 		ttl            time.Duration
+		sourceRef      string
+		labelStrs      []string
 	)
 
 	cmd := &cobra.Command{
@@ -25,7 +32,10 @@ func newUpdateCmd(opts *factory.Options) *cobra.Command {
   admiral env update production --promotion-order 100
 
   # Update TTL for ephemeral environment
-  admiral env update preview-123 --ttl 48h`,
+  admiral env update preview-123 --ttl 48h
+
+  # Point an environment at a new source ref
+  admiral env update staging --source-ref v1.4.0`,
 		Args: cmdutil.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
@@ -35,28 +45,65 @@ func newUpdateCmd(opts *factory.Options) *cobra.Command {
 				return err
 			}
 
-			flags := map[string]any{}
-			if promotionOrder != 0 {
-				flags["promotion-order"] = promotionOrder
+			var paths []string
+			env := &environmentv1.Environment{Id: name, ApplicationId: app}
+
+			if cmd.Flags().Changed("promotion-order") {
+				env.PromotionOrder = promotionOrder
+				paths = append(paths, "promotion_order")
+			}
+
+			if cmd.Flags().Changed("ttl") {
+				if ttl <= 0 {
+					return fmt.Errorf("--ttl must be positive")
+				}
+				env.Ttl = durationpb.New(ttl)
+				paths = append(paths, "ttl")
 			}
-			if ttl > 0 {
-				flags["ttl"] = ttl.String()
+
+			if cmd.Flags().Changed("source-ref") {
+				env.SourceRef = sourceRef
+				paths = append(paths, "source_ref")
+			}
+
+			if cmd.Flags().Changed("label") {
+				labels, err := cmdutil.ParseLabels(labelStrs)
+				if err != nil {
+					return err
+				}
+				env.Labels = labels
+				paths = append(paths, "labels")
 			}
 
-			stub := cmdutil.StubResult{
-				Command:     "env update",
-				App:         app,
-				Environment: name,
-				Flags:       flags,
-				Status:      cmdutil.StubStatus,
+			if len(paths) == 0 {
+				return fmt.Errorf("at least one of --promotion-order, --ttl, --source-ref or --label must be specified")
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Environment().UpdateEnvironment(cmd.Context(), &environmentv1.UpdateEnvironmentRequest{
+				Environment: env,
+				UpdateMask:  &fieldmaskpb.FieldMask{Paths: paths},
+			})
+			if err != nil {
+				return err
 			}
 
-			return cmdutil.PrintStub(os.Stdout, opts.OutputFormat, stub)
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(resp, func(w *tabwriter.Writer) {
+				printEnvironmentRow(w, resp.Environment)
+			})
 		},
 	}
 
 	cmd.Flags().Int32Var(&promotionOrder, "promotion-order", 0, "promotion order (lower = earlier)")
 	cmd.Flags().DurationVar(&ttl, "ttl", 0, "time-to-live for ephemeral environments (e.g. 24h)")
+	cmd.Flags().StringVar(&sourceRef, "source-ref", "", "source reference (branch, tag, or commit)")
+	cmdutil.AddLabelFlag(cmd, &labelStrs, "label to set (key=value, repeatable)")
 
 	return cmd
 }
@@ -1,5 +1,11 @@
 package cmd
 
+import (
+	"errors"
+
+	"go.admiral.io/cli/internal/cmdutil"
+)
+
 type exitError struct {
 	err  error
 	code int
@@ -8,3 +14,19 @@ type exitError struct {
 func (e *exitError) Error() string {
 	return e.err.Error()
 }
+
+// toExitError determines how the CLI exits for err. Subcommands outside this
+// package signal exit codes with cmdutil.ExitError; anything else exits 1.
+func toExitError(err error) *exitError {
+	eerr := &exitError{}
+	if errors.As(err, &eerr) {
+		return eerr
+	}
+
+	cerr := &cmdutil.ExitError{}
+	if errors.As(err, &cerr) {
+		return &exitError{err: cerr.Err, code: cerr.Code}
+	}
+
+	return &exitError{err: err, code: 1}
+}
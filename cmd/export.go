@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/manifest"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/properties"
+)
+
+func newExportCmd(opts *factory.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print live resources as manifests",
+		Long: `Print live resources as manifests that 'admiral apply' accepts.
+
+Server-managed fields such as IDs, timestamps, health and cluster UIDs are
+left out, as is the admiral.io/manifest-set label, which apply sets itself.
+
+Sensitive values are never exported. Each sensitive variable is written as a
+reference to an environment variable named after its app, environment and
+key, e.g. BILLING_API_STAGING_DB_PASSWORD. Applying the manifest reads the
+value from that variable, or keeps the live value if it is unset.
+
+Manifests are written as YAML unless -o json or another document format is
+given.`,
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newExportAppCmd(opts),
+		newExportClusterCmd(opts),
+		newExportVariablesCmd(opts),
+	)
+
+	return cmd
+}
+
+func newExportAppCmd(opts *factory.Options) *cobra.Command {
+	var exportOpts manifest.ExportOptions
+
+	cmd := &cobra.Command{
+		Use:   "app [app]",
+		Short: "Export an application",
+		Long: `Export an application, and optionally its environments and variables.
+
+The app can be provided as a positional argument or resolved from the
+active context set via 'admiral use <app>'.`,
+		Example: `  # Export an application with its environments and variables
+  admiral export app billing-api --with-envs --with-variables > billing.yaml
+
+  # Re-apply it
+  admiral apply -f billing.yaml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			app := ""
+			if len(args) == 1 {
+				app = args[0]
+			}
+			if app == "" {
+				props, err := properties.Load(opts.ConfigDir)
+				if err != nil {
+					return err
+				}
+				app = props.App
+			}
+			if app == "" {
+				_ = cmd.Help()
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return fmt.Errorf("no app specified; use a positional argument or set context with 'admiral use <app>'")
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			manifests, err := manifest.ExportApplication(cmd.Context(), c, app, exportOpts)
+			if err != nil {
+				return err
+			}
+			return printManifests(cmd, opts.OutputFormat, manifests)
+		},
+	}
+
+	cmd.Flags().BoolVar(&exportOpts.Environments, "with-envs", false, "include the application's environments")
+	cmd.Flags().BoolVar(&exportOpts.Variables, "with-variables", false, "include the application's variables, and with --with-envs each environment's")
+
+	return cmd
+}
+
+func newExportClusterCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cluster <name>",
+		Short: "Export a cluster",
+		Args:  cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			m, err := manifest.ExportCluster(cmd.Context(), c, args[0])
+			if err != nil {
+				return err
+			}
+			return printManifests(cmd, opts.OutputFormat, []*manifest.Manifest{m})
+		},
+	}
+}
+
+func newExportVariablesCmd(opts *factory.Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "variables",
+		Short: "Export the global variables",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			m, err := manifest.ExportVariables(cmd.Context(), c)
+			if err != nil {
+				return err
+			}
+			return printManifests(cmd, opts.OutputFormat, []*manifest.Manifest{m})
+		},
+	}
+}
+
+// printManifests writes manifests as a YAML stream for the table and yaml
+// formats, or as a list in any other format.
+func printManifests(cmd *cobra.Command, format output.Format, manifests []*manifest.Manifest) error {
+	if format.IsTable() || format == output.FormatYAML {
+		return manifest.Encode(cmd.OutOrStdout(), manifests)
+	}
+	p := output.NewPrinter(format)
+	p.Out = cmd.OutOrStdout()
+	return p.PrintObject(manifests, nil)
+}
@@ -2,9 +2,10 @@ package cmd
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -13,6 +14,7 @@ import (
 	appcmd "go.admiral.io/cli/cmd/app"
 	authcmd "go.admiral.io/cli/cmd/auth"
 	clustercmd "go.admiral.io/cli/cmd/cluster"
+	configcmd "go.admiral.io/cli/cmd/config"
 	envcmd "go.admiral.io/cli/cmd/env"
 	variablecmd "go.admiral.io/cli/cmd/variable"
 	"go.admiral.io/cli/internal/config"
@@ -29,14 +31,14 @@ func Execute(version version.Version, exit func(int), args []string) {
 func (cmd *rootCmd) Execute(args []string) {
 	cmd.cmd.SetArgs(args)
 
-	if err := cmd.cmd.Execute(); err != nil {
-		code := 1
-		eerr := &exitError{}
-		if errors.As(err, &eerr) {
-			code = eerr.code
+	err := cmd.cmd.Execute()
+	credentials.CloseStores()
+	if err != nil {
+		eerr := toExitError(err)
+		if eerr.err != nil {
+			output.Writef(os.Stderr, "Error: %s\n", err)
 		}
-		output.Writef(os.Stderr, "Error: %s\n", err)
-		cmd.exit(code)
+		cmd.exit(eerr.code)
 	}
 }
 
@@ -46,6 +48,7 @@ type rootCmd struct {
 
 	verbose      bool
 	configPath   string
+	profile      string
 	outputFormat string
 	factoryOpts  *factory.Options
 }
@@ -70,13 +73,27 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 				slog.Debug("debug logs enabled")
 			}
 
+			factoryOpts.ConfigDir = root.configPath
+			factoryOpts.Verbose = root.verbose
+
+			cfg, err := config.Load(root.configPath)
+			if err != nil {
+				return err
+			}
+			if err := applyProfile(cmd, root, cfg, &factoryOpts); err != nil {
+				return err
+			}
+
 			f, err := output.ParseFormat(root.outputFormat)
 			if err != nil {
 				return err
 			}
+			if f.Kind() == output.FormatView {
+				if f, err = resolveView(cmd, cfg, f.Arg()); err != nil {
+					return err
+				}
+			}
 			factoryOpts.OutputFormat = f
-			factoryOpts.ConfigDir = root.configPath
-			factoryOpts.Verbose = root.verbose
 
 			return nil
 		},
@@ -89,7 +106,7 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 
 			done := make(chan struct{})
 			go func() {
-				_ = credentials.ProactiveRefresh(root.configPath, time.Minute)
+				_ = credentials.ProactiveRefresh(root.configPath, factoryOpts.CredentialKey(), time.Minute)
 				close(done)
 			}()
 
@@ -110,6 +127,7 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 
 	// Config flags
 	cmd.PersistentFlags().StringVar(&root.configPath, "config-dir", defaultConfigPath, "path to config directory")
+	cmd.PersistentFlags().StringVar(&root.profile, "profile", "", "named profile from the config file (overrides $ADMIRAL_PROFILE)")
 
 	// Server flags
 	cmd.PersistentFlags().StringVarP(&factoryOpts.ServerAddr, "server", "s", "api.admiral.io:443", "host:port of the API server")
@@ -117,7 +135,7 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 	cmd.PersistentFlags().BoolVarP(&factoryOpts.Insecure, "insecure", "i", false, "skip server certificate and domain verification")
 
 	// Output flags
-	cmd.PersistentFlags().StringVarP(&root.outputFormat, "output", "o", "table", "output format: table, json, yaml, wide")
+	cmd.PersistentFlags().StringVarP(&root.outputFormat, "output", "o", "table", "output format: "+output.FormatHelp)
 
 	// Auth flags (hidden, for dev/testing override)
 	cmd.PersistentFlags().StringVar(&factoryOpts.Issuer, "issuer", "https://auth.admiral.io", "OIDC identity provider URL")
@@ -135,6 +153,9 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 	authCmd := authcmd.NewAuthCmd(&factoryOpts)
 	cmd.AddCommand(authCmd.Cmd)
 
+	// Config commands
+	cmd.AddCommand(configcmd.NewConfigCmd(&factoryOpts).Cmd)
+
 	// Resource commands
 	cmd.AddCommand(
 		appcmd.NewAppCmd(&factoryOpts).Cmd,
@@ -143,6 +164,13 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 		variablecmd.NewVariableCmd(&factoryOpts).Cmd,
 	)
 
+	// Declarative commands
+	cmd.AddCommand(
+		newApplyCmd(&factoryOpts),
+		newExportCmd(&factoryOpts),
+		newDiffCmd(&factoryOpts),
+	)
+
 	// Utility commands
 	cmd.AddCommand(
 		newCompletionCmd(),
@@ -156,3 +184,89 @@ func newRootCmd(ver version.Version, exit func(int)) *rootCmd {
 
 	return root
 }
+
+// applyProfile overlays the active profile from cfg onto opts. Flags set
+// explicitly on the command line always take precedence.
+//
+// A missing profile only warns under the config command, so it can still be
+// used to add, select or remove profiles.
+func applyProfile(cmd *cobra.Command, root *rootCmd, cfg *config.Config, opts *factory.Options) error {
+	name, p, err := cfg.ResolveProfile(root.profile)
+	if err != nil {
+		if !inConfigCmd(cmd) {
+			return err
+		}
+		output.Writef(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		return nil
+	}
+	if p == nil {
+		return nil
+	}
+
+	slog.Debug("using profile", "profile", name)
+	opts.Profile = name
+
+	flags := cmd.Flags()
+	if p.Server != "" && !flags.Changed("server") {
+		opts.ServerAddr = p.Server
+	}
+	if p.PlainText && !flags.Changed("plaintext") {
+		opts.PlainText = true
+	}
+	if p.Insecure && !flags.Changed("insecure") {
+		opts.Insecure = true
+	}
+	if p.Issuer != "" && !flags.Changed("issuer") {
+		opts.Issuer = p.Issuer
+	}
+	if p.ClientID != "" && !flags.Changed("client-id") {
+		opts.ClientID = p.ClientID
+	}
+	if len(p.Scopes) > 0 && !flags.Changed("scopes") {
+		opts.Scopes = p.Scopes
+	}
+	if p.Output != "" && !flags.Changed("output") {
+		root.outputFormat = p.Output
+	}
+
+	return nil
+}
+
+// inConfigCmd reports whether cmd is the config command or below it.
+func inConfigCmd(cmd *cobra.Command) bool {
+	for c := cmd; c != nil && c.HasParent(); c = c.Parent() {
+		if !c.Parent().HasParent() {
+			return c.Name() == "config"
+		}
+	}
+	return false
+}
+
+// resolveView looks up the named view for the resource type of cmd and
+// returns it as a custom-columns format.
+func resolveView(cmd *cobra.Command, cfg *config.Config, name string) (output.Format, error) {
+	spec, err := cfg.View(viewResource(cmd), name)
+	if err != nil {
+		return "", err
+	}
+	f, err := output.ParseFormat(string(output.FormatCustomColumns) + "=" + spec)
+	if err != nil {
+		return "", fmt.Errorf("view %q: %w", name, err)
+	}
+	return f, nil
+}
+
+// viewResource returns the resource type views are saved under for cmd: the
+// command groups above it joined by "-", e.g. "cluster" for 'cluster list'
+// and "cluster-token" for 'cluster token list'. A top-level command is its
+// own resource type.
+func viewResource(cmd *cobra.Command) string {
+	var groups []string
+	for c := cmd.Parent(); c != nil && c.HasParent(); c = c.Parent() {
+		groups = append([]string{c.Name()}, groups...)
+	}
+	if len(groups) == 0 {
+		return cmd.Name()
+	}
+	return strings.Join(groups, "-")
+}
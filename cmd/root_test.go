@@ -2,12 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/config"
+	"go.admiral.io/cli/internal/credentials"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/version"
 )
 
@@ -57,7 +64,7 @@ func TestRootCmd_HasExpectedSubcommands(t *testing.T) {
 	root := newRootCmd(testversion, mem.Exit).cmd
 
 	expected := []string{
-		"auth", "cluster", "version", "completion", "whoami", "use",
+		"auth", "cluster", "version", "completion", "whoami", "use", "apply", "export", "diff",
 	}
 
 	names := make([]string, 0, len(root.Commands()))
@@ -77,7 +84,7 @@ func TestRootCmd_AuthSubcommands(t *testing.T) {
 	auth, _, err := root.Find([]string{"auth"})
 	require.NoError(t, err)
 
-	expected := []string{"login", "logout"}
+	expected := []string{"login", "logout", "status"}
 	names := subcmdNames(auth)
 	for _, want := range expected {
 		require.Contains(t, names, want, "auth missing subcommand %q", want)
@@ -91,7 +98,7 @@ func TestRootCmd_ClusterSubcommands(t *testing.T) {
 	cluster, _, err := root.Find([]string{"cluster"})
 	require.NoError(t, err)
 
-	expected := []string{"list", "get", "create", "update", "delete", "status", "token"}
+	expected := []string{"list", "get", "create", "update", "delete", "status", "kubeconfig", "credential", "token"}
 	names := subcmdNames(cluster)
 	for _, want := range expected {
 		require.Contains(t, names, want, "cluster missing subcommand %q", want)
@@ -128,6 +135,7 @@ func TestRootCmd_PersistentFlags(t *testing.T) {
 		{"output", "o"},
 		{"verbose", "v"},
 		{"config-dir", ""},
+		{"profile", ""},
 		{"plaintext", ""},
 		{"insecure", "i"},
 	}
@@ -265,6 +273,30 @@ func TestRootCmd_ExecuteWithExitErrorCode(t *testing.T) {
 	require.Equal(t, 42, mem.code)
 }
 
+func TestRootCmd_ExecuteWithSubcommandExitError(t *testing.T) {
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit)
+
+	root.cmd.AddCommand(&cobra.Command{
+		Use: "differ",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return &cmdutil.ExitError{Code: 1}
+		},
+	})
+	root.cmd.AddCommand(&cobra.Command{
+		Use: "fail",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("wrapped: %w", &cmdutil.ExitError{Err: &simpleError{msg: "custom exit"}, Code: 3})
+		},
+	})
+
+	root.Execute([]string{"differ"})
+	require.Equal(t, 1, mem.code)
+
+	root.Execute([]string{"fail"})
+	require.Equal(t, 3, mem.code)
+}
+
 // ---------------------------------------------------------------------------
 // Version command
 // ---------------------------------------------------------------------------
@@ -395,9 +427,20 @@ func TestLeafCommand_ArgValidation(t *testing.T) {
 		{"cluster delete needs 1 arg", []string{"cluster", "delete"}},
 		{"cluster update needs 1 arg", []string{"cluster", "update"}},
 		{"cluster status needs 1 arg", []string{"cluster", "status"}},
+		{"cluster kubeconfig needs 1 arg", []string{"cluster", "kubeconfig"}},
+		{"cluster credential needs 1 arg", []string{"cluster", "credential"}},
+		{"variable run needs a command", []string{"variable", "run", "my-api"}},
+		{"variable run rejects 2 args before --", []string{"variable", "run", "a", "b", "--", "env"}},
+		{"variable search needs 1 arg", []string{"variable", "search"}},
 		{"cluster token get needs 2 args", []string{"cluster", "token", "get"}},
 		{"cluster token get rejects 3 args", []string{"cluster", "token", "get", "a", "b", "c"}},
 		{"cluster token revoke needs 2 args", []string{"cluster", "token", "revoke"}},
+		{"apply needs -f", []string{"apply"}},
+		{"apply --prune needs --set", []string{"apply", "-f", "platform.yaml", "--prune"}},
+		{"diff needs -f", []string{"diff"}},
+		{"diff --prune needs --set", []string{"diff", "-f", "platform.yaml", "--prune"}},
+		{"export cluster needs 1 arg", []string{"export", "cluster"}},
+		{"export app rejects 2 args", []string{"export", "app", "a", "b"}},
 	}
 
 	for _, tc := range tests {
@@ -412,6 +455,19 @@ func TestLeafCommand_ArgValidation(t *testing.T) {
 	}
 }
 
+// Deletes can't be confirmed without a terminal, so apply refuses to start.
+func TestApply_PruneRequiresConfirmWithoutTerminal(t *testing.T) {
+	for _, file := range []string{"platform.yaml", "-"} {
+		mem := &exitMemento{}
+		root := newRootCmd(testversion, mem.Exit).cmd
+		root.SetIn(strings.NewReader(""))
+		root.SetArgs([]string{"apply", "-f", file, "--set", "platform", "--prune"})
+
+		err := root.Execute()
+		require.ErrorContains(t, err, "--prune requires --confirm")
+	}
+}
+
 // Leaf commands that take no args should reject stray args.
 func TestLeafCommand_NoArgsValidation(t *testing.T) {
 	tests := []struct {
@@ -420,6 +476,9 @@ func TestLeafCommand_NoArgsValidation(t *testing.T) {
 	}{
 		{"cluster list rejects args", []string{"cluster", "list", "extra"}},
 		{"cluster create rejects args", []string{"cluster", "create", "extra"}},
+		{"apply rejects args", []string{"apply", "-f", "platform.yaml", "extra"}},
+		{"diff rejects args", []string{"diff", "-f", "platform.yaml", "extra"}},
+		{"export variables rejects args", []string{"export", "variables", "extra"}},
 	}
 
 	for _, tc := range tests {
@@ -567,6 +626,259 @@ func TestWhoamiCmd_RejectsArgs(t *testing.T) {
 	require.Error(t, err)
 }
 
+// ---------------------------------------------------------------------------
+// Config profile commands
+// ---------------------------------------------------------------------------
+
+func TestRootCmd_ConfigProfileSubcommands(t *testing.T) {
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+
+	profileCmd, _, err := root.Find([]string{"config", "profile"})
+	require.NoError(t, err)
+
+	expected := []string{"list", "add", "remove", "use"}
+	names := subcmdNames(profileCmd)
+	for _, want := range expected {
+		require.Contains(t, names, want, "config profile missing subcommand %q", want)
+	}
+}
+
+func TestConfigProfile_AddListUse(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	mem := &exitMemento{}
+
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "add", "staging", "--server", "staging:443", "--use"})
+	require.NoError(t, root.Execute())
+
+	root = newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "add", "dev", "--server", "localhost:8080", "--plaintext"})
+	require.NoError(t, root.Execute())
+
+	var buf bytes.Buffer
+	root = newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&buf)
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "list"})
+	require.NoError(t, root.Execute())
+	require.Contains(t, buf.String(), "staging:443")
+	require.Contains(t, buf.String(), "localhost:8080")
+
+	buf.Reset()
+	root = newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&buf)
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "use", "dev"})
+	require.NoError(t, root.Execute())
+	require.Contains(t, buf.String(), `Active profile set to "dev"`)
+
+	cfg, err := config.Load(dir)
+	require.NoError(t, err)
+	require.Equal(t, "dev", cfg.CurrentProfile)
+	require.True(t, cfg.Profiles["dev"].PlainText)
+	require.Empty(t, cfg.Profiles["staging"].Issuer, "unset flags should not be captured")
+}
+
+func TestConfigProfile_AddDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	mem := &exitMemento{}
+
+	for i, wantErr := range []bool{false, true} {
+		root := newRootCmd(testversion, mem.Exit).cmd
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"--config-dir", dir, "config", "profile", "add", "prod", "--server", "prod:443"})
+		err := root.Execute()
+		if wantErr {
+			require.ErrorContains(t, err, "already exists", "attempt %d", i)
+		} else {
+			require.NoError(t, err, "attempt %d", i)
+		}
+	}
+}
+
+func TestConfigProfile_Remove(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	require.NoError(t, config.Save(dir, &config.Config{
+		CurrentProfile: "prod",
+		Profiles:       map[string]*config.Profile{"prod": {Server: "prod:443"}},
+	}))
+
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "remove", "prod"})
+	require.NoError(t, root.Execute())
+
+	cfg, err := config.Load(dir)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Profiles)
+	require.Empty(t, cfg.CurrentProfile, "removing the active profile should clear it")
+}
+
+func TestConfigProfile_UseUnknown(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "use", "missing"})
+
+	err := root.Execute()
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestConfigProfile_MissingActiveProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "gone")
+	require.NoError(t, config.Save(dir, &config.Config{
+		Profiles: map[string]*config.Profile{"prod": {Server: "prod:443"}},
+	}))
+
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", dir, "version"})
+	require.ErrorContains(t, root.Execute(), `profile "gone" not found`)
+
+	var errOut bytes.Buffer
+	root = newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&errOut)
+	root.SetArgs([]string{"--config-dir", dir, "config", "profile", "add", "gone", "--server", "gone:443"})
+	require.NoError(t, root.Execute())
+	require.Contains(t, errOut.String(), `Warning: profile "gone" not found`)
+
+	cfg, err := config.Load(dir)
+	require.NoError(t, err)
+	require.Contains(t, cfg.Profiles, "gone")
+}
+
+func TestAuthStatus_ListsSessions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv(credentials.EnvToken, "")
+
+	tok := &oauth2.Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)}
+	require.NoError(t, credentials.SaveToken(dir, "api.admiral.io:443", tok, "https://auth.admiral.io", "cid", "url"))
+	require.NoError(t, credentials.SaveToken(dir, "staging:443", tok, "https://auth.staging", "cid", "url"))
+
+	var buf bytes.Buffer
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&buf)
+	root.SetArgs([]string{"--config-dir", dir, "auth", "status"})
+	require.NoError(t, root.Execute())
+
+	out := buf.String()
+	require.Contains(t, out, "api.admiral.io:443")
+	require.Contains(t, out, "staging:443")
+	require.Contains(t, out, "*")
+}
+
+func TestAuthStatus_NoSessions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+
+	var buf bytes.Buffer
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&buf)
+	root.SetArgs([]string{"--config-dir", dir, "auth", "status"})
+	require.NoError(t, root.Execute())
+	require.Contains(t, buf.String(), "No stored sessions")
+}
+
+func TestAuthLogin_ClientCredentialsRequiresClientID(t *testing.T) {
+	t.Setenv(config.EnvProfile, "")
+	t.Setenv("ADMIRAL_CLIENT_ID", "")
+	t.Setenv("ADMIRAL_CLIENT_SECRET", "")
+
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", t.TempDir(), "auth", "login", "--client-credentials"})
+
+	err := root.Execute()
+	require.ErrorContains(t, err, "no client ID")
+}
+
+func TestAuthLogin_DeviceAndClientCredentialsExclusive(t *testing.T) {
+	t.Setenv(config.EnvProfile, "")
+
+	mem := &exitMemento{}
+	root := newRootCmd(testversion, mem.Exit).cmd
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"--config-dir", t.TempDir(), "auth", "login", "--device", "--client-credentials"})
+
+	err := root.Execute()
+	require.ErrorContains(t, err, "none of the others can be")
+}
+
+func TestApplyProfile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, config.Save(dir, &config.Config{
+		CurrentProfile: "prod",
+		Profiles: map[string]*config.Profile{
+			"prod": {Server: "prod:443"},
+			"dev": {
+				Server:    "localhost:8080",
+				PlainText: true,
+				Issuer:    "http://localhost:5556",
+				ClientID:  "dev-client",
+				Output:    "json",
+			},
+		},
+	}))
+
+	run := func(t *testing.T, args ...string) *rootCmd {
+		t.Helper()
+		mem := &exitMemento{}
+		root := newRootCmd(testversion, mem.Exit)
+		root.cmd.SetOut(&bytes.Buffer{})
+		root.cmd.SetArgs(append([]string{"--config-dir", dir}, append(args, "version")...))
+		require.NoError(t, root.cmd.Execute())
+		return root
+	}
+
+	t.Run("current profile", func(t *testing.T) {
+		t.Setenv(config.EnvProfile, "")
+		root := run(t)
+		require.Equal(t, "prod", root.factoryOpts.Profile)
+		require.Equal(t, "prod:443", root.factoryOpts.ServerAddr)
+		require.Equal(t, "https://auth.admiral.io", root.factoryOpts.Issuer)
+	})
+
+	t.Run("env selects profile", func(t *testing.T) {
+		t.Setenv(config.EnvProfile, "dev")
+		root := run(t)
+		require.Equal(t, "dev", root.factoryOpts.Profile)
+		require.Equal(t, "localhost:8080", root.factoryOpts.ServerAddr)
+		require.True(t, root.factoryOpts.PlainText)
+		require.Equal(t, "http://localhost:5556", root.factoryOpts.Issuer)
+		require.Equal(t, "dev-client", root.factoryOpts.ClientID)
+		require.Equal(t, output.FormatJSON, root.factoryOpts.OutputFormat)
+	})
+
+	t.Run("flags override profile", func(t *testing.T) {
+		t.Setenv(config.EnvProfile, "")
+		root := run(t, "--profile", "dev", "--server", "other:443", "-o", "yaml")
+		require.Equal(t, "dev", root.factoryOpts.Profile)
+		require.Equal(t, "other:443", root.factoryOpts.ServerAddr)
+		require.Equal(t, output.FormatYAML, root.factoryOpts.OutputFormat)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		mem := &exitMemento{}
+		root := newRootCmd(testversion, mem.Exit).cmd
+		root.SetArgs([]string{"--config-dir", dir, "--profile", "missing", "version"})
+		require.ErrorContains(t, root.Execute(), "not found")
+	})
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
@@ -586,3 +898,64 @@ type simpleError struct {
 func (e *simpleError) Error() string {
 	return e.msg
 }
+
+// ---------------------------------------------------------------------------
+// Config view commands
+// ---------------------------------------------------------------------------
+
+func TestConfigView_SetListUseRemove(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.EnvProfile, "")
+	mem := &exitMemento{}
+
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		root := newRootCmd(testversion, mem.Exit).cmd
+		root.SetOut(&buf)
+		root.SetArgs(append([]string{"--config-dir", dir}, args...))
+		err := root.Execute()
+		return buf.String(), err
+	}
+
+	_, err := run("config", "profile", "add", "staging", "--server", "staging:443", "--use")
+	require.NoError(t, err)
+
+	_, err = run("config", "view", "set", "config-profile", "current", "CURRENT:.currentProfile")
+	require.NoError(t, err)
+
+	out, err := run("config", "view", "list")
+	require.NoError(t, err)
+	require.Contains(t, out, "config-profile")
+	require.Contains(t, out, "CURRENT:.currentProfile")
+
+	out, err = run("config", "profile", "list", "-o", "view=current")
+	require.NoError(t, err)
+	require.Equal(t, "CURRENT\nstaging\n", out)
+
+	_, err = run("config", "view", "list", "-o", "view=current")
+	require.ErrorContains(t, err, `view "current" not found for config-view`)
+
+	_, err = run("config", "view", "set", "cluster", "bad", "NAME")
+	require.ErrorContains(t, err, "expected HEADER:PATH")
+
+	_, err = run("config", "view", "remove", "config-profile", "current")
+	require.NoError(t, err)
+	_, err = run("config", "profile", "list", "-o", "view=current")
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestViewResource(t *testing.T) {
+	root := newRootCmd(testversion, (&exitMemento{}).Exit).cmd
+
+	for args, want := range map[string]string{
+		"cluster list":       "cluster",
+		"cluster get":        "cluster",
+		"cluster token list": "cluster-token",
+		"variable list":      "variable",
+		"whoami":             "whoami",
+	} {
+		cmd, _, err := root.Find(strings.Fields(args))
+		require.NoError(t, err)
+		require.Equal(t, want, viewResource(cmd), args)
+	}
+}
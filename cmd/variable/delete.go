@@ -1,13 +1,15 @@
 package variable
 
 import (
-	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func newDeleteCmd(opts *factory.Options) *cobra.Command {
@@ -69,16 +71,26 @@ active context set via 'admiral use <app>'. Use --global for global variables
 				}
 			}
 
-			stub := stubResult{
-				Operation:   "delete",
-				Scope:       string(rs.Scope),
-				App:         rs.App,
-				Environment: rs.Env,
-				Key:         key,
-				Status:      stubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Variable().DeleteVariable(cmd.Context(), &variablev1.DeleteVariableRequest{
+				Scope:         rs.Scope.proto(),
+				ApplicationId: rs.App,
+				EnvironmentId: rs.Env,
+				Key:           key,
+			})
+			if err != nil {
+				return err
 			}
 
-			return printStub(os.Stdout, opts.OutputFormat, stub)
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(resp, func(w *tabwriter.Writer) {
+				output.Writef(w, "Variable %s deleted\n", key)
+			})
 		},
 	}
 
@@ -0,0 +1,139 @@
+package variable
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// Ways to export sensitive values, for --sensitive-values.
+const (
+	sensitiveOmit        = "omit"
+	sensitivePlaceholder = "placeholder"
+)
+
+func newExportCmd(opts *factory.Options) *cobra.Command {
+	var (
+		envFlag         string
+		globalFlag      bool
+		file            string
+		format          string
+		sensitiveValues string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export [app]",
+		Short: "Export variables to a file",
+		Long: `Export the variables in a scope as a .env, JSON or YAML file.
+
+Writes to stdout unless --file is given. The format is inferred from the
+--file extension unless --format is given; stdout defaults to dotenv.
+
+Sensitive values are never exported. By default sensitive variables are
+left out; with --sensitive-values placeholder they are written with the
+value ` + variables.MaskedValue + `, which 'variable import' skips.`,
+		Example: `  # Export an environment's variables as a .env file
+  admiral variable export my-api -e staging --file staging.env
+
+  # Export app-scoped variables as YAML to stdout
+  admiral variable export my-api --format yaml
+
+  # List sensitive keys with a placeholder value
+  admiral variable export my-api -e production --sensitive-values placeholder`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sensitiveValues != sensitiveOmit && sensitiveValues != sensitivePlaceholder {
+				return fmt.Errorf("invalid --sensitive-values %q: must be omit or placeholder", sensitiveValues)
+			}
+
+			ff, err := detectFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			var appArg string
+			if len(args) == 1 {
+				appArg = args[0]
+			}
+
+			props, err := properties.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			rs, err := resolveScopeWithHelp(cmd, globalFlag, envFlag, appArg, props.App)
+			if err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			current, err := variables.ListAll(cmd.Context(), c.Variable(), &variablev1.ListVariablesRequest{
+				Scope:         rs.Scope.proto(),
+				ApplicationId: rs.App,
+				EnvironmentId: rs.Env,
+			})
+			if err != nil {
+				return err
+			}
+
+			vars, omitted := exportValues(current, sensitiveValues)
+			if len(omitted) > 0 {
+				output.Writef(cmd.ErrOrStderr(), "Omitted %d sensitive variable(s): %v\n", len(omitted), omitted)
+			}
+
+			if file != "" && file != "-" {
+				f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) //nolint:gosec // path is supplied by the user on purpose
+				if err != nil {
+					return err
+				}
+				defer f.Close() //nolint:errcheck // closed explicitly below
+				if err := writeVariables(f, ff, vars); err != nil {
+					return err
+				}
+				return f.Close()
+			}
+
+			return writeVariables(cmd.OutOrStdout(), ff, vars)
+		},
+	}
+
+	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
+	cmd.Flags().BoolVar(&globalFlag, "global", false, "export variables at global scope")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "file to write (default: stdout)")
+	cmd.Flags().StringVar(&format, "format", "", "file format: dotenv, json or yaml (default: from extension, or dotenv)")
+	cmd.Flags().StringVar(&sensitiveValues, "sensitive-values", sensitiveOmit, "how to export sensitive variables: omit or placeholder")
+
+	return cmd
+}
+
+// exportValues returns the values to export and the sorted keys of sensitive
+// variables that were left out.
+func exportValues(vars []*variablev1.Variable, sensitiveValues string) (map[string]string, []string) {
+	values := make(map[string]string, len(vars))
+	var omitted []string
+	for _, v := range vars {
+		switch {
+		case !v.Sensitive:
+			values[v.Key] = v.Value
+		case sensitiveValues == sensitivePlaceholder:
+			values[v.Key] = variables.MaskedValue
+		default:
+			omitted = append(omitted, v.Key)
+		}
+	}
+	slices.Sort(omitted)
+	return values, omitted
+}
@@ -0,0 +1,313 @@
+package variable
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileFormat is a variable file format for import and export.
+type fileFormat string
+
+const (
+	formatDotenv fileFormat = "dotenv"
+	formatJSON   fileFormat = "json"
+	formatYAML   fileFormat = "yaml"
+)
+
+// detectFormat returns the explicit format if set, otherwise infers it from
+// the file extension. Anything that isn't .json, .yaml or .yml is read as a
+// dotenv file.
+func detectFormat(explicit, path string) (fileFormat, error) {
+	switch fileFormat(explicit) {
+	case formatDotenv, formatJSON, formatYAML:
+		return fileFormat(explicit), nil
+	case "":
+	default:
+		return "", fmt.Errorf("invalid format %q: must be one of dotenv, json, yaml", explicit)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return formatJSON, nil
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	default:
+		return formatDotenv, nil
+	}
+}
+
+// parseVariables reads a flat KEY=VALUE document in the given format.
+func parseVariables(format fileFormat, data []byte) (map[string]string, error) {
+	var (
+		vars map[string]string
+		err  error
+	)
+	switch format {
+	case formatJSON:
+		vars, err = parseJSONVariables(data)
+	case formatYAML:
+		vars, err = parseYAMLVariables(data)
+	default:
+		vars, err = parseDotenv(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range vars {
+		if err := validateKey(k); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// validateKey rejects keys that can't round-trip through a dotenv file.
+func validateKey(k string) error {
+	if k == "" || strings.ContainsAny(k, "= \t\r\n#\"'") {
+		return fmt.Errorf("invalid variable key %q", k)
+	}
+	return nil
+}
+
+// parseDotenv parses a dotenv file: KEY=VALUE lines with optional "export"
+// prefixes, blank lines and # comments. Values may be single-quoted (taken
+// literally) or double-quoted (with \n, \r, \t, \" and \\ escapes, and
+// spanning lines). Unquoted values end at " #".
+func parseDotenv(data []byte) (map[string]string, error) {
+	vars := map[string]string{}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		switch {
+		case strings.HasPrefix(rest, `"`):
+			// Double-quoted values may continue on following lines.
+			quoted := rest[1:]
+			for {
+				v, tail, closed := cutDoubleQuoted(quoted)
+				if closed {
+					if err := checkTrailing(tail); err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNo, err)
+					}
+					value = v
+					break
+				}
+				i++
+				if i >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated double-quoted value", lineNo)
+				}
+				quoted += "\n" + lines[i]
+			}
+		case strings.HasPrefix(rest, "'"):
+			end := strings.Index(rest[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single-quoted value", lineNo)
+			}
+			if err := checkTrailing(rest[end+2:]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			value = rest[1 : end+1]
+		default:
+			if idx := strings.Index(rest, " #"); idx >= 0 {
+				rest = rest[:idx]
+			}
+			value = strings.TrimSpace(rest)
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// cutDoubleQuoted unescapes s up to its closing double quote and returns the
+// value and whatever follows the quote. closed is false if s has no closing
+// quote.
+func cutDoubleQuoted(s string) (value, tail string, closed bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), s[i+1:], true
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", false
+}
+
+// checkTrailing allows only whitespace and a comment after a quoted value.
+func checkTrailing(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && !strings.HasPrefix(s, "#") {
+		return fmt.Errorf("unexpected %q after quoted value", s)
+	}
+	return nil
+}
+
+// parseJSONVariables parses a JSON object of scalar values. Numbers and
+// booleans are kept in their literal form.
+func parseJSONVariables(data []byte) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of KEY: VALUE pairs: %w", err)
+	}
+
+	vars := make(map[string]string, len(raw))
+	for k, v := range raw {
+		v = bytes.TrimSpace(v)
+		switch {
+		case len(v) > 0 && v[0] == '"':
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return nil, fmt.Errorf("key %q: %w", k, err)
+			}
+			vars[k] = s
+		case len(v) > 0 && (v[0] == '{' || v[0] == '['):
+			return nil, fmt.Errorf("key %q: nested values are not supported", k)
+		case string(v) == "null":
+			return nil, fmt.Errorf("key %q: null values are not supported", k)
+		default:
+			vars[k] = string(v)
+		}
+	}
+	return vars, nil
+}
+
+// parseYAMLVariables parses a YAML mapping of scalar values. Scalars are kept
+// as written, so 010 and "yes" aren't reinterpreted.
+func parseYAMLVariables(data []byte) (map[string]string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return map[string]string{}, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a YAML mapping of KEY: VALUE pairs")
+	}
+
+	vars := make(map[string]string, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+		if v.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: key %q: nested values are not supported", v.Line, k.Value)
+		}
+		if v.Tag == "!!null" {
+			return nil, fmt.Errorf("line %d: key %q: null values are not supported", v.Line, k.Value)
+		}
+		vars[k.Value] = v.Value
+	}
+	return vars, nil
+}
+
+// writeVariables writes vars in the given format, sorted by key.
+func writeVariables(w io.Writer, format fileFormat, vars map[string]string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(vars)
+	case formatYAML:
+		if len(vars) == 0 {
+			_, err := io.WriteString(w, "{}\n")
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(vars); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return writeDotenv(w, vars)
+	}
+}
+
+func writeDotenv(w io.Writer, vars map[string]string) error {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	bw := bufio.NewWriter(w)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(bw, "%s=%s\n", k, quoteDotenv(vars[k]))
+	}
+	return bw.Flush()
+}
+
+// quoteDotenv leaves simple values bare and double-quotes anything that
+// parseDotenv would otherwise read differently.
+func quoteDotenv(v string) string {
+	plain := v != "" && strings.TrimSpace(v) == v && !strings.ContainsAny(v, "\"'#\\\n\r\t")
+	if plain {
+		return v
+	}
+	if v == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range []byte(v) {
+		switch c {
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
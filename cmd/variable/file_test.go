@@ -0,0 +1,204 @@
+package variable
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		explicit string
+		path     string
+		want     fileFormat
+		wantErr  string
+	}{
+		{path: ".env", want: formatDotenv},
+		{path: "staging.env", want: formatDotenv},
+		{path: "vars.json", want: formatJSON},
+		{path: "vars.YAML", want: formatYAML},
+		{path: "vars.yml", want: formatYAML},
+		{path: "", want: formatDotenv},
+		{explicit: "json", path: "vars.txt", want: formatJSON},
+		{explicit: "toml", wantErr: "invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.explicit+tt.path, func(t *testing.T) {
+			got, err := detectFormat(tt.explicit, tt.path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDotenv(t *testing.T) {
+	data := []byte(`# comment
+export LOG_LEVEL=debug
+PORT = 8080 # trailing comment
+EMPTY=
+SINGLE='literal \n $HOME # not a comment'
+DOUBLE="tab\there \"quoted\" back\\slash"
+CERT="-----BEGIN CERTIFICATE-----
+MIIB
+-----END CERTIFICATE-----
+"
+URL=https://example.com/#anchor
+`)
+
+	vars, err := parseVariables(formatDotenv, data)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"LOG_LEVEL": "debug",
+		"PORT":      "8080",
+		"EMPTY":     "",
+		"SINGLE":    `literal \n $HOME # not a comment`,
+		"DOUBLE":    "tab\there \"quoted\" back\\slash",
+		"CERT":      "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		"URL":       "https://example.com/#anchor",
+	}, vars)
+
+	for _, bad := range []string{"NOVALUE", "=x", `A="open`, "A='open", `A="x" y`, "BAD KEY=1"} {
+		_, err := parseVariables(formatDotenv, []byte(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+func TestParseJSONVariables(t *testing.T) {
+	vars, err := parseVariables(formatJSON, []byte(`{"PORT": 8080, "DEBUG": true, "RATIO": 0.5, "NAME": "api"}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"PORT": "8080", "DEBUG": "true", "RATIO": "0.5", "NAME": "api"}, vars)
+
+	_, err = parseVariables(formatJSON, []byte(`{"A": {"nested": 1}}`))
+	require.ErrorContains(t, err, "nested values are not supported")
+
+	_, err = parseVariables(formatJSON, []byte(`["A"]`))
+	require.ErrorContains(t, err, "expected a JSON object")
+}
+
+func TestParseYAMLVariables(t *testing.T) {
+	vars, err := parseVariables(formatYAML, []byte("ZIP: 010\nENABLED: yes\nCERT: |\n  line1\n  line2\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ZIP": "010", "ENABLED": "yes", "CERT": "line1\nline2\n"}, vars)
+
+	_, err = parseVariables(formatYAML, []byte("A:\n  - 1\n"))
+	require.ErrorContains(t, err, "nested values are not supported")
+
+	_, err = parseVariables(formatYAML, []byte("A: ~\n"))
+	require.ErrorContains(t, err, "null values are not supported")
+}
+
+func TestWriteVariablesRoundTrip(t *testing.T) {
+	vars := map[string]string{
+		"PLAIN":  "value",
+		"EMPTY":  "",
+		"SPACES": " padded ",
+		"QUOTES": `say "hi" it's`,
+		"HASH":   "a #b",
+		"CERT":   "-----BEGIN-----\r\nabc\n-----END-----\n",
+		"SLASH":  `C:\path\n`,
+	}
+
+	for _, format := range []fileFormat{formatDotenv, formatJSON, formatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeVariables(&buf, format, vars))
+
+			got, err := parseVariables(format, buf.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, vars, got)
+		})
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	current := []*variablev1.Variable{
+		{Key: "KEEP", Value: "1"},
+		{Key: "CHANGE", Value: "old"},
+		{Key: "SECRET", Value: "", Sensitive: true},
+		{Key: "STALE", Value: "x"},
+	}
+	file := map[string]string{
+		"KEEP":   "1",
+		"CHANGE": "new",
+		"SECRET": "s3cr3t",
+		"NEW":    "n",
+	}
+
+	plan := planImport(current, file, nil, false, false)
+	require.Equal(t, []string{"NEW"}, plan.Add)
+	require.Equal(t, []string{"CHANGE", "SECRET"}, plan.Change)
+	require.Empty(t, plan.Remove)
+	require.Equal(t, 1, plan.Unchanged)
+	require.True(t, plan.existing["SECRET"].Sensitive)
+
+	plan = planImport(current, file, nil, true, false)
+	require.Equal(t, []string{"STALE"}, plan.Remove)
+
+	require.True(t, planImport(current[:1], map[string]string{"KEEP": "1"}, nil, true, false).empty())
+}
+
+func TestPlanImport_PruneSensitive(t *testing.T) {
+	current := []*variablev1.Variable{
+		{Key: "PORT", Value: "8080"},
+		{Key: "TOKEN", Sensitive: true},
+		{Key: "DB_PASSWORD", Sensitive: true},
+	}
+	file := map[string]string{"PORT": "8080"}
+
+	plan := planImport(current, file, []string{"TOKEN"}, true, false)
+	require.Empty(t, plan.Remove)
+	require.Equal(t, []string{"DB_PASSWORD"}, plan.Kept)
+
+	// Placeholder keys are kept even when sensitive variables are pruned.
+	plan = planImport(current, file, []string{"TOKEN"}, true, true)
+	require.Equal(t, []string{"DB_PASSWORD"}, plan.Remove)
+	require.Empty(t, plan.Kept)
+}
+
+func TestExportImportPrune_RoundTrip(t *testing.T) {
+	current := []*variablev1.Variable{
+		{Key: "PORT", Value: "8080"},
+		{Key: "TOKEN", Value: "", Sensitive: true},
+	}
+
+	for _, mode := range []string{sensitiveOmit, sensitivePlaceholder} {
+		t.Run(mode, func(t *testing.T) {
+			values, _ := exportValues(current, mode)
+			var buf bytes.Buffer
+			require.NoError(t, writeVariables(&buf, formatDotenv, values))
+
+			vars, err := parseVariables(formatDotenv, buf.Bytes())
+			require.NoError(t, err)
+			skipped := skipPlaceholders(vars)
+
+			// Re-importing an export with --prune deletes nothing.
+			plan := planImport(current, vars, skipped, true, false)
+			require.True(t, plan.empty())
+			require.Equal(t, 1, plan.Unchanged)
+		})
+	}
+}
+
+func TestExportValues(t *testing.T) {
+	vars := []*variablev1.Variable{
+		{Key: "PORT", Value: "8080"},
+		{Key: "TOKEN", Value: "", Sensitive: true},
+	}
+
+	values, omitted := exportValues(vars, sensitiveOmit)
+	require.Equal(t, map[string]string{"PORT": "8080"}, values)
+	require.Equal(t, []string{"TOKEN"}, omitted)
+
+	values, omitted = exportValues(vars, sensitivePlaceholder)
+	require.Equal(t, map[string]string{"PORT": "8080", "TOKEN": variables.MaskedValue}, values)
+	require.Empty(t, omitted)
+}
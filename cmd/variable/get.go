@@ -1,19 +1,23 @@
 package variable
 
 import (
-	"os"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func newGetCmd(opts *factory.Options) *cobra.Command {
 	var (
 		envFlag    string
 		globalFlag bool
+		reveal     bool
 	)
 
 	cmd := &cobra.Command{
@@ -22,7 +26,9 @@ func newGetCmd(opts *factory.Options) *cobra.Command {
 		Long: `Get a single variable by key.
 
 The app can be provided as a positional argument or resolved from the
-active context set via 'admiral use <app>'. Use --global for global variables.`,
+active context set via 'admiral use <app>'. Use --global for global variables.
+
+Sensitive values are masked unless --reveal is passed.`,
 		Example: `  # Get an app-scoped variable
   admiral variable get my-api IMAGE_TAG
 
@@ -32,6 +38,9 @@ active context set via 'admiral use <app>'. Use --global for global variables.`,
   # Get a global variable
   admiral variable get --global LOG_FORMAT
 
+  # Show a sensitive value
+  admiral variable get my-api DATABASE_PASSWORD --reveal
+
   # Use the active app context
   admiral use my-api
   admiral variable get IMAGE_TAG`,
@@ -52,21 +61,51 @@ active context set via 'admiral use <app>'. Use --global for global variables.`,
 				return err
 			}
 
-			stub := stubResult{
-				Operation:   "get",
-				Scope:       string(rs.Scope),
-				App:         rs.App,
-				Environment: rs.Env,
-				Key:         key,
-				Status:      stubStatus,
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			resp, err := c.Variable().GetVariable(cmd.Context(), &variablev1.GetVariableRequest{
+				Scope:           rs.Scope.proto(),
+				ApplicationId:   rs.App,
+				EnvironmentId:   rs.Env,
+				Key:             key,
+				RevealSensitive: reveal,
+			})
+			if err != nil {
+				return err
+			}
+
+			v := resp.Variable
+			maskSensitive([]*variablev1.Variable{v}, reveal)
+			p := output.NewPrinter(opts.OutputFormat)
+
+			sections := []output.Section{
+				{
+					Details: []output.Detail{
+						{Key: "Key", Value: v.Key},
+						{Key: "Value", Value: v.Value},
+						{Key: "Scope", Value: formatScope(v.Scope)},
+						{Key: "App", Value: v.ApplicationId},
+						{Key: "Environment", Value: v.EnvironmentId},
+						{Key: "Sensitive", Value: fmt.Sprintf("%t", v.Sensitive)},
+						{Key: "Type", Value: variables.FormatType(v.Type)},
+						{Key: "Pattern", Value: formatOptional(v.Pattern)},
+						{Key: "Created", Value: output.FormatTimestamp(v.CreatedAt)},
+						{Key: "Updated", Value: output.FormatTimestamp(v.UpdatedAt)},
+					},
+				},
 			}
 
-			return printStub(os.Stdout, opts.OutputFormat, stub)
+			return p.PrintDetail(resp, sections)
 		},
 	}
 
 	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
 	cmd.Flags().BoolVar(&globalFlag, "global", false, "get variable at global scope")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "show the value of a sensitive variable")
 
 	return cmd
 }
@@ -0,0 +1,348 @@
+package variable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func newImportCmd(opts *factory.Options) *cobra.Command {
+	var (
+		envFlag        string
+		globalFlag     bool
+		file           string
+		format         string
+		prune          bool
+		pruneSensitive bool
+		dryRun         bool
+		sensitive      bool
+		confirm        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import [app] --file FILE",
+		Short: "Import variables from a file",
+		Long: `Import variables from a .env, JSON or YAML file.
+
+The format is inferred from the file extension (.json, .yaml/.yml, anything
+else is read as dotenv) unless --format is given. Use --file - to read from
+stdin. JSON and YAML files must be a flat mapping of keys to scalar values.
+
+Keys in the file are added or updated in the target scope; other keys are
+left alone unless --prune is given, which deletes them. Sensitive variables
+missing from the file are only deleted with --prune-sensitive as well, since
+'variable export' leaves them out by default. The added, changed and removed
+keys are printed before anything is applied. Use --dry-run to print them
+without applying.
+
+Values equal to the placeholder written by 'variable export' for sensitive
+variables are skipped and never pruned, so an exported file can be
+re-imported safely.
+Existing variables keep their sensitivity, type and pattern, and new values
+are checked against the type and pattern before anything is applied.`,
+		Example: `  # Import a .env file into an environment
+  admiral variable import my-api -e staging --file .env
+
+  # Preview an import that also deletes keys missing from the file
+  admiral variable import my-api -e staging --file vars.yaml --prune --dry-run
+
+  # Import global variables (with confirmation)
+  admiral variable import --global --file globals.json --confirm`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				_ = cmd.Help()
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr())
+				return fmt.Errorf("--file is required")
+			}
+			if pruneSensitive && !prune {
+				return fmt.Errorf("--prune-sensitive requires --prune")
+			}
+
+			ff, err := detectFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			data, err := readFileOrStdin(cmd.InOrStdin(), file)
+			if err != nil {
+				return err
+			}
+
+			vars, err := parseVariables(ff, data)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+
+			skipped := skipPlaceholders(vars)
+			for _, k := range skipped {
+				output.Writef(cmd.ErrOrStderr(), "Skipping %s: value is the sensitive placeholder\n", k)
+			}
+
+			var appArg string
+			if len(args) == 1 {
+				appArg = args[0]
+			}
+
+			props, err := properties.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			rs, err := resolveScopeWithHelp(cmd, globalFlag, envFlag, appArg, props.App)
+			if err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			current, err := variables.ListAll(cmd.Context(), c.Variable(), &variablev1.ListVariablesRequest{
+				Scope:         rs.Scope.proto(),
+				ApplicationId: rs.App,
+				EnvironmentId: rs.Env,
+			})
+			if err != nil {
+				return err
+			}
+
+			plan := planImport(current, vars, skipped, prune, pruneSensitive)
+			if err := plan.validate(vars); err != nil {
+				return err
+			}
+			if len(plan.Kept) > 0 {
+				output.Writef(cmd.ErrOrStderr(), "Keeping %d sensitive variable(s) not in the file: %v; use --prune-sensitive to delete them\n",
+					len(plan.Kept), plan.Kept)
+			}
+
+			p := output.NewPrinter(opts.OutputFormat)
+			p.Out = cmd.OutOrStdout()
+			if err := p.PrintObject(plan, func(w *tabwriter.Writer) {
+				printImportPlan(w, plan)
+			}); err != nil {
+				return err
+			}
+
+			if dryRun || plan.empty() {
+				return nil
+			}
+
+			// GLOBAL scope requires confirmation.
+			if rs.Scope == scopeGlobal && !confirm {
+				ok, err := cmdutil.ConfirmPrompt(
+					cmd.InOrStdin(), cmd.ErrOrStderr(),
+					"Importing GLOBAL variables affects all apps. Continue?",
+				)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					cmdutil.Writef(cmd.ErrOrStderr(), "Aborted.\n")
+					return nil
+				}
+			}
+
+			if err := applyImport(cmd.Context(), c, rs, plan, vars, sensitive); err != nil {
+				return err
+			}
+
+			output.Writef(cmd.ErrOrStderr(), "Imported %d added, %d changed, %d removed.\n",
+				len(plan.Add), len(plan.Change), len(plan.Remove))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
+	cmd.Flags().BoolVar(&globalFlag, "global", false, "import variables at global scope")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "file to import, or - for stdin (required)")
+	cmd.Flags().StringVar(&format, "format", "", "file format: dotenv, json or yaml (default: from extension)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "delete variables in the scope that are not in the file")
+	cmd.Flags().BoolVar(&pruneSensitive, "prune-sensitive", false, "with --prune, also delete sensitive variables that are not in the file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes without applying them")
+	cmd.Flags().BoolVar(&sensitive, "sensitive", false, "mark imported variables as sensitive")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "skip confirmation prompt")
+
+	return cmd
+}
+
+// readFileOrStdin reads path, or r if path is "-".
+func readFileOrStdin(r io.Reader, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(r)
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // path is supplied by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// skipPlaceholders removes the keys whose value is the sensitive placeholder
+// written by 'variable export' from vars, and returns them sorted.
+func skipPlaceholders(vars map[string]string) []string {
+	var skipped []string
+	for k, v := range vars {
+		if v == variables.MaskedValue {
+			skipped = append(skipped, k)
+			delete(vars, k)
+		}
+	}
+	slices.Sort(skipped)
+	return skipped
+}
+
+// importPlan lists the keys an import adds, changes and removes, each sorted.
+type importPlan struct {
+	Add       []string `json:"add,omitempty" yaml:"add,omitempty"`
+	Change    []string `json:"change,omitempty" yaml:"change,omitempty"`
+	Remove    []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+	Unchanged int      `json:"unchanged" yaml:"unchanged"`
+
+	// Kept lists the sensitive keys missing from the file that a prune left
+	// in place because sensitive variables weren't to be pruned.
+	Kept []string `json:"kept,omitempty" yaml:"kept,omitempty"`
+
+	// existing holds the variables already in the scope, so updates keep
+	// their sensitivity, type and pattern.
+	existing map[string]*variablev1.Variable
+}
+
+func (p *importPlan) empty() bool {
+	return len(p.Add) == 0 && len(p.Change) == 0 && len(p.Remove) == 0
+}
+
+// planImport compares the variables in a scope with those read from a file.
+// Sensitive values aren't returned by the API, so existing sensitive keys
+// in the file always count as changed. With prune, keys missing from the
+// file are removed, except those in keep and, unless pruneSensitive is set,
+// sensitive ones.
+func planImport(current []*variablev1.Variable, file map[string]string, keep []string, prune, pruneSensitive bool) *importPlan {
+	existing := make(map[string]*variablev1.Variable, len(current))
+	for _, v := range current {
+		existing[v.Key] = v
+	}
+	plan := &importPlan{existing: existing}
+
+	for k, v := range file {
+		cur, ok := existing[k]
+		switch {
+		case !ok:
+			plan.Add = append(plan.Add, k)
+		case cur.Sensitive || cur.Value != v:
+			plan.Change = append(plan.Change, k)
+		default:
+			plan.Unchanged++
+		}
+	}
+
+	if prune {
+		for k, cur := range existing {
+			if _, ok := file[k]; ok || slices.Contains(keep, k) {
+				continue
+			}
+			if cur.Sensitive && !pruneSensitive {
+				plan.Kept = append(plan.Kept, k)
+				continue
+			}
+			plan.Remove = append(plan.Remove, k)
+		}
+	}
+
+	slices.Sort(plan.Add)
+	slices.Sort(plan.Change)
+	slices.Sort(plan.Remove)
+	slices.Sort(plan.Kept)
+	return plan
+}
+
+// validate checks the values to add or change against the type and pattern
+// of the existing variables.
+func (p *importPlan) validate(vars map[string]string) error {
+	var errs []error
+	for _, k := range p.Change {
+		cur := p.existing[k]
+		err := variables.Validate(&variablev1.Variable{
+			Key:       k,
+			Value:     vars[k],
+			Type:      cur.Type,
+			Pattern:   cur.Pattern,
+			Sensitive: cur.Sensitive,
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func printImportPlan(w io.Writer, plan *importPlan) {
+	if plan.empty() {
+		output.Writeln(w, "No changes.")
+		return
+	}
+
+	for _, k := range plan.Add {
+		output.Writef(w, "+ %s\n", k)
+	}
+	for _, k := range plan.Change {
+		output.Writef(w, "~ %s\n", k)
+	}
+	for _, k := range plan.Remove {
+		output.Writef(w, "- %s\n", k)
+	}
+	output.Writef(w, "\n%d to add, %d to change, %d to remove, %d unchanged.\n",
+		len(plan.Add), len(plan.Change), len(plan.Remove), plan.Unchanged)
+}
+
+func applyImport(ctx context.Context, c client.AdmiralClient, rs resolvedScope, plan *importPlan, vars map[string]string, sensitive bool) error {
+	for _, k := range slices.Concat(plan.Add, plan.Change) {
+		req := &variablev1.SetVariableRequest{
+			Scope:         rs.Scope.proto(),
+			ApplicationId: rs.App,
+			EnvironmentId: rs.Env,
+			Key:           k,
+			Value:         vars[k],
+			Sensitive:     sensitive,
+		}
+		if cur, ok := plan.existing[k]; ok {
+			req.Sensitive = req.Sensitive || cur.Sensitive
+			req.Type = cur.Type
+			req.Pattern = cur.Pattern
+		}
+		_, err := c.Variable().SetVariable(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %w", k, err)
+		}
+	}
+
+	for _, k := range plan.Remove {
+		_, err := c.Variable().DeleteVariable(ctx, &variablev1.DeleteVariableRequest{
+			Scope:         rs.Scope.proto(),
+			ApplicationId: rs.App,
+			EnvironmentId: rs.Env,
+			Key:           k,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", k, err)
+		}
+	}
+
+	return nil
+}
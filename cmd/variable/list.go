@@ -1,18 +1,26 @@
 package variable
 
 import (
-	"os"
+	"fmt"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func newListCmd(opts *factory.Options) *cobra.Command {
 	var (
 		envFlag    string
 		globalFlag bool
+		effective  bool
+		reveal     bool
+		pageSize   int32
+		pageToken  string
 	)
 
 	cmd := &cobra.Command{
@@ -21,13 +29,23 @@ func newListCmd(opts *factory.Options) *cobra.Command {
 		Long: `List variables for a given scope.
 
 The app can be provided as a positional argument or resolved from the
-active context set via 'admiral use <app>'. Use --global for global variables.`,
+active context set via 'admiral use <app>'. Use --global for global variables.
+
+Use --effective to list the final value of every variable that applies to
+the app (and environment, with -e): global variables are overridden by app
+variables, which are overridden by app+environment variables. The SCOPE
+column shows where each value came from.
+
+Sensitive values are masked unless --reveal is passed.`,
 		Example: `  # List app-scoped variables
   admiral variable list my-api
 
   # List variables for a specific environment
   admiral variable list my-api -e staging
 
+  # List the merged variables that apply to an environment
+  admiral variable list my-api -e staging --effective
+
   # List global variables
   admiral variable list --global
 
@@ -51,20 +69,60 @@ active context set via 'admiral use <app>'. Use --global for global variables.`,
 				return err
 			}
 
-			stub := stubResult{
-				Operation:   "list",
-				Scope:       string(rs.Scope),
-				App:         rs.App,
-				Environment: rs.Env,
-				Status:      stubStatus,
+			if effective && rs.Scope == scopeGlobal {
+				return fmt.Errorf("--effective requires an app; it can't be combined with --global")
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			var resp *variablev1.ListVariablesResponse
+			if effective {
+				vars, err := variables.Effective(cmd.Context(), c.Variable(), rs.App, rs.Env, reveal)
+				if err != nil {
+					return err
+				}
+				resp = &variablev1.ListVariablesResponse{Variables: vars}
+			} else {
+				resp, err = c.Variable().ListVariables(cmd.Context(), &variablev1.ListVariablesRequest{
+					Scope:           rs.Scope.proto(),
+					ApplicationId:   rs.App,
+					EnvironmentId:   rs.Env,
+					PageSize:        pageSize,
+					PageToken:       pageToken,
+					RevealSensitive: reveal,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			maskSensitive(resp.Variables, reveal)
+
+			p := output.NewPrinter(opts.OutputFormat)
+			if err := p.PrintResource(resp, func(w *tabwriter.Writer) {
+				printVariables(w, opts.OutputFormat, resp.Variables)
+			}); err != nil {
+				return err
+			}
+
+			if resp.NextPageToken != "" {
+				output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", resp.NextPageToken)
 			}
 
-			return printStub(os.Stdout, opts.OutputFormat, stub)
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
 	cmd.Flags().BoolVar(&globalFlag, "global", false, "list variables at global scope")
+	cmd.Flags().BoolVar(&effective, "effective", false, "list the merged variables that apply to the app and environment")
+	cmd.Flags().BoolVar(&reveal, "reveal", false, "show the values of sensitive variables")
+	cmd.Flags().Int32Var(&pageSize, "page-size", 50, "maximum number of results per page")
+	cmd.Flags().StringVar(&pageToken, "page-token", "", "pagination token from a previous response")
+	cmd.MarkFlagsMutuallyExclusive("effective", "page-token")
 
 	return cmd
 }
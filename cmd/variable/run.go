@@ -0,0 +1,245 @@
+package variable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// forwardedSignals are relayed from the CLI to the child process.
+var forwardedSignals = []os.Signal{syscall.SIGTERM}
+
+// terminalSignals are caught so admiral outlives the child, but not relayed:
+// the child shares admiral's process group, so the terminal (Ctrl-C, Ctrl-\,
+// hangup) already delivers them to it.
+var terminalSignals = []os.Signal{os.Interrupt, syscall.SIGHUP, syscall.SIGQUIT}
+
+func newRunCmd(opts *factory.Options) *cobra.Command {
+	var (
+		envFlag    string
+		noOverride bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run [app] -- COMMAND [ARGS...]",
+		Short: "Run a command with an environment's variables",
+		Long: `Run a command with the effective variables of an app and environment.
+
+Variables are resolved the same way as 'variable list --effective': global
+variables are overridden by app variables, which are overridden by
+app+environment variables. Sensitive values are included.
+
+The resolved variables are added to the command's environment, replacing
+local variables with the same name. Use --no-override to keep local values
+instead.
+
+A terminate signal sent to admiral is forwarded to the command. Interrupt,
+quit and hangup signals from the terminal reach the command directly;
+admiral waits for it and exits with the command's exit code.`,
+		Example: `  # Run a service with the staging variables
+  admiral variable run my-api -e staging -- go run ./cmd/server
+
+  # Keep locally exported values, e.g. DATABASE_URL pointing at localhost
+  admiral variable run my-api -e staging --no-override -- npm start
+
+  # Use the active app context
+  admiral use my-api
+  admiral variable run -e dev -- env`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return fmt.Errorf("no command given; usage: %s", cmd.UseLine())
+			}
+			if dash > 1 {
+				return fmt.Errorf("expected at most 1 argument before --, got %d", dash)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			var appArg string
+			if dash == 1 {
+				appArg = args[0]
+			}
+			command := args[dash:]
+
+			props, err := properties.Load(opts.ConfigDir)
+			if err != nil {
+				return err
+			}
+
+			rs, err := resolveScopeWithHelp(cmd, false, envFlag, appArg, props.App)
+			if err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			vars, err := variables.Effective(cmd.Context(), c.Variable(), rs.App, rs.Env, true)
+			// Don't hold the connection open for the lifetime of the command.
+			_ = c.Close()
+			if err != nil {
+				return err
+			}
+
+			env := mergeEnv(os.Environ(), vars, !noOverride)
+
+			code, err := runCommand(command, env, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+			if err != nil {
+				return &cmdutil.ExitError{Err: err, Code: code}
+			}
+			if code != 0 {
+				return &cmdutil.ExitError{Code: code}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
+	cmd.Flags().BoolVar(&noOverride, "no-override", false, "keep local environment variables that are also set in Admiral")
+
+	return cmd
+}
+
+// mergeEnv adds vars to base, a list of KEY=VALUE entries as returned by
+// os.Environ. With override, vars replace entries with the same key;
+// otherwise existing entries win.
+func mergeEnv(base []string, vars []*variablev1.Variable, override bool) []string {
+	index := make(map[string]int, len(base))
+	env := make([]string, 0, len(base)+len(vars))
+	for _, kv := range base {
+		k, _, _ := strings.Cut(kv, "=")
+		if i, ok := index[k]; ok {
+			env[i] = kv
+			continue
+		}
+		index[k] = len(env)
+		env = append(env, kv)
+	}
+
+	for _, v := range vars {
+		kv := v.Key + "=" + v.Value
+		if i, ok := index[v.Key]; ok {
+			if override {
+				env[i] = kv
+			}
+			continue
+		}
+		index[v.Key] = len(env)
+		env = append(env, kv)
+	}
+	return env
+}
+
+// runCommand runs command with env and returns its exit code. Signals
+// received meanwhile are forwarded to it. A command that can't be started
+// returns an error with exit code 127, like a shell.
+func runCommand(command []string, env []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	path, err := lookPath(command[0], env)
+	if err != nil {
+		return 127, err
+	}
+
+	child := exec.Command(path, command[1:]...) //nolint:gosec // running the user's command is the point
+	child.Env = env
+	child.Stdin = stdin
+	child.Stdout = stdout
+	child.Stderr = stderr
+
+	// Catch signals before starting so none are lost or kill admiral while
+	// the child is still running.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, slices.Concat(forwardedSignals, terminalSignals)...)
+	defer signal.Stop(sigs)
+
+	if err := child.Start(); err != nil {
+		return 127, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if slices.Contains(forwardedSignals, sig) {
+					_ = child.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err = child.Wait()
+	close(done)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitCode(exitErr), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// lookPath resolves name like exec.LookPath, but searches the PATH set in
+// env, which Admiral variables may override, rather than admiral's own. If
+// env sets no PATH, admiral's is searched. Relative PATH entries are skipped,
+// as exec.LookPath refuses them too.
+func lookPath(name string, env []string) (string, error) {
+	if strings.ContainsAny(name, `/\`) {
+		return exec.LookPath(name)
+	}
+
+	dirs, ok := "", false
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		if k == "PATH" || (runtime.GOOS == "windows" && strings.EqualFold(k, "PATH")) {
+			dirs, ok = v, true
+		}
+	}
+	if !ok {
+		return exec.LookPath(name)
+	}
+
+	for _, dir := range filepath.SplitList(dirs) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		if path, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return path, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// exitCode returns the exit code of a finished process, using the shell
+// convention of 128+N for a process killed by signal N.
+func exitCode(err *exec.ExitError) int {
+	if code := err.ExitCode(); code >= 0 {
+		return code
+	}
+	if ws, ok := err.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return 1
+}
@@ -0,0 +1,101 @@
+package variable
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestMergeEnv(t *testing.T) {
+	base := []string{"PATH=/usr/bin", "LOG_LEVEL=info", "HOME=/home/dev"}
+	vars := []*variablev1.Variable{
+		{Key: "LOG_LEVEL", Value: "debug"},
+		{Key: "PORT", Value: "8080"},
+		{Key: "CERT", Value: "line1\nline2=x"},
+	}
+
+	require.Equal(t, []string{
+		"PATH=/usr/bin",
+		"LOG_LEVEL=debug",
+		"HOME=/home/dev",
+		"PORT=8080",
+		"CERT=line1\nline2=x",
+	}, mergeEnv(base, vars, true))
+
+	require.Equal(t, []string{
+		"PATH=/usr/bin",
+		"LOG_LEVEL=info",
+		"HOME=/home/dev",
+		"PORT=8080",
+		"CERT=line1\nline2=x",
+	}, mergeEnv(base, vars, false))
+}
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	t.Run("passes environment and output", func(t *testing.T) {
+		var stdout bytes.Buffer
+		code, err := runCommand([]string{"sh", "-c", `printf '%s' "$GREETING"`}, []string{"GREETING=hello"}, strings.NewReader(""), &stdout, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+		require.Equal(t, "hello", stdout.String())
+	})
+
+	t.Run("propagates exit code", func(t *testing.T) {
+		code, err := runCommand([]string{"sh", "-c", "exit 3"}, nil, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+	})
+
+	t.Run("killed by signal", func(t *testing.T) {
+		code, err := runCommand([]string{"sh", "-c", "kill -TERM $$"}, nil, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, 143, code)
+	})
+
+	t.Run("command not found", func(t *testing.T) {
+		code, err := runCommand([]string{"admiral-no-such-command"}, nil, strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Equal(t, 127, code)
+	})
+}
+
+func TestLookPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "admiral-test-tool")
+	require.NoError(t, os.WriteFile(tool, []byte("#!/bin/sh\n"), 0o755)) //nolint:gosec // the script must be executable
+
+	// The child's PATH is searched, not admiral's.
+	got, err := lookPath("admiral-test-tool", []string{"PATH=/nonexistent" + string(filepath.ListSeparator) + dir})
+	require.NoError(t, err)
+	require.Equal(t, tool, got)
+
+	t.Setenv("PATH", dir)
+	_, err = lookPath("admiral-test-tool", []string{"PATH=/nonexistent"})
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	// Relative entries are never searched.
+	t.Chdir(dir)
+	_, err = lookPath("admiral-test-tool", []string{"PATH=."})
+	require.ErrorIs(t, err, exec.ErrNotFound)
+
+	// Without a PATH in env, admiral's is used.
+	got, err = lookPath("admiral-test-tool", nil)
+	require.NoError(t, err)
+	require.Equal(t, tool, got)
+}
@@ -1,16 +1,15 @@
 package variable
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 
 	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 // scope represents the resolved variable scope.
@@ -22,6 +21,38 @@ const (
 	scopeAppEnv scope = "APP_ENV"
 )
 
+// proto returns the API enum for s.
+func (s scope) proto() variablev1.VariableScope {
+	switch s {
+	case scopeGlobal:
+		return variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL
+	case scopeApp:
+		return variablev1.VariableScope_VARIABLE_SCOPE_APP
+	case scopeAppEnv:
+		return variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV
+	default:
+		return variablev1.VariableScope_VARIABLE_SCOPE_UNSPECIFIED
+	}
+}
+
+// formatScope returns the display name of an API scope: GLOBAL, APP or
+// APP_ENV.
+func formatScope(s variablev1.VariableScope) string {
+	name := strings.TrimPrefix(s.String(), "VARIABLE_SCOPE_")
+	if name == "UNSPECIFIED" {
+		return "<unknown>"
+	}
+	return name
+}
+
+// formatOptional returns s, or "<none>" if it is empty.
+func formatOptional(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
 // resolvedScope holds the fully resolved scope with optional app and env.
 type resolvedScope struct {
 	Scope scope
@@ -122,64 +153,43 @@ func splitArgsWithKey(args []string) (appArg, key string, err error) {
 	}
 }
 
-// stubResult holds the parameters that would be sent to the API.
-type stubResult struct {
-	Operation   string            `json:"operation" yaml:"operation"`
-	Scope       string            `json:"scope" yaml:"scope"`
-	App         string            `json:"app,omitempty" yaml:"app,omitempty"`
-	Environment string            `json:"environment,omitempty" yaml:"environment,omitempty"`
-	Key         string            `json:"key,omitempty" yaml:"key,omitempty"`
-	Variables   map[string]string `json:"variables,omitempty" yaml:"variables,omitempty"`
-	Sensitive   bool              `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
-	Status      string            `json:"status" yaml:"status"`
-}
-
-const stubStatus = "not yet implemented (proto/gRPC pending)"
-
-func printStub(w io.Writer, format output.Format, stub stubResult) error {
-	switch format {
-	case output.FormatJSON:
-		enc := json.NewEncoder(w)
-		enc.SetIndent("", "  ")
-		return enc.Encode(stub)
-	case output.FormatYAML:
-		return yaml.NewEncoder(w).Encode(stub)
-	case output.FormatTable, output.FormatWide:
-		return printStubTable(w, stub)
-	default:
-		return fmt.Errorf("unsupported format: %s", format)
+// maskSensitive replaces the values of sensitive variables with a
+// placeholder unless reveal is set, so they are hidden in every output
+// format.
+func maskSensitive(vars []*variablev1.Variable, reveal bool) {
+	for _, v := range vars {
+		v.Value = variables.DisplayValue(v, reveal)
 	}
 }
 
-func printStubTable(out io.Writer, stub stubResult) error {
-	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
-
-	output.Writef(w, "Operation:\t%s\n", stub.Operation)
-	output.Writef(w, "Scope:\t%s\n", stub.Scope)
-
-	if stub.App != "" {
-		output.Writef(w, "App:\t%s\n", stub.App)
-	}
-	if stub.Environment != "" {
-		output.Writef(w, "Environment:\t%s\n", stub.Environment)
-	}
-
-	if stub.Key != "" {
-		output.Writef(w, "Key:\t%s\n", stub.Key)
-	}
-
-	if len(stub.Variables) > 0 {
-		output.Writeln(w, "Variables:")
-		for k, v := range stub.Variables {
-			output.Writef(w, "  %s\t= %s\n", k, v)
+// printVariables writes the table view of vars.
+func printVariables(w *tabwriter.Writer, format output.Format, vars []*variablev1.Variable) {
+	if format == output.FormatWide {
+		output.Writeln(w, "KEY\tVALUE\tSCOPE\tAGE\tTYPE\tSENSITIVE\tAPP\tENVIRONMENT\tCREATED\tUPDATED")
+		for _, v := range vars {
+			output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\t%s\t%s\t%s\n",
+				v.Key,
+				v.Value,
+				formatScope(v.Scope),
+				output.FormatAge(v.CreatedAt),
+				variables.FormatType(v.Type),
+				v.Sensitive,
+				v.ApplicationId,
+				v.EnvironmentId,
+				output.FormatTimestamp(v.CreatedAt),
+				output.FormatTimestamp(v.UpdatedAt),
+			)
 		}
+		return
 	}
 
-	if stub.Sensitive {
-		output.Writef(w, "Sensitive:\ttrue\n")
+	output.Writeln(w, "KEY\tVALUE\tSCOPE\tAGE")
+	for _, v := range vars {
+		output.Writef(w, "%s\t%s\t%s\t%s\n",
+			v.Key,
+			v.Value,
+			formatScope(v.Scope),
+			output.FormatAge(v.CreatedAt),
+		)
 	}
-
-	output.Writef(w, "\nStatus:\t%s\n", stub.Status)
-
-	return w.Flush()
 }
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func TestResolveScope(t *testing.T) {
@@ -210,3 +213,24 @@ func TestParseKV(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatScope(t *testing.T) {
+	require.Equal(t, "GLOBAL", formatScope(scopeGlobal.proto()))
+	require.Equal(t, "APP", formatScope(scopeApp.proto()))
+	require.Equal(t, "APP_ENV", formatScope(scopeAppEnv.proto()))
+	require.Equal(t, "<unknown>", formatScope(variablev1.VariableScope_VARIABLE_SCOPE_UNSPECIFIED))
+}
+
+func TestMaskSensitive(t *testing.T) {
+	vars := []*variablev1.Variable{
+		{Key: "PORT", Value: "8080"},
+		{Key: "TOKEN", Value: "s3cr3t", Sensitive: true},
+	}
+
+	maskSensitive(vars, true)
+	require.Equal(t, "s3cr3t", vars[1].Value)
+
+	maskSensitive(vars, false)
+	require.Equal(t, "8080", vars[0].Value)
+	require.Equal(t, variables.MaskedValue, vars[1].Value)
+}
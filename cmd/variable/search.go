@@ -0,0 +1,306 @@
+package variable
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"go.admiral.io/cli/internal/cmdutil"
+	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// searchPageSize is the page size used when walking apps and environments.
+const searchPageSize = 100
+
+func newSearchCmd(opts *factory.Options) *cobra.Command {
+	var (
+		valuePattern    string
+		searchSensitive bool
+		concurrency     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search KEY-OR-REGEX",
+		Short: "Find variables across all apps",
+		Long: `Find variables by key across every app and environment.
+
+The argument is a key or a regular expression that must match the whole key,
+e.g. DATABASE_URL or 'DB_.*'. Global, app and app+environment variables are
+searched in every app visible to the current user.
+
+Use --value-pattern to only report variables whose value contains a match
+for a regular expression, e.g. an old hostname. Sensitive values are not
+searched unless --search-sensitive is given, and are always masked in the
+output.
+
+Apps are searched in parallel, up to --concurrency at a time.`,
+		Example: `  # Which apps set DATABASE_URL?
+  admiral variable search DATABASE_URL
+
+  # Where does the old database host still appear?
+  admiral variable search '.*' --value-pattern 'db-old\.internal'
+
+  # Include sensitive values in the value search
+  admiral variable search 'DB_.*' --value-pattern 'db-old' --search-sensitive`,
+		Args: cmdutil.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+
+			q, err := newSearchQuery(args[0], valuePattern, searchSensitive)
+			if err != nil {
+				return err
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			found, err := searchVariables(cmd.Context(), c, q, concurrency)
+			if err != nil {
+				return err
+			}
+			maskSensitive(found, false)
+
+			resp := &variablev1.ListVariablesResponse{Variables: found}
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(resp, func(w *tabwriter.Writer) {
+				printSearchResults(w, opts.OutputFormat, found)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&valuePattern, "value-pattern", "", "only report variables whose value matches this regular expression")
+	cmd.Flags().BoolVar(&searchSensitive, "search-sensitive", false, "match --value-pattern against sensitive values too")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 8, "maximum number of apps searched at once")
+
+	return cmd
+}
+
+// searchQuery matches variables by key and, optionally, value.
+type searchQuery struct {
+	key   *regexp.Regexp
+	value *regexp.Regexp
+	// sensitive allows value matches against sensitive variables.
+	sensitive bool
+}
+
+func newSearchQuery(key, value string, sensitive bool) (*searchQuery, error) {
+	q := &searchQuery{sensitive: sensitive}
+
+	var err error
+	if q.key, err = variables.CompilePattern(key); err != nil {
+		return nil, err
+	}
+	if value != "" {
+		if q.value, err = regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("invalid --value-pattern %q: %w", value, err)
+		}
+	}
+	return q, nil
+}
+
+// reveal reports whether values must be fetched unmasked.
+func (q *searchQuery) reveal() bool {
+	return q.value != nil && q.sensitive
+}
+
+func (q *searchQuery) match(v *variablev1.Variable) bool {
+	if !q.key.MatchString(v.Key) {
+		return false
+	}
+	if q.value == nil {
+		return true
+	}
+	if v.Sensitive && !q.sensitive {
+		return false
+	}
+	return q.value.MatchString(v.Value)
+}
+
+// searchVariables collects the variables matching q in the global scope and
+// in every app and environment, searching up to concurrency apps at once.
+// The first error cancels the search.
+func searchVariables(ctx context.Context, c client.AdmiralClient, q *searchQuery, concurrency int) ([]*variablev1.Variable, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		found    []*variablev1.Variable
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	collect := func(req *variablev1.ListVariablesRequest) error {
+		req.RevealSensitive = q.reveal()
+		vars, err := variables.ListAll(ctx, c.Variable(), req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, v := range vars {
+			// Don't rely on the server to echo back the scope.
+			if v.ApplicationId == "" {
+				v.ApplicationId = req.ApplicationId
+			}
+			if v.EnvironmentId == "" {
+				v.EnvironmentId = req.EnvironmentId
+			}
+			if q.match(v) {
+				found = append(found, v)
+			}
+		}
+		return nil
+	}
+
+	if err := collect(&variablev1.ListVariablesRequest{Scope: variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL}); err != nil {
+		return nil, err
+	}
+
+	sem := make(chan struct{}, concurrency)
+	req := &applicationv1.ListApplicationsRequest{PageSize: searchPageSize}
+	for ctx.Err() == nil {
+		resp, err := c.Application().ListApplications(ctx, req)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		for _, app := range resp.Applications {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := searchApp(ctx, c, app.Name, collect); err != nil {
+					fail(fmt.Errorf("searching %s: %w", app.Name, err))
+				}
+			}()
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sortSearchResults(found)
+	return found, nil
+}
+
+// searchApp collects the app's variables and those of each of its
+// environments.
+func searchApp(ctx context.Context, c client.AdmiralClient, app string, collect func(*variablev1.ListVariablesRequest) error) error {
+	err := collect(&variablev1.ListVariablesRequest{
+		Scope:         variablev1.VariableScope_VARIABLE_SCOPE_APP,
+		ApplicationId: app,
+	})
+	if err != nil {
+		return err
+	}
+
+	req := &environmentv1.ListEnvironmentsRequest{ApplicationId: app, PageSize: searchPageSize}
+	for {
+		resp, err := c.Environment().ListEnvironments(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, env := range resp.Environments {
+			err := collect(&variablev1.ListVariablesRequest{
+				Scope:         variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV,
+				ApplicationId: app,
+				EnvironmentId: env.Name,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// sortSearchResults orders results by app, environment and key, with global
+// variables first.
+func sortSearchResults(vars []*variablev1.Variable) {
+	slices.SortFunc(vars, func(a, b *variablev1.Variable) int {
+		return cmp.Or(
+			cmp.Compare(a.ApplicationId, b.ApplicationId),
+			cmp.Compare(a.EnvironmentId, b.EnvironmentId),
+			cmp.Compare(a.Key, b.Key),
+		)
+	})
+}
+
+// printSearchResults writes the table view of search results.
+func printSearchResults(w *tabwriter.Writer, format output.Format, vars []*variablev1.Variable) {
+	if format == output.FormatWide {
+		output.Writeln(w, "APP\tENVIRONMENT\tSCOPE\tKEY\tVALUE\tTYPE\tSENSITIVE\tUPDATED")
+		for _, v := range vars {
+			output.Writef(w, "%s\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+				formatOptional(v.ApplicationId),
+				formatOptional(v.EnvironmentId),
+				formatScope(v.Scope),
+				v.Key,
+				v.Value,
+				variables.FormatType(v.Type),
+				v.Sensitive,
+				output.FormatTimestamp(v.UpdatedAt),
+			)
+		}
+		return
+	}
+
+	output.Writeln(w, "APP\tENVIRONMENT\tSCOPE\tKEY\tVALUE")
+	for _, v := range vars {
+		output.Writef(w, "%s\t%s\t%s\t%s\t%s\n",
+			formatOptional(v.ApplicationId),
+			formatOptional(v.EnvironmentId),
+			formatScope(v.Scope),
+			v.Key,
+			v.Value,
+		)
+	}
+}
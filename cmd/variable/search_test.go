@@ -0,0 +1,54 @@
+package variable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestSearchQuery(t *testing.T) {
+	dbURL := &variablev1.Variable{Key: "DATABASE_URL", Value: "postgres://db-old.internal/app"}
+	secret := &variablev1.Variable{Key: "DATABASE_PASSWORD", Value: "db-old", Sensitive: true}
+	other := &variablev1.Variable{Key: "OLD_DATABASE_URL", Value: "postgres://db-old.internal/app"}
+
+	q, err := newSearchQuery("DATABASE_URL", "", false)
+	require.NoError(t, err)
+	require.True(t, q.match(dbURL))
+	require.False(t, q.match(other), "key patterns match the whole key")
+	require.False(t, q.reveal())
+
+	q, err = newSearchQuery("DATABASE_.*", `db-old\.`, false)
+	require.NoError(t, err)
+	require.True(t, q.match(dbURL))
+	require.False(t, q.match(secret), "sensitive values aren't searched by default")
+	require.False(t, q.reveal())
+
+	q, err = newSearchQuery("DATABASE_.*", "db-old", true)
+	require.NoError(t, err)
+	require.True(t, q.match(secret))
+	require.True(t, q.reveal())
+
+	_, err = newSearchQuery("(", "", false)
+	require.ErrorContains(t, err, "invalid pattern")
+	_, err = newSearchQuery("A", "[", false)
+	require.ErrorContains(t, err, "invalid --value-pattern")
+}
+
+func TestSortSearchResults(t *testing.T) {
+	vars := []*variablev1.Variable{
+		{Key: "B", ApplicationId: "billing", EnvironmentId: "prod"},
+		{Key: "A", ApplicationId: "billing", EnvironmentId: "prod"},
+		{Key: "Z", ApplicationId: "billing"},
+		{Key: "Z"},
+		{Key: "A", ApplicationId: "api", EnvironmentId: "dev"},
+	}
+	sortSearchResults(vars)
+
+	var got []string
+	for _, v := range vars {
+		got = append(got, v.ApplicationId+"/"+v.EnvironmentId+"/"+v.Key)
+	}
+	require.Equal(t, []string{"//Z", "api/dev/A", "billing//Z", "billing/prod/A", "billing/prod/B"}, got)
+}
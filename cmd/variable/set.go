@@ -1,21 +1,29 @@
 package variable
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"slices"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"go.admiral.io/cli/internal/cmdutil"
 	"go.admiral.io/cli/internal/factory"
+	"go.admiral.io/cli/internal/output"
 	"go.admiral.io/cli/internal/properties"
+	"go.admiral.io/cli/internal/variables"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
 )
 
 func newSetCmd(opts *factory.Options) *cobra.Command {
 	var (
-		envFlag    string
-		globalFlag bool
-		sensitive  bool
-		confirm    bool
+		envFlag     string
+		globalFlag  bool
+		sensitive   bool
+		typeFlag    string
+		patternFlag string
+		confirm     bool
 	)
 
 	cmd := &cobra.Command{
@@ -31,13 +39,49 @@ Scope is inferred automatically:
   --global               → GLOBAL (all apps, requires --confirm or prompt)
   <app> -e <env> K=V     → APP_ENV (app + environment)
   <app> K=V              → APP (app-scoped)
-  K=V (with context set) → APP (from active context)`,
+  K=V (with context set) → APP (from active context)
+
+To keep values out of shell history and process listings, a value can be
+read from elsewhere:
+  KEY=@path   the contents of a file, byte-for-byte (e.g. a PEM certificate)
+  KEY=-       all of stdin, byte-for-byte (one variable per command)
+  KEY=        with --sensitive, prompt for the value without echoing it
+Use KEY=@@value for a literal value starting with @.
+
+Values are stored exactly as read, including any trailing newline.
+
+Use --type to declare what a variable holds and --pattern to constrain it
+with a regular expression that must match the whole value. Values are
+checked before anything is sent:
+  string    any value (the default)
+  int       a base-10 integer, e.g. 3 or -1
+  bool      true or false
+  duration  a Go duration, e.g. 30s or 1h30m
+  url       an absolute URL, e.g. https://example.com/path
+  json      any valid JSON document
+A variable keeps its type and pattern when it is set again without these
+flags, and the new value is checked against them.`,
 		Example: `  # Set an app-scoped variable (app as argument)
   admiral variable set my-api IMAGE_TAG=v2.0.0
 
   # Set multiple variables for an app+environment
   admiral variable set my-api -e staging IMAGE_TAG=v2.0.0 LOG_LEVEL=debug
 
+  # Store a certificate from a file as a sensitive variable
+  admiral variable set my-api -e production TLS_CERT=@./tls.crt --sensitive
+
+  # Read a secret from stdin
+  vault kv get -field=password secret/db | admiral variable set my-api DB_PASSWORD=- --sensitive
+
+  # Prompt for a secret without echoing it
+  admiral variable set my-api DB_PASSWORD= --sensitive
+
+  # Declare a typed variable; later values such as REPLICAS=three are rejected
+  admiral variable set my-api REPLICAS=3 --type int
+
+  # Constrain a value with a pattern
+  admiral variable set my-api LOG_LEVEL=info --pattern 'debug|info|warn|error'
+
   # Set a global variable (with confirmation)
   admiral variable set --global LOG_FORMAT=json --confirm
 
@@ -56,14 +100,21 @@ Scope is inferred automatically:
 			}
 
 			// Parse all KEY=VALUE pairs.
-			vars := make(map[string]string, len(kvPairs))
+			raw := make(map[string]string, len(kvPairs))
+			keys := make([]string, 0, len(kvPairs))
+			fromStdin := false
 			for _, kv := range kvPairs {
 				k, v, err := parseKV(kv)
 				if err != nil {
 					return err
 				}
-				vars[k] = v
+				if _, ok := raw[k]; !ok {
+					keys = append(keys, k)
+				}
+				raw[k] = v
+				fromStdin = fromStdin || v == "-"
 			}
+			slices.Sort(keys)
 
 			// Load context app.
 			props, err := properties.Load(opts.ConfigDir)
@@ -76,6 +127,60 @@ Scope is inferred automatically:
 				return err
 			}
 
+			// The confirmation prompt reads stdin too.
+			if rs.Scope == scopeGlobal && !confirm && fromStdin {
+				return errors.New("--confirm is required to read a global variable's value from stdin")
+			}
+
+			values := &valueReader{
+				stdin:  cmd.InOrStdin(),
+				hidden: sensitive,
+				prompt: func(key string) (string, error) {
+					v, err := cmdutil.SecretPrompt(cmd.InOrStdin(), cmd.ErrOrStderr(), fmt.Sprintf("Value for %s: ", key))
+					if errors.Is(err, cmdutil.ErrNotTerminal) {
+						return "", fmt.Errorf("stdin is not a terminal; pass %s=@FILE or %s=- instead", key, key)
+					}
+					return v, err
+				},
+			}
+			vars := make(map[string]string, len(keys))
+			for _, k := range keys {
+				v, err := values.resolve(k, raw[k])
+				if err != nil {
+					return err
+				}
+				vars[k] = v
+			}
+
+			c, err := factory.CreateClient(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			defer c.Close() //nolint:errcheck // best-effort cleanup
+
+			// Variables keep their sensitivity, and their type and pattern
+			// unless the flags change them; new values are checked against
+			// either.
+			current, err := variables.ListAll(cmd.Context(), c.Variable(), &variablev1.ListVariablesRequest{
+				Scope:         rs.Scope.proto(),
+				ApplicationId: rs.App,
+				EnvironmentId: rs.Env,
+			})
+			if err != nil {
+				return err
+			}
+			cons := constraints{pattern: patternFlag, setPattern: cmd.Flags().Changed("pattern")}
+			if cmd.Flags().Changed("type") {
+				cons.setType = true
+				if cons.typ, err = variables.ParseType(typeFlag); err != nil {
+					return err
+				}
+			}
+			updates, err := buildUpdates(keys, vars, current, cons, sensitive)
+			if err != nil {
+				return err
+			}
+
 			// GLOBAL scope requires confirmation.
 			if rs.Scope == scopeGlobal && !confirm {
 				ok, err := cmdutil.ConfirmPrompt(
@@ -91,24 +196,86 @@ Scope is inferred automatically:
 				}
 			}
 
-			stub := stubResult{
-				Operation:   "set",
-				Scope:       string(rs.Scope),
-				App:         rs.App,
-				Environment: rs.Env,
-				Variables:   vars,
-				Sensitive:   sensitive,
-				Status:      stubStatus,
+			result := &variablev1.ListVariablesResponse{}
+			for _, v := range updates {
+				resp, err := c.Variable().SetVariable(cmd.Context(), &variablev1.SetVariableRequest{
+					Scope:         rs.Scope.proto(),
+					ApplicationId: rs.App,
+					EnvironmentId: rs.Env,
+					Key:           v.Key,
+					Value:         v.Value,
+					Sensitive:     v.Sensitive,
+					Type:          v.Type,
+					Pattern:       v.Pattern,
+				})
+				if err != nil {
+					return err
+				}
+				result.Variables = append(result.Variables, resp.Variable)
 			}
+			maskSensitive(result.Variables, false)
 
-			return printStub(os.Stdout, opts.OutputFormat, stub)
+			p := output.NewPrinter(opts.OutputFormat)
+			return p.PrintResource(result, func(w *tabwriter.Writer) {
+				printVariables(w, opts.OutputFormat, result.Variables)
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&envFlag, "env", "e", "", "target environment")
 	cmd.Flags().BoolVar(&globalFlag, "global", false, "set variables at global scope")
 	cmd.Flags().BoolVar(&sensitive, "sensitive", false, "mark variables as sensitive")
+	cmd.Flags().StringVar(&typeFlag, "type", "", "value type: string, int, bool, duration, url or json")
+	cmd.Flags().StringVar(&patternFlag, "pattern", "", "regular expression the whole value must match (\"\" removes it)")
 	cmd.Flags().BoolVar(&confirm, "confirm", false, "skip confirmation prompt")
 
 	return cmd
 }
+
+// constraints holds the --type and --pattern flags; the set fields report
+// whether each was given.
+type constraints struct {
+	typ        variablev1.VariableType
+	pattern    string
+	setType    bool
+	setPattern bool
+}
+
+// buildUpdates returns the variables to set for keys, sorted as given. Each
+// takes its type and pattern from cons if set, otherwise from the matching
+// variable in current, and its value is validated against them.
+func buildUpdates(keys []string, vars map[string]string, current []*variablev1.Variable, cons constraints, sensitive bool) ([]*variablev1.Variable, error) {
+	if cons.setPattern && cons.pattern != "" {
+		if _, err := variables.CompilePattern(cons.pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	existing := make(map[string]*variablev1.Variable, len(current))
+	for _, v := range current {
+		existing[v.Key] = v
+	}
+
+	updates := make([]*variablev1.Variable, 0, len(keys))
+	var errs []error
+	for _, k := range keys {
+		v := &variablev1.Variable{Key: k, Value: vars[k], Type: cons.typ, Pattern: cons.pattern, Sensitive: sensitive}
+		if cur, ok := existing[k]; ok {
+			if !cons.setType {
+				v.Type = cur.Type
+			}
+			if !cons.setPattern {
+				v.Pattern = cur.Pattern
+			}
+			v.Sensitive = v.Sensitive || cur.Sensitive
+		}
+		if err := variables.Validate(v); err != nil {
+			errs = append(errs, err)
+		}
+		updates = append(updates, v)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return updates, nil
+}
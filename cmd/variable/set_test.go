@@ -0,0 +1,53 @@
+package variable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestBuildUpdates(t *testing.T) {
+	current := []*variablev1.Variable{
+		{Key: "REPLICAS", Value: "2", Type: variablev1.VariableType_VARIABLE_TYPE_INT},
+		{Key: "LEVEL", Value: "info", Pattern: "debug|info"},
+	}
+	keys := []string{"LEVEL", "NEW", "REPLICAS"}
+
+	t.Run("keeps existing constraints", func(t *testing.T) {
+		got, err := buildUpdates(keys, map[string]string{"LEVEL": "debug", "NEW": "x", "REPLICAS": "3"}, current, constraints{}, false)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, "debug|info", got[0].Pattern)
+		require.Equal(t, variablev1.VariableType_VARIABLE_TYPE_UNSPECIFIED, got[1].Type)
+		require.Equal(t, variablev1.VariableType_VARIABLE_TYPE_INT, got[2].Type)
+	})
+
+	t.Run("keeps sensitivity", func(t *testing.T) {
+		secret := []*variablev1.Variable{{Key: "TOKEN", Sensitive: true}}
+		got, err := buildUpdates([]string{"TOKEN", "NEW"}, map[string]string{"TOKEN": "t", "NEW": "x"}, secret, constraints{}, false)
+		require.NoError(t, err)
+		require.True(t, got[0].Sensitive)
+		require.False(t, got[1].Sensitive)
+	})
+
+	t.Run("reports every invalid value", func(t *testing.T) {
+		_, err := buildUpdates(keys, map[string]string{"LEVEL": "trace", "NEW": "x", "REPLICAS": "three"}, current, constraints{}, false)
+		require.ErrorContains(t, err, `LEVEL: "trace" does not match`)
+		require.ErrorContains(t, err, `REPLICAS: "three" is not an int`)
+	})
+
+	t.Run("flags override", func(t *testing.T) {
+		cons := constraints{typ: variablev1.VariableType_VARIABLE_TYPE_STRING, setType: true, setPattern: true}
+		got, err := buildUpdates([]string{"REPLICAS"}, map[string]string{"REPLICAS": "three"}, current, cons, false)
+		require.NoError(t, err)
+		require.Equal(t, variablev1.VariableType_VARIABLE_TYPE_STRING, got[0].Type)
+		require.Empty(t, got[0].Pattern)
+	})
+
+	t.Run("invalid pattern flag", func(t *testing.T) {
+		_, err := buildUpdates(keys, map[string]string{}, nil, constraints{pattern: "[", setPattern: true}, false)
+		require.ErrorContains(t, err, "invalid pattern")
+	})
+}
@@ -0,0 +1,69 @@
+package variable
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// valueReader resolves the VALUE half of KEY=VALUE arguments, which may
+// name a file or stdin instead of holding the value itself so secrets stay
+// out of shell history and process listings.
+type valueReader struct {
+	// stdin is read for KEY=- values.
+	stdin io.Reader
+	// prompt asks for a value without echoing it. It is used for KEY= (an
+	// empty value) when hidden is set; nil disables prompting.
+	prompt func(key string) (string, error)
+	hidden bool
+
+	stdinUsedBy string
+}
+
+// resolve returns the value for key from raw:
+//
+//	@path  the contents of the file at path, byte-for-byte
+//	-      all of stdin, byte-for-byte (once per command)
+//	@@...  a literal value starting with @
+//	empty  a hidden prompt, if enabled
+//
+// Anything else is the value itself.
+func (r *valueReader) resolve(key, raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "@@"):
+		return raw[1:], nil
+	case strings.HasPrefix(raw, "@"):
+		path := raw[1:]
+		if path == "" {
+			return "", fmt.Errorf("invalid value for %s: expected a file path after @", key)
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // path is supplied by the user on purpose
+		if err != nil {
+			return "", fmt.Errorf("reading value for %s: %w", key, err)
+		}
+		return string(data), nil
+	case raw == "-":
+		if r.stdinUsedBy != "" {
+			return "", fmt.Errorf("only one value can be read from stdin, already used by %s", r.stdinUsedBy)
+		}
+		r.stdinUsedBy = key
+		data, err := io.ReadAll(r.stdin)
+		if err != nil {
+			return "", fmt.Errorf("reading value for %s from stdin: %w", key, err)
+		}
+		return string(data), nil
+	case raw == "" && r.hidden && r.prompt != nil:
+		v, err := r.prompt(key)
+		if err != nil {
+			return "", fmt.Errorf("reading value for %s: %w", key, err)
+		}
+		if v == "" {
+			return "", errors.New("no value entered for " + key)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
@@ -0,0 +1,69 @@
+package variable
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueReader(t *testing.T) {
+	pem := "-----BEGIN CERTIFICATE-----\r\nMIIB\x00yz==\n-----END CERTIFICATE-----\n"
+	path := filepath.Join(t.TempDir(), "tls.crt")
+	require.NoError(t, os.WriteFile(path, []byte(pem), 0o600))
+
+	prompted := 0
+	r := &valueReader{
+		stdin:  strings.NewReader("s3cr3t\n\n"),
+		hidden: true,
+		prompt: func(key string) (string, error) {
+			prompted++
+			if key == "EMPTY" {
+				return "", nil
+			}
+			return "typed-" + key, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		raw     string
+		want    string
+		wantErr string
+	}{
+		{name: "literal", key: "A", raw: "plain", want: "plain"},
+		{name: "file is byte-for-byte", key: "CERT", raw: "@" + path, want: pem},
+		{name: "escaped at", key: "HANDLE", raw: "@@admiral", want: "@admiral"},
+		{name: "stdin is byte-for-byte", key: "PASSWORD", raw: "-", want: "s3cr3t\n\n"},
+		{name: "stdin only once", key: "OTHER", raw: "-", wantErr: "already used by PASSWORD"},
+		{name: "prompt", key: "TOKEN", raw: "", want: "typed-TOKEN"},
+		{name: "empty prompt", key: "EMPTY", raw: "", wantErr: "no value entered for EMPTY"},
+		{name: "missing file", key: "B", raw: "@" + filepath.Join(t.TempDir(), "nope"), wantErr: "reading value for B"},
+		{name: "bare at", key: "C", raw: "@", wantErr: "expected a file path after @"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.resolve(tt.key, tt.raw)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+	require.Equal(t, 2, prompted)
+}
+
+func TestValueReader_NoPromptUnlessHidden(t *testing.T) {
+	r := &valueReader{
+		prompt: func(string) (string, error) { return "", errors.New("unexpected prompt") },
+	}
+	got, err := r.resolve("EMPTY", "")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
@@ -37,6 +37,10 @@ Use -e/--env to target a specific environment within an app.`,
 		newGetCmd(opts),
 		newListCmd(opts),
 		newDeleteCmd(opts),
+		newImportCmd(opts),
+		newExportCmd(opts),
+		newRunCmd(opts),
+		newSearchCmd(opts),
 	)
 
 	root.Cmd = cmd
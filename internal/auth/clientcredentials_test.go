@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/credentials"
+)
+
+// newClientCredentialsIdP serves OIDC discovery and a token endpoint that
+// accepts the client credentials grant for svc-client/svc-secret.
+func newClientCredentialsIdP(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.NotContains(t, r.FormValue("scope"), "openid")
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "svc-client" || secret != "svc-secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token": "svc-access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientCredentialsLogin(t *testing.T) {
+	t.Run("saves a renewable session", func(t *testing.T) {
+		srv := newClientCredentialsIdP(t)
+		dir := t.TempDir()
+
+		err := ClientCredentialsLogin(context.Background(), LoginOptions{
+			Issuer:        srv.URL,
+			ClientID:      "svc-client",
+			ClientSecret:  "svc-secret",
+			Scopes:        []string{"openid", "offline_access", "admiral.api"},
+			ConfigDir:     dir,
+			CredentialKey: testKey,
+		})
+		require.NoError(t, err)
+
+		tok, err := credentials.GetToken(dir, testKey)
+		require.NoError(t, err)
+		assert.Equal(t, "svc-access", tok.AccessToken)
+		assert.Empty(t, tok.RefreshToken)
+
+		// The file store doesn't keep the secret, so the session is renewed
+		// from the environment.
+		t.Setenv(EnvClientSecret, "")
+		sessions, err := credentials.ListSessions(dir)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, "svc-client", sessions[0].ClientID)
+		assert.False(t, sessions[0].Refreshable)
+
+		t.Setenv(EnvClientSecret, "svc-secret")
+		sessions, err = credentials.ListSessions(dir)
+		require.NoError(t, err)
+		assert.True(t, sessions[0].Refreshable)
+	})
+
+	t.Run("rejected secret", func(t *testing.T) {
+		srv := newClientCredentialsIdP(t)
+
+		err := ClientCredentialsLogin(context.Background(), LoginOptions{
+			Issuer:        srv.URL,
+			ClientID:      "svc-client",
+			ClientSecret:  "wrong",
+			ConfigDir:     t.TempDir(),
+			CredentialKey: testKey,
+		})
+		require.ErrorContains(t, err, "client credentials grant failed")
+	})
+
+	t.Run("requires client ID and secret", func(t *testing.T) {
+		err := ClientCredentialsLogin(context.Background(), LoginOptions{
+			Issuer:    "https://example.com",
+			ClientID:  "svc-client",
+			ConfigDir: t.TempDir(),
+		})
+		require.ErrorContains(t, err, "requires a client ID and client secret")
+	})
+}
+
+func TestReadClientCredentialsFile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sa.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"client_id": "svc-client", "client_secret": "svc-secret"}`), 0600))
+
+		f, err := ReadClientCredentialsFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "svc-client", f.ClientID)
+		assert.Equal(t, "svc-secret", f.ClientSecret)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := ReadClientCredentialsFile(filepath.Join(t.TempDir(), "nope.json"))
+		require.ErrorContains(t, err, "failed to read client credentials file")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sa.json")
+		require.NoError(t, os.WriteFile(path, []byte("client_id=x"), 0600))
+
+		_, err := ReadClientCredentialsFile(path)
+		require.ErrorContains(t, err, "failed to parse client credentials file")
+	})
+}
@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"go.admiral.io/cli/internal/credentials"
+	"go.admiral.io/cli/internal/output"
+)
+
+const (
+	// deviceCodeGrantType is the grant type for polling the token endpoint
+	// during the device authorization flow (RFC 8628 §3.4).
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultDeviceInterval is the polling interval, in seconds, when the
+	// authorization server doesn't specify one (RFC 8628 §3.2).
+	defaultDeviceInterval = 5
+
+	// maxDeviceInterval caps the backoff applied after transient errors.
+	maxDeviceInterval = 60
+)
+
+// devicePollUnit is the unit of the polling interval. Tests shorten it so the
+// flow doesn't take real seconds.
+var devicePollUnit = time.Second
+
+// tokenResponse is a token endpoint response, successful or not (RFC 6749 §5).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (r *tokenResponse) token() *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken:  r.AccessToken,
+		TokenType:    r.TokenType,
+		RefreshToken: r.RefreshToken,
+	}
+	if r.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return token
+}
+
+func (r *tokenResponse) err() error {
+	if r.ErrorDescription != "" {
+		return fmt.Errorf("%s: %s", r.Error, r.ErrorDescription)
+	}
+	return errors.New(r.Error)
+}
+
+// DeviceLogin performs the OAuth 2.0 device authorization flow (RFC 8628).
+// It prints a verification URI and user code to opts.Out and polls the token
+// endpoint until the user approves the request on another device.
+func DeviceLogin(ctx context.Context, opts LoginOptions) error {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	oidcCtx := oidc.ClientContext(ctx, httpClient)
+	provider, err := oidc.NewProvider(oidcCtx, opts.Issuer)
+	if err != nil {
+		return fmt.Errorf("failed to query OIDC provider %q: %w", opts.Issuer, err)
+	}
+
+	oc := &oauth2.Config{
+		ClientID: opts.ClientID,
+		Endpoint: provider.Endpoint(),
+		Scopes:   opts.Scopes,
+	}
+	if oc.Endpoint.DeviceAuthURL == "" {
+		return fmt.Errorf("OIDC provider %q does not support device authorization", opts.Issuer)
+	}
+
+	da, err := oc.DeviceAuth(oidcCtx)
+	if err != nil {
+		return fmt.Errorf("device authorization request failed: %w", err)
+	}
+
+	out := opts.Out
+	if out == nil {
+		out = io.Discard
+	}
+	output.Writef(out, "To log in, open the following URL on any device:\n\n  %s\n\n", da.VerificationURI)
+	output.Writef(out, "and enter the code: %s\n\n", da.UserCode)
+	if da.VerificationURIComplete != "" {
+		output.Writef(out, "Or open this URL to skip entering the code:\n\n  %s\n\n", da.VerificationURIComplete)
+	}
+	output.Writeln(out, "Waiting for approval...")
+
+	token, err := pollDeviceToken(ctx, httpClient, oc, da)
+	if err != nil {
+		return err
+	}
+
+	// Verify the ID token if the provider issued one (OIDC Core §3.1.3.7).
+	if rawIDToken, ok := token.Extra("id_token").(string); ok && rawIDToken != "" {
+		if _, err := provider.Verifier(&oidc.Config{ClientID: opts.ClientID}).Verify(oidcCtx, rawIDToken); err != nil {
+			return fmt.Errorf("id token verification failed: %w", err)
+		}
+	}
+
+	slog.Debug("device login flow completed")
+	return credentials.SaveToken(opts.ConfigDir, opts.CredentialKey, token, opts.Issuer, opts.ClientID, oc.Endpoint.TokenURL)
+}
+
+// pollDeviceToken polls the token endpoint until the device code is approved,
+// denied or expires. It honours slow_down by adding five seconds to the
+// interval (RFC 8628 §3.5) and backs off exponentially on transient errors.
+func pollDeviceToken(ctx context.Context, httpClient *http.Client, oc *oauth2.Config, da *oauth2.DeviceAuthResponse) (*oauth2.Token, error) {
+	interval := da.Interval
+	if interval <= 0 {
+		interval = defaultDeviceInterval
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", deviceCodeGrantType)
+	data.Set("device_code", da.DeviceCode)
+	data.Set("client_id", oc.ClientID)
+
+	for {
+		if !da.Expiry.IsZero() && time.Now().After(da.Expiry) {
+			return nil, errors.New("device code expired before it was approved: run 'admiral auth login --device' again")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("login canceled: %w", ctx.Err())
+		case <-time.After(time.Duration(interval) * devicePollUnit):
+		}
+
+		res, err := requestToken(ctx, httpClient, oc.Endpoint.TokenURL, data)
+		if err != nil {
+			interval = min(interval*2, maxDeviceInterval)
+			slog.Debug("device token poll failed, backing off", "error", err, "interval", interval)
+			continue
+		}
+
+		switch res.Error {
+		case "":
+			token := res.token()
+			if res.IDToken != "" {
+				token = token.WithExtra(map[string]any{"id_token": res.IDToken})
+			}
+			return token, nil
+		case "authorization_pending":
+			// The user hasn't approved yet; keep polling.
+		case "slow_down":
+			interval += 5
+			slog.Debug("authorization server asked to slow down", "interval", interval)
+		case "expired_token":
+			return nil, errors.New("device code expired before it was approved: run 'admiral auth login --device' again")
+		case "access_denied":
+			return nil, errors.New("authorization denied")
+		default:
+			return nil, fmt.Errorf("device token request failed: %w", res.err())
+		}
+	}
+}
+
+// requestToken posts a form to the token endpoint and decodes the response.
+// OAuth error responses are returned in tokenResponse.Error rather than as an
+// error; only transport failures and unparseable responses return an error.
+func requestToken(ctx context.Context, httpClient *http.Client, tokenURL string, data url.Values) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort cleanup
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("token endpoint unavailable (HTTP %d)", resp.StatusCode)
+	}
+
+	var res tokenResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("invalid token response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if res.Error == "" && res.AccessToken == "" {
+		return nil, fmt.Errorf("token response missing access_token (HTTP %d)", resp.StatusCode)
+	}
+
+	return &res, nil
+}
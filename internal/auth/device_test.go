@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"go.admiral.io/cli/internal/credentials"
+)
+
+// fakeIdP is an httptest OIDC provider that supports the device
+// authorization grant. tokenReplies are served in order by the token
+// endpoint; the last one repeats.
+type fakeIdP struct {
+	*httptest.Server
+
+	noDeviceEndpoint bool
+	tokenReplies     []fakeTokenReply
+	polls            atomic.Int32
+}
+
+type fakeTokenReply struct {
+	status int
+	body   map[string]any
+}
+
+func newFakeIdP(t *testing.T, replies ...fakeTokenReply) *fakeIdP {
+	t.Helper()
+
+	idp := &fakeIdP{tokenReplies: replies}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		doc := map[string]any{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		}
+		if !idp.noDeviceEndpoint {
+			doc["device_authorization_endpoint"] = idp.URL + "/device"
+		}
+		writeJSON(w, http.StatusOK, doc)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "test-client", r.FormValue("client_id"))
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":               "device-123",
+			"user_code":                 "ABCD-EFGH",
+			"verification_uri":          idp.URL + "/activate",
+			"verification_uri_complete": idp.URL + "/activate?user_code=ABCD-EFGH",
+			"expires_in":                600,
+			"interval":                  1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, deviceCodeGrantType, r.FormValue("grant_type"))
+		assert.Equal(t, "device-123", r.FormValue("device_code"))
+
+		n := int(idp.polls.Add(1)) - 1
+		reply := idp.tokenReplies[min(n, len(idp.tokenReplies)-1)]
+		writeJSON(w, reply.status, reply.body)
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func pending(code string) fakeTokenReply {
+	return fakeTokenReply{status: http.StatusBadRequest, body: map[string]any{"error": code}}
+}
+
+func granted() fakeTokenReply {
+	return fakeTokenReply{status: http.StatusOK, body: map[string]any{
+		"access_token":  "device-access",
+		"refresh_token": "device-refresh",
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+	}}
+}
+
+// fastPolling shortens the device polling interval for the test.
+func fastPolling(t *testing.T) {
+	t.Helper()
+	prev := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = prev })
+}
+
+func TestDeviceLogin(t *testing.T) {
+	t.Run("prints code and saves token after approval", func(t *testing.T) {
+		fastPolling(t)
+		t.Setenv(credentials.EnvToken, "")
+		idp := newFakeIdP(t, pending("authorization_pending"), pending("slow_down"), granted())
+
+		dir := t.TempDir()
+		var out bytes.Buffer
+		err := DeviceLogin(context.Background(), LoginOptions{
+			Issuer:        idp.URL,
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
+			Out:           &out,
+		})
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), idp.URL+"/activate")
+		assert.Contains(t, out.String(), "ABCD-EFGH")
+		assert.Equal(t, int32(3), idp.polls.Load())
+
+		tok, err := credentials.GetToken(dir, testKey)
+		require.NoError(t, err)
+		assert.Equal(t, "device-access", tok.AccessToken)
+		assert.Equal(t, "device-refresh", tok.RefreshToken)
+
+		sessions, err := credentials.ListSessions(dir)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, idp.URL, sessions[0].Issuer)
+		assert.True(t, sessions[0].Refreshable)
+	})
+
+	t.Run("expired device code", func(t *testing.T) {
+		fastPolling(t)
+		idp := newFakeIdP(t, pending("authorization_pending"), pending("expired_token"))
+
+		err := DeviceLogin(context.Background(), LoginOptions{
+			Issuer:        idp.URL,
+			ClientID:      "test-client",
+			ConfigDir:     t.TempDir(),
+			CredentialKey: testKey,
+		})
+		require.ErrorContains(t, err, "device code expired")
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		fastPolling(t)
+		idp := newFakeIdP(t, pending("access_denied"))
+
+		err := DeviceLogin(context.Background(), LoginOptions{
+			Issuer:        idp.URL,
+			ClientID:      "test-client",
+			ConfigDir:     t.TempDir(),
+			CredentialKey: testKey,
+		})
+		require.ErrorContains(t, err, "authorization denied")
+	})
+
+	t.Run("backs off on server errors", func(t *testing.T) {
+		fastPolling(t)
+		idp := newFakeIdP(t, fakeTokenReply{status: http.StatusServiceUnavailable, body: map[string]any{}}, granted())
+
+		err := DeviceLogin(context.Background(), LoginOptions{
+			Issuer:        idp.URL,
+			ClientID:      "test-client",
+			ConfigDir:     t.TempDir(),
+			CredentialKey: testKey,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), idp.polls.Load())
+	})
+
+	t.Run("provider without device endpoint", func(t *testing.T) {
+		idp := newFakeIdP(t, granted())
+		idp.noDeviceEndpoint = true
+
+		err := DeviceLogin(context.Background(), LoginOptions{
+			Issuer:    idp.URL,
+			ClientID:  "test-client",
+			ConfigDir: t.TempDir(),
+		})
+		require.ErrorContains(t, err, "does not support device authorization")
+	})
+
+	t.Run("canceled context stops polling", func(t *testing.T) {
+		fastPolling(t)
+		idp := newFakeIdP(t, pending("authorization_pending"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := DeviceLogin(ctx, LoginOptions{
+			Issuer:        idp.URL,
+			ClientID:      "test-client",
+			ConfigDir:     t.TempDir(),
+			CredentialKey: testKey,
+		})
+		require.ErrorContains(t, err, "login canceled")
+	})
+}
+
+func TestPollDeviceTokenSlowDown(t *testing.T) {
+	fastPolling(t)
+
+	var times []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		times = append(times, time.Now())
+		if len(times) == 1 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "slow_down"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "at"})
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	oc := &oauth2.Config{ClientID: "test-client", Endpoint: oauth2.Endpoint{TokenURL: srv.URL}}
+	_, err := pollDeviceToken(context.Background(), srv.Client(), oc, &oauth2.DeviceAuthResponse{DeviceCode: "dc", Interval: 1})
+	require.NoError(t, err)
+	require.Len(t, times, 2)
+
+	// First poll waits 1 unit, the second 1+5 units after slow_down.
+	assert.GreaterOrEqual(t, times[0].Sub(start), 1*devicePollUnit)
+	assert.GreaterOrEqual(t, times[1].Sub(times[0]), 6*devicePollUnit)
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.admiral.io/cli/internal/credentials"
+)
+
+// FederatedLogin exchanges the OIDC token in opts.FederatedTokenFile, e.g.
+// one issued by a CI provider, for an Admiral access token (RFC 8693). The
+// file is re-read whenever the session expires, so tokens rotated by the CI
+// runner keep working.
+func FederatedLogin(ctx context.Context, opts LoginOptions) error {
+	if opts.FederatedTokenFile == "" {
+		return errors.New("federated login requires a token file")
+	}
+
+	_, err := credentials.Federate(ctx, opts.ConfigDir, opts.CredentialKey, credentials.Federation{
+		Issuer:    opts.Issuer,
+		ClientID:  opts.ClientID,
+		TokenFile: opts.FederatedTokenFile,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("federated login completed")
+	return nil
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/credentials"
+)
+
+// newExchangeIdP serves OIDC discovery and an RFC 8693 token endpoint that
+// trades subject token ci-token for access token ci-access.
+func newExchangeIdP(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, credentials.GrantTokenExchange, r.FormValue("grant_type"))
+		assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", r.FormValue("subject_token_type"))
+		assert.Equal(t, "svc-client", r.FormValue("client_id"))
+
+		if r.FormValue("subject_token") != "ci-token" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"access_token":      "ci-access",
+			"issued_token_type": "urn:ietf:params:oauth:token-type:access_token",
+			"token_type":        "Bearer",
+			"expires_in":        3600,
+		})
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFederatedLogin(t *testing.T) {
+	t.Run("saves a renewable session", func(t *testing.T) {
+		srv := newExchangeIdP(t)
+		dir := t.TempDir()
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("ci-token\n"), 0600))
+
+		err := FederatedLogin(context.Background(), LoginOptions{
+			Issuer:             srv.URL,
+			ClientID:           "svc-client",
+			ConfigDir:          dir,
+			CredentialKey:      testKey,
+			FederatedTokenFile: tokenFile,
+		})
+		require.NoError(t, err)
+
+		tok, err := credentials.GetToken(dir, testKey)
+		require.NoError(t, err)
+		assert.Equal(t, "ci-access", tok.AccessToken)
+
+		sessions, err := credentials.ListSessions(dir)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.True(t, sessions[0].Refreshable)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		srv := newExchangeIdP(t)
+		tokenFile := filepath.Join(t.TempDir(), "token")
+		require.NoError(t, os.WriteFile(tokenFile, []byte("ci-token"), 0600))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := FederatedLogin(ctx, LoginOptions{
+			Issuer:             srv.URL,
+			ClientID:           "svc-client",
+			ConfigDir:          t.TempDir(),
+			CredentialKey:      testKey,
+			FederatedTokenFile: tokenFile,
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("requires a token file", func(t *testing.T) {
+		err := FederatedLogin(context.Background(), LoginOptions{
+			Issuer:    "https://example.com",
+			ConfigDir: t.TempDir(),
+		})
+		require.ErrorContains(t, err, "requires a token file")
+	})
+
+	t.Run("missing token file", func(t *testing.T) {
+		srv := newClientCredentialsIdP(t)
+
+		err := FederatedLogin(context.Background(), LoginOptions{
+			Issuer:             srv.URL,
+			ClientID:           "svc-client",
+			ConfigDir:          t.TempDir(),
+			CredentialKey:      testKey,
+			FederatedTokenFile: filepath.Join(t.TempDir(), "missing"),
+		})
+		require.ErrorContains(t, err, "failed to read federated token")
+	})
+}
@@ -36,6 +36,21 @@ type LoginOptions struct {
 	ClientID  string
 	Scopes    []string
 	ConfigDir string
+
+	// ClientSecret authenticates a service account in the client
+	// credentials flow. Unused by interactive logins.
+	ClientSecret string
+
+	// FederatedTokenFile holds a CI-issued OIDC token to exchange in the
+	// federated flow. Unused by interactive logins.
+	FederatedTokenFile string
+
+	// CredentialKey identifies the session the resulting token is stored under.
+	CredentialKey string
+
+	// Out receives instructions for flows that need the user to act on
+	// another device. Nil discards them.
+	Out io.Writer
 }
 
 // callbackPorts is the set of ports pre-registered as redirect URIs on the
@@ -200,7 +215,7 @@ func Login(ctx context.Context, opts LoginOptions) error {
 	}
 
 	slog.Debug("login flow completed")
-	return credentials.SaveToken(opts.ConfigDir, token, opts.ClientID, oc.Endpoint.TokenURL)
+	return credentials.SaveToken(opts.ConfigDir, opts.CredentialKey, token, opts.Issuer, opts.ClientID, oc.Endpoint.TokenURL)
 }
 
 // renderError writes the styled error page with the given HTTP status and message.
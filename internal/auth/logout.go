@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -15,22 +16,93 @@ type LogoutOptions struct {
 	Issuer    string
 	ClientID  string
 	ConfigDir string
+
+	// CredentialKey identifies the session to log out of.
+	CredentialKey string
+
+	// All logs out of every stored session instead of just CredentialKey.
+	All bool
 }
 
 // Logout revokes the refresh token and deletes stored credentials.
 func Logout(_ context.Context, opts LogoutOptions) error {
-	// Get current token before deleting (for revocation).
-	token, _ := credentials.GetToken(opts.ConfigDir)
+	if opts.All {
+		return logoutAll(opts)
+	}
 
-	// Delete stored credentials.
-	if err := credentials.DeleteToken(opts.ConfigDir); err != nil {
+	// Get current token before deleting (for revocation), along with the
+	// issuer and client it was obtained from.
+	token, _ := credentials.GetToken(opts.ConfigDir, opts.CredentialKey)
+	issuer, clientID := opts.Issuer, opts.ClientID
+	if sessions, err := credentials.ListSessions(opts.ConfigDir); err == nil {
+		for _, s := range sessions {
+			if s.Key != opts.CredentialKey {
+				continue
+			}
+			if s.Issuer != "" {
+				issuer = s.Issuer
+			}
+			if s.ClientID != "" {
+				clientID = s.ClientID
+			}
+		}
+	}
+
+	// Delete stored credentials, along with any token exchanged for a
+	// federated token for the same server.
+	if err := credentials.DeleteToken(opts.ConfigDir, opts.CredentialKey, opts.Issuer); err != nil {
+		return err
+	}
+	if err := credentials.DeleteToken(opts.ConfigDir, credentials.FederatedKey(opts.CredentialKey), ""); err != nil {
 		return err
 	}
 
 	// Attempt to revoke the refresh token (best-effort). The access token
 	// is short-lived and will expire on its own.
 	if token != nil && token.RefreshToken != "" {
-		_ = revokeRefreshToken(token.RefreshToken, opts.ClientID, opts.Issuer)
+		_ = revokeRefreshToken(token.RefreshToken, clientID, issuer)
+	}
+
+	return nil
+}
+
+// logoutAll deletes every stored session, then revokes each refresh token
+// against the issuer it was obtained from.
+//
+// Unreadable credentials are still deleted, so this recovers from a corrupt
+// store; there is nothing to revoke then.
+func logoutAll(opts LogoutOptions) error {
+	sessions, err := credentials.ListSessions(opts.ConfigDir)
+	if err != nil {
+		slog.Debug("failed to list sessions to revoke", "error", err)
+	}
+
+	type revocation struct {
+		token, clientID, issuer string
+	}
+	var pending []revocation
+	for _, s := range sessions {
+		token, err := credentials.GetToken(opts.ConfigDir, s.Key)
+		if err != nil || token.RefreshToken == "" {
+			continue
+		}
+
+		r := revocation{token: token.RefreshToken, clientID: s.ClientID, issuer: s.Issuer}
+		if r.issuer == "" {
+			r.issuer = opts.Issuer
+		}
+		if r.clientID == "" {
+			r.clientID = opts.ClientID
+		}
+		pending = append(pending, r)
+	}
+
+	if err := credentials.DeleteAllTokens(opts.ConfigDir); err != nil {
+		return err
+	}
+
+	for _, r := range pending {
+		_ = revokeRefreshToken(r.token, r.clientID, r.issuer)
 	}
 
 	return nil
@@ -62,16 +62,18 @@ func TestRevokeRefreshToken(t *testing.T) {
 	})
 }
 
+const testKey = "api.example.com:443"
+
 // saveTestToken writes a token to the given configDir so Logout can read it.
-func saveTestToken(t *testing.T, configDir string, tok *oauth2.Token) {
+func saveTestToken(t *testing.T, configDir, issuer string, tok *oauth2.Token) {
 	t.Helper()
-	require.NoError(t, credentials.SaveToken(configDir, tok, "test-client", "https://example.com/token"))
+	require.NoError(t, credentials.SaveToken(configDir, testKey, tok, issuer, "test-client", issuer+"/token"))
 }
 
 func TestLogout(t *testing.T) {
 	t.Run("deletes stored credentials", func(t *testing.T) {
 		dir := t.TempDir()
-		saveTestToken(t, dir, &oauth2.Token{
+		saveTestToken(t, dir, "https://example.com", &oauth2.Token{
 			AccessToken: "at",
 			Expiry:      time.Now().Add(time.Hour),
 		})
@@ -81,9 +83,10 @@ func TestLogout(t *testing.T) {
 		require.NoError(t, err)
 
 		err = Logout(context.Background(), LogoutOptions{
-			Issuer:    "https://example.com",
-			ClientID:  "test-client",
-			ConfigDir: dir,
+			Issuer:        "https://example.com",
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
 		})
 		require.NoError(t, err)
 
@@ -103,21 +106,47 @@ func TestLogout(t *testing.T) {
 		defer srv.Close()
 
 		dir := t.TempDir()
-		saveTestToken(t, dir, &oauth2.Token{
+		saveTestToken(t, dir, srv.URL, &oauth2.Token{
 			AccessToken:  "at",
 			RefreshToken: "my-refresh",
 			Expiry:       time.Now().Add(time.Hour),
 		})
 
 		err := Logout(context.Background(), LogoutOptions{
-			Issuer:    srv.URL,
-			ClientID:  "test-client",
-			ConfigDir: dir,
+			Issuer:        srv.URL,
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
 		})
 		require.NoError(t, err)
 		assert.True(t, revoked, "refresh token should have been revoked")
 	})
 
+	t.Run("revokes against the stored issuer", func(t *testing.T) {
+		var revoked bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			revoked = true
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		dir := t.TempDir()
+		saveTestToken(t, dir, srv.URL, &oauth2.Token{
+			AccessToken:  "at",
+			RefreshToken: "my-refresh",
+			Expiry:       time.Now().Add(time.Hour),
+		})
+
+		err := Logout(context.Background(), LogoutOptions{
+			Issuer:        "http://127.0.0.1:0",
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
+		})
+		require.NoError(t, err)
+		assert.True(t, revoked, "refresh token should be revoked at the issuer that issued it")
+	})
+
 	t.Run("skips revocation when no refresh token", func(t *testing.T) {
 		var called bool
 		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -127,15 +156,16 @@ func TestLogout(t *testing.T) {
 		defer srv.Close()
 
 		dir := t.TempDir()
-		saveTestToken(t, dir, &oauth2.Token{
+		saveTestToken(t, dir, srv.URL, &oauth2.Token{
 			AccessToken: "at",
 			Expiry:      time.Now().Add(time.Hour),
 		})
 
 		err := Logout(context.Background(), LogoutOptions{
-			Issuer:    srv.URL,
-			ClientID:  "test-client",
-			ConfigDir: dir,
+			Issuer:        srv.URL,
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
 		})
 		require.NoError(t, err)
 		assert.False(t, called, "revocation endpoint should not be called")
@@ -144,9 +174,10 @@ func TestLogout(t *testing.T) {
 	t.Run("succeeds when no credentials exist", func(t *testing.T) {
 		dir := t.TempDir()
 		err := Logout(context.Background(), LogoutOptions{
-			Issuer:    "https://example.com",
-			ClientID:  "test-client",
-			ConfigDir: dir,
+			Issuer:        "https://example.com",
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
 		})
 		require.NoError(t, err)
 	})
@@ -158,21 +189,76 @@ func TestLogout(t *testing.T) {
 		defer srv.Close()
 
 		dir := t.TempDir()
-		saveTestToken(t, dir, &oauth2.Token{
+		saveTestToken(t, dir, srv.URL, &oauth2.Token{
 			AccessToken:  "at",
 			RefreshToken: "rt",
 			Expiry:       time.Now().Add(time.Hour),
 		})
 
 		err := Logout(context.Background(), LogoutOptions{
-			Issuer:    srv.URL,
-			ClientID:  "test-client",
-			ConfigDir: dir,
+			Issuer:        srv.URL,
+			ClientID:      "test-client",
+			ConfigDir:     dir,
+			CredentialKey: testKey,
 		})
 		require.NoError(t, err, "logout should succeed even if revocation fails")
 	})
 }
 
+func TestLogoutAll(t *testing.T) {
+	revoked := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		revoked[r.FormValue("token")] = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	expiry := time.Now().Add(time.Hour)
+	require.NoError(t, credentials.SaveToken(dir, "prod:443", &oauth2.Token{AccessToken: "a", RefreshToken: "prod-rt", Expiry: expiry}, srv.URL, "cid", "url"))
+	require.NoError(t, credentials.SaveToken(dir, "staging:443", &oauth2.Token{AccessToken: "b", RefreshToken: "staging-rt", Expiry: expiry}, srv.URL, "cid", "url"))
+
+	err := Logout(context.Background(), LogoutOptions{
+		ConfigDir: dir,
+		All:       true,
+	})
+	require.NoError(t, err)
+
+	sessions, err := credentials.ListSessions(dir)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	assert.True(t, revoked["prod-rt"])
+	assert.True(t, revoked["staging-rt"])
+}
+
+func TestLogoutAll_CorruptCredentials(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "credentials.json"), []byte("not json"), 0o600))
+
+	require.NoError(t, Logout(context.Background(), LogoutOptions{ConfigDir: dir, All: true}))
+
+	_, err := os.Stat(filepath.Join(dir, "credentials.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLogoutKeepsOtherSessions(t *testing.T) {
+	dir := t.TempDir()
+	saveTestToken(t, dir, "https://example.com", &oauth2.Token{AccessToken: "at", Expiry: time.Now().Add(time.Hour)})
+	require.NoError(t, credentials.SaveToken(dir, "staging:443", &oauth2.Token{AccessToken: "b"}, "https://example.com", "cid", "url"))
+
+	err := Logout(context.Background(), LogoutOptions{
+		ConfigDir:     dir,
+		CredentialKey: testKey,
+	})
+	require.NoError(t, err)
+
+	sessions, err := credentials.ListSessions(dir)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "staging:443", sessions[0].Key)
+}
+
 // TestLogoutDeletesBeforeRevoking verifies that credentials are removed from
 // disk before the revocation request is made. This ensures the user is logged
 // out locally even if the revocation endpoint is slow or unreachable.
@@ -188,16 +274,17 @@ func TestLogoutDeletesBeforeRevoking(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	saveTestToken(t, dir, &oauth2.Token{
+	saveTestToken(t, dir, srv.URL, &oauth2.Token{
 		AccessToken:  "at",
 		RefreshToken: "rt",
 		Expiry:       time.Now().Add(time.Hour),
 	})
 
 	err := Logout(context.Background(), LogoutOptions{
-		Issuer:    srv.URL,
-		ClientID:  "test-client",
-		ConfigDir: dir,
+		Issuer:        srv.URL,
+		ClientID:      "test-client",
+		ConfigDir:     dir,
+		CredentialKey: testKey,
 	})
 	require.NoError(t, err)
 	assert.True(t, deletedBeforeRevoke, "credentials should be deleted before revocation request")
@@ -244,14 +331,17 @@ func TestCredentialsFileFormat(t *testing.T) {
 		TokenType:    "Bearer",
 		Expiry:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
-	saveTestToken(t, dir, tok)
+	saveTestToken(t, dir, "https://example.com", tok)
 
 	data, err := os.ReadFile(filepath.Join(dir, "credentials.json"))
 	require.NoError(t, err)
 
-	var raw map[string]any
-	require.NoError(t, json.Unmarshal(data, &raw))
+	var file struct {
+		Sessions map[string]map[string]any `json:"sessions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
 
+	raw := file.Sessions[testKey]
 	assert.Equal(t, "access", raw["access_token"])
 	assert.Equal(t, "refresh", raw["refresh_token"])
 	assert.Equal(t, "Bearer", raw["token_type"])
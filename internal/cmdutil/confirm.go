@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
 	"strings"
+
+	"golang.org/x/term"
 )
 
 // ConfirmPrompt asks the user a yes/no question, defaulting to "No".
@@ -25,6 +28,13 @@ func ConfirmPrompt(r io.Reader, w io.Writer, question string) (bool, error) {
 	return answer == "y" || answer == "yes", nil
 }
 
+// CanPrompt reports whether r is an interactive terminal, so ConfirmPrompt
+// can read an answer from it rather than from piped input.
+func CanPrompt(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // file descriptors fit in an int
+}
+
 // Writef writes formatted output to w, swallowing the return values.
 // This is a convenience re-export from the output package to avoid
 // circular imports when cmdutil needs to write prompts.
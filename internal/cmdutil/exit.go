@@ -0,0 +1,24 @@
+package cmdutil
+
+import "fmt"
+
+// ExitError makes the CLI exit with Code instead of the default 1. Subcommand
+// packages return it where the root command's exitError isn't reachable.
+//
+// A nil Err exits without printing an error, for commands whose exit code is
+// itself the result, such as a diff reporting differences.
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,138 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+
+	"go.admiral.io/cli/internal/output"
+)
+
+// Pager holds the paging flags of a list command.
+type Pager struct {
+	PageSize  int32
+	PageToken string
+	// All follows NextPageToken until the last page.
+	All bool
+	// Limit follows NextPageToken until this many items are returned. Zero
+	// means no limit.
+	Limit int
+}
+
+// AddPagerFlags registers --page-size, --page-token, --all and --limit on
+// cmd. noun names the listed resource in the help text, e.g. "clusters".
+func AddPagerFlags(cmd *cobra.Command, p *Pager, noun string) {
+	cmd.Flags().Int32Var(&p.PageSize, "page-size", 50, fmt.Sprintf("number of %s to return per page", noun))
+	cmd.Flags().StringVar(&p.PageToken, "page-token", "", "token for the next page of results")
+	cmd.Flags().BoolVar(&p.All, "all", false, fmt.Sprintf("fetch every page of %s", noun))
+	cmd.Flags().IntVar(&p.Limit, "limit", 0, fmt.Sprintf("maximum number of %s to return, fetching pages as needed", noun))
+	cmd.MarkFlagsMutuallyExclusive("all", "limit")
+}
+
+// PageFetcher fetches one page of items of at most pageSize, starting at
+// pageToken.
+type PageFetcher[T any] func(ctx context.Context, pageSize int32, pageToken string) (items []T, nextPageToken string, err error)
+
+// Each fetches pages and calls fn with the items of each. Without All or
+// Limit only the first page is fetched. It returns the token of the page
+// after the last one fetched, or "" if there are no more.
+func Each[T any](ctx context.Context, p *Pager, fetch PageFetcher[T], fn func(items []T) error) (string, error) {
+	if p.PageSize <= 0 {
+		return "", errors.New("--page-size must be positive")
+	}
+	if p.Limit < 0 {
+		return "", errors.New("--limit must not be negative")
+	}
+
+	token := p.PageToken
+	fetched := 0
+	for {
+		size := p.PageSize
+		if p.Limit > 0 {
+			size = int32(min(int(size), p.Limit-fetched)) //nolint:gosec // bounded by PageSize
+		}
+
+		items, next, err := fetch(ctx, size, token)
+		if err != nil {
+			return "", err
+		}
+		if p.Limit > 0 && len(items) > p.Limit-fetched {
+			// The server returned more than asked; the token no longer
+			// points at the next unseen item.
+			items, next = items[:p.Limit-fetched], ""
+		}
+		fetched += len(items)
+		if err := fn(items); err != nil {
+			return "", err
+		}
+
+		token = next
+		multiPage := p.All || p.Limit > 0
+		if token == "" || !multiPage || (p.Limit > 0 && fetched >= p.Limit) {
+			return token, nil
+		}
+	}
+}
+
+// ListOutput describes how the items of a paged list are printed.
+type ListOutput[T any] struct {
+	// Header writes the table header.
+	Header func(w *tabwriter.Writer)
+	// Row writes the table row of an item.
+	Row func(w *tabwriter.Writer, item T)
+	// Merge wraps every item in a single list response for json and yaml.
+	Merge func(items []T) proto.Message
+}
+
+// PrintList fetches the pages selected by p and prints them. Table output is
+// streamed a page at a time, so columns are aligned per page; other formats
+// print a single document holding the items of every page. If pages remain,
+// the next page token is written to stderr.
+func PrintList[T any](cmd *cobra.Command, format output.Format, p *Pager, fetch PageFetcher[T], out ListOutput[T]) error {
+	var (
+		next string
+		err  error
+	)
+	if format.IsTable() {
+		next, err = streamTable(cmd.Context(), cmd.OutOrStdout(), p, fetch, out)
+	} else {
+		var items []T
+		next, err = Each(cmd.Context(), p, fetch, func(page []T) error {
+			items = append(items, page...)
+			return nil
+		})
+		if err == nil {
+			printer := output.NewPrinter(format)
+			printer.Out = cmd.OutOrStdout()
+			err = printer.PrintResource(out.Merge(items), nil)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if next != "" {
+		output.Writef(cmd.ErrOrStderr(), "\nNext page token: %s\n", next)
+	}
+	return nil
+}
+
+func streamTable[T any](ctx context.Context, w io.Writer, p *Pager, fetch PageFetcher[T], out ListOutput[T]) (string, error) {
+	tw := output.NewTableWriter(w)
+	header := true
+	return Each(ctx, p, fetch, func(items []T) error {
+		if header {
+			out.Header(tw)
+			header = false
+		}
+		for _, item := range items {
+			out.Row(tw, item)
+		}
+		return tw.Flush()
+	})
+}
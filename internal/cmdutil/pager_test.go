@@ -0,0 +1,153 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.admiral.io/cli/internal/output"
+)
+
+// fakePages serves the numbers 1..total in pages, recording the page sizes
+// it was asked for.
+type fakePages struct {
+	total int
+	sizes []int32
+}
+
+func (f *fakePages) fetch(_ context.Context, pageSize int32, pageToken string) ([]int, string, error) {
+	f.sizes = append(f.sizes, pageSize)
+	start := 0
+	if pageToken != "" {
+		var err error
+		if start, err = strconv.Atoi(pageToken); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var items []int
+	for i := start; i < f.total && len(items) < int(pageSize); i++ {
+		items = append(items, i+1)
+	}
+	next := ""
+	if end := start + len(items); end < f.total {
+		next = strconv.Itoa(end)
+	}
+	return items, next, nil
+}
+
+// ---------------------------------------------------------------------------
+// Each
+// ---------------------------------------------------------------------------
+
+func TestEach(t *testing.T) {
+	tests := []struct {
+		name      string
+		pager     Pager
+		wantItems int
+		wantNext  string
+		wantSizes []int32
+	}{
+		{"first page only", Pager{PageSize: 2}, 2, "2", []int32{2}},
+		{"from token", Pager{PageSize: 2, PageToken: "4"}, 1, "", []int32{2}},
+		{"all", Pager{PageSize: 2, All: true}, 5, "", []int32{2, 2, 2}},
+		{"limit shrinks the last page", Pager{PageSize: 2, Limit: 3}, 3, "3", []int32{2, 1}},
+		{"limit beyond total", Pager{PageSize: 2, Limit: 10}, 5, "", []int32{2, 2, 2}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakePages{total: 5}
+			var got []int
+			next, err := Each(context.Background(), &tc.pager, f.fetch, func(items []int) error {
+				got = append(got, items...)
+				return nil
+			})
+			require.NoError(t, err)
+			require.Len(t, got, tc.wantItems)
+			require.Equal(t, tc.wantNext, next)
+			require.Equal(t, tc.wantSizes, f.sizes)
+		})
+	}
+}
+
+func TestEach_InvalidFlags(t *testing.T) {
+	f := &fakePages{total: 5}
+	noop := func([]int) error { return nil }
+
+	_, err := Each(context.Background(), &Pager{PageSize: 0}, f.fetch, noop)
+	require.ErrorContains(t, err, "--page-size")
+
+	_, err = Each(context.Background(), &Pager{PageSize: 1, Limit: -1}, f.fetch, noop)
+	require.ErrorContains(t, err, "--limit")
+}
+
+func TestEach_TruncatesOversizedPage(t *testing.T) {
+	fetch := func(context.Context, int32, string) ([]int, string, error) {
+		return []int{1, 2, 3}, "more", nil
+	}
+	var got []int
+	next, err := Each(context.Background(), &Pager{PageSize: 3, Limit: 2}, fetch, func(items []int) error {
+		got = append(got, items...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2}, got)
+	require.Empty(t, next)
+}
+
+// ---------------------------------------------------------------------------
+// PrintList
+// ---------------------------------------------------------------------------
+
+func numberList() ListOutput[int] {
+	return ListOutput[int]{
+		Header: func(w *tabwriter.Writer) { output.Writeln(w, "N\tSQUARE") },
+		Row:    func(w *tabwriter.Writer, n int) { output.Writef(w, "%d\t%d\n", n, n*n) },
+		Merge: func(items []int) proto.Message {
+			values := make([]*structpb.Value, 0, len(items))
+			for _, n := range items {
+				values = append(values, structpb.NewNumberValue(float64(n)))
+			}
+			return &structpb.ListValue{Values: values}
+		},
+	}
+}
+
+func runPrintList(t *testing.T, format output.Format, p Pager) (string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	f := &fakePages{total: 5}
+	require.NoError(t, PrintList(cmd, format, &p, f.fetch, numberList()))
+	return stdout.String(), stderr.String()
+}
+
+func TestPrintList_TableStreamsPages(t *testing.T) {
+	stdout, stderr := runPrintList(t, output.FormatTable, Pager{PageSize: 2, All: true})
+	require.Equal(t, 1, bytes.Count([]byte(stdout), []byte("SQUARE")), "header is printed once")
+	for n := 1; n <= 5; n++ {
+		require.Contains(t, stdout, fmt.Sprintf("%d   %d\n", n, n*n))
+	}
+	require.Empty(t, stderr)
+}
+
+func TestPrintList_JSONMergesPages(t *testing.T) {
+	stdout, stderr := runPrintList(t, output.FormatJSON, Pager{PageSize: 2, Limit: 3})
+
+	var got structpb.ListValue
+	require.NoError(t, got.UnmarshalJSON([]byte(stdout)))
+	require.Len(t, got.Values, 3)
+	require.Contains(t, stderr, "Next page token: 3")
+}
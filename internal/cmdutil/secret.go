@@ -0,0 +1,33 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by SecretPrompt when r isn't an interactive
+// terminal, so the input can't be hidden.
+var ErrNotTerminal = errors.New("input is not a terminal")
+
+// SecretPrompt writes prompt to w and reads a line from r without echoing
+// it. r must be a terminal; anything else returns ErrNotTerminal rather than
+// reading a secret in the clear.
+func SecretPrompt(r io.Reader, w io.Writer, prompt string) (string, error) {
+	f, ok := r.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) { //nolint:gosec // file descriptors fit in an int
+		return "", ErrNotTerminal
+	}
+
+	Writef(w, "%s", prompt)
+	b, err := term.ReadPassword(int(f.Fd())) //nolint:gosec // file descriptors fit in an int
+	// The user's newline isn't echoed either.
+	Writef(w, "\n")
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return string(b), nil
+}
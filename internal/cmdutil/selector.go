@@ -0,0 +1,326 @@
+package cmdutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// AddSelectorFlag registers a --selector (-l) flag on cmd.
+func AddSelectorFlag(cmd *cobra.Command, dest *string) {
+	cmd.Flags().StringVarP(dest, "selector", "l", "",
+		"label selector, e.g. 'env in (prod,staging),team!=core,!deprecated'")
+}
+
+// SelectorError reports where a label selector failed to parse.
+type SelectorError struct {
+	Selector string
+	// Pos is the byte offset of the problem in Selector.
+	Pos int
+	Msg string
+}
+
+// Error renders the message with the selector and a caret under Pos, e.g.
+//
+//	invalid selector at column 14: expected "," or ")", got "staging"
+//	  env in (prod staging)
+//	               ^
+func (e *SelectorError) Error() string {
+	return fmt.Sprintf("invalid selector at column %d: %s\n  %s\n  %s^",
+		e.Pos+1, e.Msg, e.Selector, strings.Repeat(" ", e.Pos))
+}
+
+// ParseSelector converts a kubectl-style label selector into a filter
+// expression for the API. Requirements are separated by commas and must all
+// match:
+//
+//	key=value, key==value   label equals value
+//	key!=value              label is missing or differs from value
+//	key in (v1,v2)          label equals one of the values
+//	key notin (v1,v2)       label is missing or equals none of the values
+//	key                     label is set
+//	!key                    label is not set
+//
+// For example `env in (prod,staging),!deprecated` becomes
+// `(labels.env = "prod" OR labels.env = "staging") AND NOT labels.deprecated:*`.
+// Keys that aren't plain identifiers, such as admiral.io/tier, are quoted:
+// `labels."admiral.io/tier" = "web"`.
+func ParseSelector(selector string) (string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return "", nil
+	}
+
+	p := &selectorParser{lex: selectorLexer{src: selector}}
+	p.next()
+
+	var parts []string
+	for {
+		part, err := p.requirement()
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+
+		switch p.tok.kind {
+		case tokEOF:
+			return strings.Join(parts, " AND "), nil
+		case tokComma:
+			p.next()
+		default:
+			return "", p.errorf(`expected "," or end of selector, got %s`, p.tok)
+		}
+	}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokEq
+	tokNotEq
+	tokBang
+	tokComma
+	tokLParen
+	tokRParen
+	tokInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of selector"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// selectorLexer splits a selector into words and operators. Words are label
+// keys, values and the in/notin keywords.
+type selectorLexer struct {
+	src string
+	pos int
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '/'
+}
+
+func (l *selectorLexer) next() token {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t') {
+		l.pos++
+	}
+	start := l.pos
+	if start >= len(l.src) {
+		return token{kind: tokEOF, pos: start}
+	}
+
+	single := func(kind tokenKind) token {
+		l.pos++
+		return token{kind: kind, text: l.src[start:l.pos], pos: start}
+	}
+	switch c := l.src[start]; {
+	case c == ',':
+		return single(tokComma)
+	case c == '(':
+		return single(tokLParen)
+	case c == ')':
+		return single(tokRParen)
+	case c == '=':
+		l.pos++
+		if l.pos < len(l.src) && l.src[l.pos] == '=' {
+			l.pos++
+		}
+		return token{kind: tokEq, text: l.src[start:l.pos], pos: start}
+	case c == '!':
+		if start+1 < len(l.src) && l.src[start+1] == '=' {
+			l.pos += 2
+			return token{kind: tokNotEq, text: "!=", pos: start}
+		}
+		return single(tokBang)
+	case isWordByte(c):
+		for l.pos < len(l.src) && isWordByte(l.src[l.pos]) {
+			l.pos++
+		}
+		return token{kind: tokWord, text: l.src[start:l.pos], pos: start}
+	default:
+		return single(tokInvalid)
+	}
+}
+
+type selectorParser struct {
+	lex selectorLexer
+	tok token
+}
+
+func (p *selectorParser) next() {
+	p.tok = p.lex.next()
+}
+
+func (p *selectorParser) errorf(format string, a ...any) error {
+	return &SelectorError{Selector: p.lex.src, Pos: p.tok.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+// requirement parses one comma-separated requirement.
+func (p *selectorParser) requirement() (string, error) {
+	if p.tok.kind == tokBang {
+		p.next()
+		key, err := p.key()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("NOT %s:*", labelField(key)), nil
+	}
+
+	key, err := p.key()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case p.tok.kind == tokEq || p.tok.kind == tokNotEq:
+		negate := p.tok.kind == tokNotEq
+		p.next()
+		value, err := p.value(true)
+		if err != nil {
+			return "", err
+		}
+		if negate {
+			return fmt.Sprintf("NOT %s = %q", labelField(key), value), nil
+		}
+		return fmt.Sprintf("%s = %q", labelField(key), value), nil
+	case p.tok.kind == tokWord && (p.tok.text == "in" || p.tok.text == "notin"):
+		negate := p.tok.text == "notin"
+		p.next()
+		values, err := p.set()
+		if err != nil {
+			return "", err
+		}
+		terms := make([]string, 0, len(values))
+		for _, v := range values {
+			terms = append(terms, fmt.Sprintf("%s = %q", labelField(key), v))
+		}
+		expr := "(" + strings.Join(terms, " OR ") + ")"
+		if negate {
+			return "NOT " + expr, nil
+		}
+		return expr, nil
+	case p.tok.kind == tokEOF || p.tok.kind == tokComma:
+		return fmt.Sprintf("%s:*", labelField(key)), nil
+	default:
+		return "", p.errorf(`expected "=", "!=", "in" or "notin" after %q, got %s`, key, p.tok)
+	}
+}
+
+// key parses a label key: an optional DNS prefix and "/", then a name that
+// starts and ends with a letter or digit.
+func (p *selectorParser) key() (string, error) {
+	if p.tok.kind != tokWord {
+		return "", p.errorf("expected a label key, got %s", p.tok)
+	}
+	key := p.tok.text
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if !validLabelPart(prefix) {
+			return "", p.errorf("invalid label key %q", key)
+		}
+		name = rest
+	}
+	if strings.Contains(name, "/") || !validLabelPart(name) {
+		return "", p.errorf("invalid label key %q", key)
+	}
+	p.next()
+	return key, nil
+}
+
+// value parses a label value. Empty values are allowed after = and !=.
+func (p *selectorParser) value(allowEmpty bool) (string, error) {
+	if p.tok.kind != tokWord {
+		if allowEmpty && (p.tok.kind == tokEOF || p.tok.kind == tokComma) {
+			return "", nil
+		}
+		return "", p.errorf("expected a label value, got %s", p.tok)
+	}
+	v := p.tok.text
+	if strings.Contains(v, "/") || !validLabelPart(v) {
+		return "", p.errorf("invalid label value %q", v)
+	}
+	p.next()
+	return v, nil
+}
+
+// set parses a parenthesized, comma-separated list of values.
+func (p *selectorParser) set() ([]string, error) {
+	if p.tok.kind != tokLParen {
+		return nil, p.errorf(`expected "(", got %s`, p.tok)
+	}
+	p.next()
+
+	var values []string
+	for {
+		v, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		switch p.tok.kind {
+		case tokComma:
+			p.next()
+		case tokRParen:
+			p.next()
+			return values, nil
+		default:
+			return nil, p.errorf(`expected "," or ")", got %s`, p.tok)
+		}
+	}
+}
+
+// labelField returns the filter field for the label key. Keys with dots,
+// dashes or a prefix are quoted, since the filter syntax would otherwise
+// read "." as field traversal and "/" or "-" as operators.
+func labelField(key string) string {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || i > 0 && c >= '0' && c <= '9') {
+			return fmt.Sprintf("labels.%q", key)
+		}
+	}
+	return "labels." + key
+}
+
+// validLabelPart reports whether s starts and ends with a letter or digit.
+func validLabelPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	alnum := func(c byte) bool {
+		return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+	return alnum(s[0]) && alnum(s[len(s)-1])
+}
+
+// JoinFilters combines filter expressions that must all match, skipping
+// empty ones. Each is parenthesized when there is more than one, so OR
+// terms in a raw --filter keep their meaning.
+func JoinFilters(filters ...string) string {
+	var parts []string
+	for _, f := range filters {
+		if f = strings.TrimSpace(f); f != "" {
+			parts = append(parts, f)
+		}
+	}
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	for i, f := range parts {
+		parts[i] = "(" + f + ")"
+	}
+	return strings.Join(parts, " AND ")
+}
@@ -0,0 +1,98 @@
+package cmdutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ---------------------------------------------------------------------------
+// ParseSelector
+// ---------------------------------------------------------------------------
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     string
+	}{
+		{"empty", "  ", ""},
+		{"equals", "env=prod", `labels.env = "prod"`},
+		{"double equals", "env==prod", `labels.env = "prod"`},
+		{"not equals", "team!=core", `NOT labels.team = "core"`},
+		{"empty value", "env=", `labels.env = ""`},
+		{"in", "env in (prod, staging)", `(labels.env = "prod" OR labels.env = "staging")`},
+		{"notin", "env notin (dev)", `NOT (labels.env = "dev")`},
+		{"exists", "canary", `labels.canary:*`},
+		{"not exists", "!deprecated", `NOT labels.deprecated:*`},
+		{"prefixed key", "admiral.io/tier=web", `labels."admiral.io/tier" = "web"`},
+		{"prefixed key not equals", "admiral.io/tier!=web", `NOT labels."admiral.io/tier" = "web"`},
+		{"prefixed key in", "admiral.io/tier in (web,api)", `(labels."admiral.io/tier" = "web" OR labels."admiral.io/tier" = "api")`},
+		{"prefixed key exists", "admiral.io/tier", `labels."admiral.io/tier":*`},
+		{"prefixed key not exists", "!admiral.io/tier", `NOT labels."admiral.io/tier":*`},
+		{"dashed key", "cost-center=ops", `labels."cost-center" = "ops"`},
+		{"digit first key", "3tier=web", `labels."3tier" = "web"`},
+		{
+			"multiple requirements",
+			"env in (prod,staging),team!=core,!deprecated",
+			`(labels.env = "prod" OR labels.env = "staging") AND NOT labels.team = "core" AND NOT labels.deprecated:*`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseSelector(tc.selector)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseSelector_Errors(t *testing.T) {
+	tests := []struct {
+		selector string
+		pos      int
+		msg      string
+	}{
+		{"env in (prod staging)", 13, `expected "," or ")", got "staging"`},
+		{"env in (prod", 12, `expected "," or ")", got end of selector`},
+		{"env in prod", 7, `expected "(", got "prod"`},
+		{"env in ()", 8, `expected a label value, got ")"`},
+		{"env > 3", 4, `expected "=", "!=", "in" or "notin" after "env", got ">"`},
+		{"env=prod,", 9, "expected a label key, got end of selector"},
+		{"!", 1, "expected a label key, got end of selector"},
+		{"-env=prod", 0, `invalid label key "-env"`},
+		{"env=prod-", 4, `invalid label value "prod-"`},
+		{"env=prod tier=web", 9, `expected "," or end of selector, got "tier"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.selector, func(t *testing.T) {
+			_, err := ParseSelector(tc.selector)
+
+			var serr *SelectorError
+			require.True(t, errors.As(err, &serr), "got %v", err)
+			require.Equal(t, tc.pos, serr.Pos)
+			require.Equal(t, tc.msg, serr.Msg)
+		})
+	}
+}
+
+func TestSelectorError_Caret(t *testing.T) {
+	_, err := ParseSelector("env in (prod staging)")
+	require.EqualError(t, err, "invalid selector at column 14: expected \",\" or \")\", got \"staging\"\n"+
+		"  env in (prod staging)\n"+
+		"               ^")
+}
+
+// ---------------------------------------------------------------------------
+// JoinFilters
+// ---------------------------------------------------------------------------
+
+func TestJoinFilters(t *testing.T) {
+	require.Empty(t, JoinFilters("", " "))
+	require.Equal(t, `labels.a = "1"`, JoinFilters("", `labels.a = "1"`))
+	require.Equal(t,
+		`(labels.a = "1") AND (name = "x" OR name = "y")`,
+		JoinFilters(`labels.a = "1"`, "", `name = "x" OR name = "y"`),
+	)
+}
@@ -1,10 +1,50 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvProfile is the environment variable for selecting a named profile.
+	EnvProfile = "ADMIRAL_PROFILE"
+
+	// configFile is the name of the file where profiles are stored.
+	configFile = "config.yaml"
 )
 
+// Config holds the named server profiles and the currently selected one.
+type Config struct {
+	CurrentProfile string              `json:"currentProfile,omitempty" yaml:"current-profile,omitempty"`
+	Profiles       map[string]*Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+
+	// CredentialStore selects where tokens are kept (e.g. "file" or
+	// "secret-service"). Empty means the plaintext file store.
+	CredentialStore string `json:"credentialStore,omitempty" yaml:"credential-store,omitempty"`
+
+	// Views holds named custom-columns specs per resource type, selected
+	// with -o view=NAME. For example Views["cluster"]["ops"] might be
+	// "NAME:.name,UID:.clusterUid,TEAM:.labels.team".
+	Views map[string]map[string]string `json:"views,omitempty" yaml:"views,omitempty"`
+}
+
+// Profile holds the connection, auth, and output settings for a single
+// Admiral control plane. Empty fields fall back to the flag defaults.
+type Profile struct {
+	Server    string   `json:"server,omitempty" yaml:"server,omitempty"`
+	PlainText bool     `json:"plaintext,omitempty" yaml:"plaintext,omitempty"`
+	Insecure  bool     `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+	Issuer    string   `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ClientID  string   `json:"clientId,omitempty" yaml:"client-id,omitempty"`
+	Scopes    []string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Output    string   `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
 // ConfigDir returns the configuration directory for Admiral CLI.
 // Resolution order:
 //  1. $ADMIRAL_CONFIG_DIR (if set)
@@ -26,3 +66,93 @@ func ConfigDir() (string, error) {
 
 	return filepath.Join(homeDir, ".config", "admiral"), nil
 }
+
+// Load reads the config file from the config directory.
+// Returns an empty Config (not an error) if the file does not exist.
+func Load(configDir string) (*Config, error) {
+	path := filepath.Join(configDir, configFile)
+	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from configDir + constant filename
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Config{Profiles: map[string]*Profile{}}, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Profile{}
+	}
+
+	return &cfg, nil
+}
+
+// Save writes the config file to the config directory.
+func Save(configDir string, cfg *Config) error {
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	path := filepath.Join(configDir, configFile)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveProfile returns the name and settings of the active profile.
+// Resolution order:
+//  1. name (from the --profile flag, if set)
+//  2. $ADMIRAL_PROFILE (if set)
+//  3. current-profile from the config file
+//
+// Returns an empty name and nil profile when no profile is selected.
+func (c *Config) ResolveProfile(name string) (string, *Profile, error) {
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+	if name == "" {
+		return "", nil, nil
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("profile %q not found; run 'admiral config profile list' to see available profiles", name)
+	}
+	if p == nil {
+		p = &Profile{}
+	}
+
+	return name, p, nil
+}
+
+// ProfileNames returns the names of all configured profiles in sorted order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// View returns the custom-columns spec of the named view for resource.
+func (c *Config) View(resource, name string) (string, error) {
+	spec, ok := c.Views[resource][name]
+	if !ok {
+		return "", fmt.Errorf("view %q not found for %s; run 'admiral config view list' to see available views", name, resource)
+	}
+	return spec, nil
+}
@@ -61,3 +61,182 @@ func TestConfigDir(t *testing.T) {
 		}
 	})
 }
+
+func TestLoad_NoFile(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.CurrentProfile != "" {
+		t.Fatalf("expected empty current profile, got %q", cfg.CurrentProfile)
+	}
+	if cfg.Profiles == nil || len(cfg.Profiles) != 0 {
+		t.Fatalf("expected empty non-nil profiles, got %v", cfg.Profiles)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+
+	cfg := &Config{
+		CurrentProfile: "staging",
+		Profiles: map[string]*Profile{
+			"staging": {
+				Server:   "api.staging.admiral.io:443",
+				Issuer:   "https://auth.staging.admiral.io",
+				ClientID: "staging-client",
+				Scopes:   []string{"openid"},
+				Output:   "json",
+			},
+			"dev": {Server: "localhost:8080", PlainText: true},
+		},
+	}
+	if err := Save(dir, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, configFile))
+	if err != nil {
+		t.Fatalf("stat config file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("expected file mode 0600, got %o", perm)
+	}
+
+	got, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.CurrentProfile != "staging" {
+		t.Fatalf("CurrentProfile: want %q, got %q", "staging", got.CurrentProfile)
+	}
+	if got.Profiles["staging"].ClientID != "staging-client" {
+		t.Fatalf("ClientID: want %q, got %q", "staging-client", got.Profiles["staging"].ClientID)
+	}
+	if !got.Profiles["dev"].PlainText {
+		t.Fatal("expected dev profile to be plaintext")
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, configFile), []byte("profiles: ["), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := Load(dir); err == nil {
+		t.Fatal("expected error for invalid YAML")
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	cfg := &Config{
+		CurrentProfile: "prod",
+		Profiles: map[string]*Profile{
+			"prod":    {Server: "api.admiral.io:443"},
+			"staging": {Server: "api.staging.admiral.io:443"},
+			"empty":   nil,
+		},
+	}
+
+	t.Run("flag takes precedence", func(t *testing.T) {
+		t.Setenv(EnvProfile, "prod")
+
+		name, p, err := cfg.ResolveProfile("staging")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "staging" || p.Server != "api.staging.admiral.io:443" {
+			t.Fatalf("unexpected profile %q: %+v", name, p)
+		}
+	})
+
+	t.Run("env overrides current profile", func(t *testing.T) {
+		t.Setenv(EnvProfile, "staging")
+
+		name, _, err := cfg.ResolveProfile("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "staging" {
+			t.Fatalf("expected staging, got %q", name)
+		}
+	})
+
+	t.Run("falls back to current profile", func(t *testing.T) {
+		t.Setenv(EnvProfile, "")
+
+		name, _, err := cfg.ResolveProfile("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "prod" {
+			t.Fatalf("expected prod, got %q", name)
+		}
+	})
+
+	t.Run("no profile selected", func(t *testing.T) {
+		t.Setenv(EnvProfile, "")
+
+		name, p, err := (&Config{}).ResolveProfile("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "" || p != nil {
+			t.Fatalf("expected no profile, got %q: %+v", name, p)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, _, err := cfg.ResolveProfile("missing")
+		if err == nil {
+			t.Fatal("expected error for unknown profile")
+		}
+	})
+
+	t.Run("empty profile entry", func(t *testing.T) {
+		_, p, err := cfg.ResolveProfile("empty")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p == nil {
+			t.Fatal("expected non-nil profile")
+		}
+	})
+}
+
+func TestProfileNames(t *testing.T) {
+	cfg := &Config{Profiles: map[string]*Profile{"staging": {}, "dev": {}, "prod": {}}}
+
+	got := cfg.ProfileNames()
+	want := []string{"dev", "prod", "staging"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestView(t *testing.T) {
+	cfg := &Config{Views: map[string]map[string]string{
+		"cluster": {"ops": "NAME:.name,UID:.clusterUid"},
+	}}
+
+	spec, err := cfg.View("cluster", "ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec != "NAME:.name,UID:.clusterUid" {
+		t.Fatalf("unexpected spec %q", spec)
+	}
+
+	if _, err := cfg.View("app", "ops"); err == nil {
+		t.Fatal("expected error for view of another resource")
+	}
+	if _, err := (&Config{}).View("cluster", "ops"); err == nil {
+		t.Fatal("expected error with no views")
+	}
+}
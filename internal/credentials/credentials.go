@@ -8,11 +8,12 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"runtime"
+	"sort"
 	"time"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 
 	"go.admiral.io/sdk/client"
 )
@@ -21,11 +22,25 @@ const (
 	// EnvToken is the environment variable for providing an access token directly.
 	EnvToken = "ADMIRAL_TOKEN"
 
+	// EnvClientSecret is the environment variable for the service account
+	// client secret. Client credentials sessions kept in the file store are
+	// re-minted with it, since the secret isn't written to disk.
+	EnvClientSecret = "ADMIRAL_CLIENT_SECRET"
+
 	// credentialsFile is the name of the file where tokens are stored.
 	credentialsFile = "credentials.json"
 
 	// refreshWindow is how far before expiry we proactively refresh.
 	refreshWindow = 30 * time.Second
+
+	// GrantClientCredentials marks sessions obtained with the OAuth2 client
+	// credentials grant. They are re-minted with the client secret instead
+	// of a refresh token.
+	GrantClientCredentials = "client_credentials"
+
+	// legacySessionKey holds the single session from credentials files written
+	// before sessions were keyed. It is adopted by the first key that looks it up.
+	legacySessionKey = "legacy"
 )
 
 // credentials holds an OAuth2 token and the metadata needed for refresh.
@@ -35,9 +50,53 @@ type credentials struct {
 	RefreshToken string    `json:"refresh_token,omitempty"`
 	Expiry       time.Time `json:"expiry,omitempty"`
 
-	// Stored so ResolveToken can refresh without external config.
+	// Stored so ResolveToken can refresh and Logout can revoke without
+	// external config.
+	Issuer   string `json:"issuer,omitempty"`
 	ClientID string `json:"client_id,omitempty"`
 	TokenURL string `json:"token_url,omitempty"`
+
+	// Set for sessions that are re-minted or re-exchanged rather than
+	// refreshed.
+	GrantType        string   `json:"grant_type,omitempty"`
+	ClientSecret     string   `json:"client_secret,omitempty"`
+	Scopes           []string `json:"scopes,omitempty"`
+	SubjectTokenFile string   `json:"subject_token_file,omitempty"`
+
+	// SubjectTokenHash is the SHA-256 of the federated token the access
+	// token was exchanged for.
+	SubjectTokenHash string `json:"subject_token_hash,omitempty"`
+}
+
+// renewable reports whether a new access token can be obtained without the
+// user logging in again.
+func (c *credentials) renewable() bool {
+	if c.TokenURL == "" {
+		return false
+	}
+	switch c.GrantType {
+	case GrantClientCredentials:
+		return c.ClientSecret != "" || os.Getenv(EnvClientSecret) != ""
+	case GrantTokenExchange:
+		return c.SubjectTokenFile != "" || os.Getenv(EnvFederatedToken) != ""
+	default:
+		return c.RefreshToken != ""
+	}
+}
+
+// credentialsStore is the on-disk layout of the credentials file: one
+// session per key, so logging into one server leaves the others intact.
+type credentialsStore struct {
+	Sessions map[string]*credentials `json:"sessions"`
+}
+
+// Session summarizes a stored login without exposing its tokens.
+type Session struct {
+	Key         string    `json:"key" yaml:"key"`
+	Issuer      string    `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	ClientID    string    `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	Expiry      time.Time `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+	Refreshable bool      `json:"refreshable" yaml:"refreshable"`
 }
 
 // TokenResult holds a resolved token and its auth scheme.
@@ -47,20 +106,34 @@ type TokenResult struct {
 }
 
 // ResolveToken returns a valid access token from the environment variable or
-// credentials file. If the stored token is expired (or close to expiry) and a
-// refresh token is available, it transparently refreshes and persists the new token.
-func ResolveToken(configDir string) (*TokenResult, error) {
+// the session stored under key. If the stored token is expired (or close to
+// expiry) and a refresh token is available, it transparently refreshes and
+// persists the new token.
+//
+// When fed is non-nil and a federated token is supplied via the environment,
+// it is exchanged with fed's issuer instead, so CI jobs need no prior login.
+// The exchanged token is kept under FederatedKey(key), apart from any
+// interactive session. fed's issuer also decides whether a session stored
+// before sessions were keyed by server is adopted under key.
+func ResolveToken(ctx context.Context, configDir, key string, fed *Federation) (*TokenResult, error) {
 	if t := os.Getenv(EnvToken); t != "" {
 		return &TokenResult{Token: t, AuthScheme: client.AuthSchemeToken}, nil
 	}
 
-	creds, err := readCredentials(configDir)
+	if fed != nil && FederatedTokenFromEnv() {
+		return resolveFederated(ctx, configDir, FederatedKey(key), *fed)
+	}
+
+	creds, err := readCredentials(configDir, key)
+	if err != nil && fed != nil {
+		creds, err = adoptLegacy(configDir, key, fed.Issuer)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("not logged in: run 'admiral auth login' first")
+		return nil, fmt.Errorf("not logged in to %s: run 'admiral auth login' first", key)
 	}
 
 	if creds.AccessToken == "" {
-		return nil, fmt.Errorf("not logged in: run 'admiral auth login' first")
+		return nil, fmt.Errorf("not logged in to %s: run 'admiral auth login' first", key)
 	}
 
 	// If the token is still valid, return it directly.
@@ -69,11 +142,11 @@ func ResolveToken(configDir string) (*TokenResult, error) {
 	}
 
 	// Token is expired or about to expire — try to refresh.
-	if creds.RefreshToken == "" || creds.TokenURL == "" {
+	if !creds.renewable() {
 		return nil, fmt.Errorf("session expired: run 'admiral auth login' to re-authenticate")
 	}
 
-	refreshed, err := refreshCredentials(configDir, creds)
+	refreshed, err := renewCredentials(configDir, key, creds)
 	if err != nil {
 		return nil, fmt.Errorf("session expired (refresh failed): run 'admiral auth login' to re-authenticate")
 	}
@@ -81,24 +154,55 @@ func ResolveToken(configDir string) (*TokenResult, error) {
 	return &TokenResult{Token: refreshed.AccessToken, AuthScheme: client.AuthSchemeBearer}, nil
 }
 
-// SaveToken writes an OAuth2 token and refresh metadata to the credentials file.
-func SaveToken(configDir string, token *oauth2.Token, clientID, tokenURL string) error {
+// SaveToken stores an OAuth2 token and refresh metadata under key, leaving
+// sessions for other keys untouched.
+func SaveToken(configDir, key string, token *oauth2.Token, issuer, clientID, tokenURL string) error {
 	creds := &credentials{
 		AccessToken:  token.AccessToken,
 		TokenType:    token.TokenType,
 		RefreshToken: token.RefreshToken,
 		Expiry:       token.Expiry,
+		Issuer:       issuer,
 		ClientID:     clientID,
 		TokenURL:     tokenURL,
 	}
 
-	return writeCredentials(configDir, creds)
+	return writeCredentials(configDir, key, creds)
 }
 
-// GetToken reads the OAuth2 token from the credentials file.
-// Returns nil if no credentials are stored.
-func GetToken(configDir string) (*oauth2.Token, error) {
-	creds, err := readCredentials(configDir)
+// SaveClientCredentials stores a token obtained with the client credentials
+// grant under key, along with the scopes needed to mint a new one when it
+// expires. The client secret is stored only in the Secret Service store: the
+// file store keeps tokens in plaintext, so sessions there are re-minted with
+// $ADMIRAL_CLIENT_SECRET instead, and must log in again without it.
+func SaveClientCredentials(configDir, key string, token *oauth2.Token, issuer, clientID, clientSecret, tokenURL string, scopes []string) error {
+	name, err := storeName(configDir)
+	if err != nil {
+		return err
+	}
+	if name == StoreFile {
+		clientSecret = ""
+	}
+
+	creds := &credentials{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		Expiry:       token.Expiry,
+		Issuer:       issuer,
+		ClientID:     clientID,
+		TokenURL:     tokenURL,
+		GrantType:    GrantClientCredentials,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	}
+
+	return writeCredentials(configDir, key, creds)
+}
+
+// GetToken reads the OAuth2 token stored under key.
+// Returns an error if no session is stored for key.
+func GetToken(configDir, key string) (*oauth2.Token, error) {
+	creds, err := readCredentials(configDir, key)
 	if err != nil {
 		return nil, err
 	}
@@ -111,25 +215,49 @@ func GetToken(configDir string) (*oauth2.Token, error) {
 	}, nil
 }
 
-// ProactiveRefresh refreshes the access token if it is valid but will expire
-// within the given window. This is intended to be called after a command
-//
-//	completes, so the next invocation has a fresh token ready. Callers
-//
-// intentionally ignore errors — this is the best effort.
-func ProactiveRefresh(configDir string, window time.Duration) error {
+// ListSessions returns a summary of every stored session, sorted by key.
+// Returns an empty slice (not an error) if no credentials file exists.
+func ListSessions(configDir string) ([]Session, error) {
+	store, err := readStore(configDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Session{}, nil
+		}
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(store.Sessions))
+	for key, creds := range store.Sessions {
+		sessions = append(sessions, Session{
+			Key:         key,
+			Issuer:      creds.Issuer,
+			ClientID:    creds.ClientID,
+			Expiry:      creds.Expiry,
+			Refreshable: creds.renewable(),
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Key < sessions[j].Key })
+
+	return sessions, nil
+}
+
+// ProactiveRefresh refreshes the access token stored under key if it is
+// valid but will expire within the given window. This is intended to be
+// called after a command completes, so the next invocation has a fresh
+// token ready. Callers intentionally ignore errors — this is best effort.
+func ProactiveRefresh(configDir, key string, window time.Duration) error {
 	if os.Getenv(EnvToken) != "" {
 		return nil // env-supplied tokens are not managed by us
 	}
 
-	creds, err := readCredentials(configDir)
+	creds, err := readCredentials(configDir, key)
 	if err != nil {
 		return err
 	}
 
 	// Nothing to do if there's no expiry, no refresh token, or the token
 	// isn't expiring within the window.
-	if creds.Expiry.IsZero() || creds.RefreshToken == "" || creds.TokenURL == "" {
+	if creds.Expiry.IsZero() || !creds.renewable() {
 		return nil
 	}
 	remaining := time.Until(creds.Expiry)
@@ -137,54 +265,144 @@ func ProactiveRefresh(configDir string, window time.Duration) error {
 		return nil // still fresh enough, or already expired (pre-command refresh handles that)
 	}
 
-	_, err = refreshCredentials(configDir, creds)
+	_, err = renewCredentials(configDir, key, creds)
 	return err
 }
 
-// DeleteToken removes the credentials file.
-func DeleteToken(configDir string) error {
-	path := filepath.Join(configDir, credentialsFile)
-	err := os.Remove(path)
-	if errors.Is(err, os.ErrNotExist) {
+// DeleteToken removes the session stored under key. The legacy session is
+// removed instead if key has none and the legacy session came from issuer,
+// the one server that would adopt it. The stored credentials are removed
+// entirely once the last session is deleted.
+func DeleteToken(configDir, key, issuer string) error {
+	store, err := readStore(configDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if _, ok := store.Sessions[key]; ok {
+		delete(store.Sessions, key)
+	} else if legacy, ok := store.Sessions[legacySessionKey]; ok && legacy != nil && issuer != "" && legacy.Issuer == issuer {
+		// An unadopted legacy session would be handed to this key on the
+		// next lookup, so logging out must remove it too.
+		delete(store.Sessions, legacySessionKey)
+	} else {
 		return nil
 	}
-	return err
+
+	if len(store.Sessions) == 0 {
+		return DeleteAllTokens(configDir)
+	}
+	return writeStore(configDir, store)
 }
 
-func writeCredentials(configDir string, creds *credentials) error {
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+// DeleteAllTokens removes every stored session.
+func DeleteAllTokens(configDir string) error {
+	store, err := openStore(configDir)
+	if err != nil {
+		return err
 	}
 
-	data, err := json.MarshalIndent(creds, "", "  ")
+	return store.Delete()
+}
+
+// writeCredentials stores creds under key, keeping the other sessions. It
+// fails if the stored sessions can't be read, e.g. while the Secret Service
+// collection is locked, rather than overwrite them.
+func writeCredentials(configDir, key string, creds *credentials) error {
+	store, err := readStore(configDir)
 	if err != nil {
-		return fmt.Errorf("failed to marshal credentials: %w", err)
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		store = &credentialsStore{}
+	}
+	if store.Sessions == nil {
+		store.Sessions = map[string]*credentials{}
 	}
 
-	path := filepath.Join(configDir, credentialsFile)
-	if err := os.WriteFile(path, data, 0600); err != nil {
-		return fmt.Errorf("failed to write credentials: %w", err)
+	store.Sessions[key] = creds
+	return writeStore(configDir, store)
+}
+
+func readCredentials(configDir, key string) (*credentials, error) {
+	store, err := readStore(configDir)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if creds, ok := store.Sessions[key]; ok && creds != nil {
+		return creds, nil
+	}
+	return nil, fmt.Errorf("no credentials stored for %s: %w", key, os.ErrNotExist)
 }
 
-func readCredentials(configDir string) (*credentials, error) {
-	path := filepath.Join(configDir, credentialsFile)
+// adoptLegacy moves the legacy session to key, so existing logins survive
+// the upgrade, and returns it. The legacy session doesn't record the server
+// it was issued for, so it is only adopted by a server using the issuer it
+// came from; otherwise its token could be sent to the wrong server.
+func adoptLegacy(configDir, key, issuer string) (*credentials, error) {
+	store, err := readStore(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, ok := store.Sessions[legacySessionKey]
+	if !ok || creds == nil || issuer == "" || creds.Issuer != issuer {
+		return nil, fmt.Errorf("no credentials stored for %s: %w", key, os.ErrNotExist)
+	}
+
+	delete(store.Sessions, legacySessionKey)
+	store.Sessions[key] = creds
+	if err := writeStore(configDir, store); err != nil {
+		slog.Debug("failed to migrate legacy credentials", "error", err)
+	}
+
+	return creds, nil
+}
+
+func writeStore(configDir string, store *credentialsStore) error {
+	backend, err := openStore(configDir)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
 
-	checkFilePermissions(path)
+	return backend.Write(data)
+}
 
-	data, err := os.ReadFile(path) //nolint:gosec // path is constructed from configDir + constant filename
+func readStore(configDir string) (*credentialsStore, error) {
+	backend, err := openStore(configDir)
 	if err != nil {
 		return nil, err
 	}
 
-	var creds credentials
-	if err := json.Unmarshal(data, &creds); err != nil {
+	data, err := backend.Read()
+	if err != nil {
 		return nil, err
 	}
 
-	return &creds, nil
+	var store credentialsStore
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, fmt.Errorf("failed to parse stored credentials: %w; run 'admiral auth logout --all' to remove them", err)
+	}
+	if store.Sessions == nil {
+		store.Sessions = map[string]*credentials{}
+
+		// Files written before sessions were keyed hold one flat session.
+		var legacy credentials
+		if err := json.Unmarshal(data, &legacy); err == nil && legacy.AccessToken != "" {
+			store.Sessions[legacySessionKey] = &legacy
+		}
+	}
+
+	return &store, nil
 }
 
 // checkFilePermissions warns via slog if the credentials file has permissions
@@ -211,8 +429,55 @@ func checkFilePermissions(path string) {
 	}
 }
 
-// refreshCredentials uses the refresh token to obtain a new access token and persists it.
-func refreshCredentials(configDir string, creds *credentials) (*credentials, error) {
+// renewCredentials obtains a new access token for creds using whichever grant
+// the session was created with, and persists it under key.
+func renewCredentials(configDir, key string, creds *credentials) (*credentials, error) {
+	switch creds.GrantType {
+	case GrantClientCredentials:
+		return remintCredentials(configDir, key, creds)
+	case GrantTokenExchange:
+		return exchangeCredentials(context.Background(), configDir, key, creds)
+	default:
+		return refreshCredentials(configDir, key, creds)
+	}
+}
+
+// remintCredentials performs the client credentials grant again with the
+// stored client secret, or $ADMIRAL_CLIENT_SECRET if none is stored, and
+// persists the new token under key.
+func remintCredentials(configDir, key string, creds *credentials) (*credentials, error) {
+	secret := creds.ClientSecret
+	if secret == "" {
+		secret = os.Getenv(EnvClientSecret)
+	}
+
+	cfg := &clientcredentials.Config{
+		ClientID:     creds.ClientID,
+		ClientSecret: secret,
+		TokenURL:     creds.TokenURL,
+		Scopes:       creds.Scopes,
+	}
+
+	token, err := cfg.Token(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	newCreds := *creds
+	newCreds.AccessToken = token.AccessToken
+	newCreds.TokenType = token.TokenType
+	newCreds.Expiry = token.Expiry
+
+	if err := writeCredentials(configDir, key, &newCreds); err != nil {
+		return nil, err
+	}
+
+	return &newCreds, nil
+}
+
+// refreshCredentials uses the refresh token to obtain a new access token and
+// persists it under key.
+func refreshCredentials(configDir, key string, creds *credentials) (*credentials, error) {
 	// Set Expiry to a past time so the oauth2 library considers the token
 	// expired and actually performs the refresh. Without this, oauth2's
 	// internal expiryDelta (10s) causes it to return the existing token
@@ -241,12 +506,13 @@ func refreshCredentials(configDir string, creds *credentials) (*credentials, err
 		TokenType:    refreshed.TokenType,
 		RefreshToken: refreshed.RefreshToken,
 		Expiry:       refreshed.Expiry,
+		Issuer:       creds.Issuer,
 		ClientID:     creds.ClientID,
 		TokenURL:     creds.TokenURL,
 	}
 
 	// Persist the refreshed token so we don't refresh again on the next call.
-	if err := writeCredentials(configDir, newCreds); err != nil {
+	if err := writeCredentials(configDir, key, newCreds); err != nil {
 		return nil, err
 	}
 
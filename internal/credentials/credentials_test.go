@@ -1,7 +1,10 @@
 package credentials
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -13,6 +16,8 @@ import (
 	"go.admiral.io/sdk/client"
 )
 
+const testKey = "api.example.com:443"
+
 func TestSaveAndGetToken(t *testing.T) {
 	dir := t.TempDir()
 
@@ -23,7 +28,7 @@ func TestSaveAndGetToken(t *testing.T) {
 		Expiry:       time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
 
-	if err := SaveToken(dir, token, "client-id", "https://auth.example.com/token"); err != nil {
+	if err := SaveToken(dir, testKey, token, "https://auth.example.com", "client-id", "https://auth.example.com/token"); err != nil {
 		t.Fatalf("SaveToken: %v", err)
 	}
 
@@ -36,7 +41,7 @@ func TestSaveAndGetToken(t *testing.T) {
 		t.Fatalf("expected file mode 0600, got %o", perm)
 	}
 
-	got, err := GetToken(dir)
+	got, err := GetToken(dir, testKey)
 	if err != nil {
 		t.Fatalf("GetToken: %v", err)
 	}
@@ -58,7 +63,7 @@ func TestSaveToken_CreatesConfigDir(t *testing.T) {
 	dir := filepath.Join(t.TempDir(), "nested", "config")
 
 	token := &oauth2.Token{AccessToken: "tok"}
-	if err := SaveToken(dir, token, "cid", "https://auth.example.com/token"); err != nil {
+	if err := SaveToken(dir, testKey, token, "https://auth.example.com", "cid", "https://auth.example.com/token"); err != nil {
 		t.Fatalf("SaveToken: %v", err)
 	}
 
@@ -70,7 +75,7 @@ func TestSaveToken_CreatesConfigDir(t *testing.T) {
 func TestGetToken_NoFile(t *testing.T) {
 	dir := t.TempDir()
 
-	_, err := GetToken(dir)
+	_, err := GetToken(dir, testKey)
 	if err == nil {
 		t.Fatal("expected error when no credentials file exists")
 	}
@@ -80,11 +85,11 @@ func TestDeleteToken(t *testing.T) {
 	dir := t.TempDir()
 
 	token := &oauth2.Token{AccessToken: "to-delete"}
-	if err := SaveToken(dir, token, "cid", "url"); err != nil {
+	if err := SaveToken(dir, testKey, token, "https://auth.example.com", "cid", "url"); err != nil {
 		t.Fatalf("SaveToken: %v", err)
 	}
 
-	if err := DeleteToken(dir); err != nil {
+	if err := DeleteToken(dir, testKey, ""); err != nil {
 		t.Fatalf("DeleteToken: %v", err)
 	}
 
@@ -97,7 +102,7 @@ func TestDeleteToken_NoFile(t *testing.T) {
 	dir := t.TempDir()
 
 	// Should not error when file doesn't exist.
-	if err := DeleteToken(dir); err != nil {
+	if err := DeleteToken(dir, testKey, ""); err != nil {
 		t.Fatalf("DeleteToken on missing file: %v", err)
 	}
 }
@@ -105,7 +110,7 @@ func TestDeleteToken_NoFile(t *testing.T) {
 func TestResolveToken_EnvVar(t *testing.T) {
 	t.Setenv(EnvToken, "env-token-789")
 
-	got, err := ResolveToken(t.TempDir())
+	got, err := ResolveToken(context.Background(), t.TempDir(), testKey, nil)
 	require.NoError(t, err)
 	require.Equal(t, "env-token-789", got.Token)
 	require.Equal(t, client.AuthSchemeToken, got.AuthScheme)
@@ -117,11 +122,11 @@ func TestResolveToken_EnvVarTakesPrecedence(t *testing.T) {
 		AccessToken: "file-token",
 		Expiry:      time.Now().Add(1 * time.Hour),
 	}
-	require.NoError(t, SaveToken(dir, token, "cid", "url"))
+	require.NoError(t, SaveToken(dir, testKey, token, "https://auth.example.com", "cid", "url"))
 
 	t.Setenv(EnvToken, "env-token")
 
-	got, err := ResolveToken(dir)
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
 	require.NoError(t, err)
 	require.Equal(t, "env-token", got.Token)
 	require.Equal(t, client.AuthSchemeToken, got.AuthScheme)
@@ -135,9 +140,9 @@ func TestResolveToken_ValidToken(t *testing.T) {
 		AccessToken: "valid-token",
 		Expiry:      time.Now().Add(1 * time.Hour),
 	}
-	require.NoError(t, SaveToken(dir, token, "cid", "url"))
+	require.NoError(t, SaveToken(dir, testKey, token, "https://auth.example.com", "cid", "url"))
 
-	got, err := ResolveToken(dir)
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
 	require.NoError(t, err)
 	require.Equal(t, "valid-token", got.Token)
 	require.Equal(t, client.AuthSchemeBearer, got.AuthScheme)
@@ -149,9 +154,9 @@ func TestResolveToken_NoExpiry(t *testing.T) {
 
 	// Zero expiry should be treated as valid (e.g. long-lived tokens).
 	token := &oauth2.Token{AccessToken: "no-expiry-token"}
-	require.NoError(t, SaveToken(dir, token, "cid", "url"))
+	require.NoError(t, SaveToken(dir, testKey, token, "https://auth.example.com", "cid", "url"))
 
-	got, err := ResolveToken(dir)
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
 	require.NoError(t, err)
 	require.Equal(t, "no-expiry-token", got.Token)
 	require.Equal(t, client.AuthSchemeBearer, got.AuthScheme)
@@ -160,7 +165,7 @@ func TestResolveToken_NoExpiry(t *testing.T) {
 func TestResolveToken_NotLoggedIn(t *testing.T) {
 	os.Unsetenv(EnvToken)
 
-	_, err := ResolveToken(t.TempDir())
+	_, err := ResolveToken(context.Background(), t.TempDir(), testKey, nil)
 	if err == nil {
 		t.Fatal("expected error when not logged in")
 	}
@@ -175,11 +180,11 @@ func TestResolveToken_EmptyAccessToken(t *testing.T) {
 
 	// Write credentials with empty access token.
 	creds := &credentials{AccessToken: ""}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
-	_, err := ResolveToken(dir)
+	_, err := ResolveToken(context.Background(), dir, testKey, nil)
 	if err == nil {
 		t.Fatal("expected error for empty access token")
 	}
@@ -196,11 +201,11 @@ func TestResolveToken_ExpiredNoRefreshToken(t *testing.T) {
 		AccessToken: "expired-token",
 		Expiry:      time.Now().Add(-1 * time.Minute),
 	}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
-	_, err := ResolveToken(dir)
+	_, err := ResolveToken(context.Background(), dir, testKey, nil)
 	if err == nil {
 		t.Fatal("expected error for expired token without refresh token")
 	}
@@ -213,7 +218,7 @@ func TestProactiveRefresh_EnvVarSkips(t *testing.T) {
 	t.Setenv(EnvToken, "env-token")
 
 	// Should return nil immediately when env var is set.
-	if err := ProactiveRefresh(t.TempDir(), 2*time.Minute); err != nil {
+	if err := ProactiveRefresh(t.TempDir(), testKey, 2*time.Minute); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -221,7 +226,7 @@ func TestProactiveRefresh_EnvVarSkips(t *testing.T) {
 func TestProactiveRefresh_NoCredentialsFile(t *testing.T) {
 	os.Unsetenv(EnvToken)
 
-	err := ProactiveRefresh(t.TempDir(), 2*time.Minute)
+	err := ProactiveRefresh(t.TempDir(), testKey, 2*time.Minute)
 	if err == nil {
 		t.Fatal("expected error when no credentials file exists")
 	}
@@ -232,12 +237,12 @@ func TestProactiveRefresh_NoExpiry(t *testing.T) {
 	os.Unsetenv(EnvToken)
 
 	creds := &credentials{AccessToken: "tok", RefreshToken: "rt", TokenURL: "url"}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
 	// Zero expiry — nothing to refresh.
-	if err := ProactiveRefresh(dir, 2*time.Minute); err != nil {
+	if err := ProactiveRefresh(dir, testKey, 2*time.Minute); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -252,12 +257,12 @@ func TestProactiveRefresh_TokenStillFresh(t *testing.T) {
 		TokenURL:     "url",
 		Expiry:       time.Now().Add(1 * time.Hour),
 	}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
 	// Token expires in 1h, window is 2min — should skip.
-	if err := ProactiveRefresh(dir, 2*time.Minute); err != nil {
+	if err := ProactiveRefresh(dir, testKey, 2*time.Minute); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -272,12 +277,12 @@ func TestProactiveRefresh_TokenAlreadyExpired(t *testing.T) {
 		TokenURL:     "url",
 		Expiry:       time.Now().Add(-1 * time.Minute),
 	}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
 	// Already expired — pre-command refresh handles this, not proactive.
-	if err := ProactiveRefresh(dir, 2*time.Minute); err != nil {
+	if err := ProactiveRefresh(dir, testKey, 2*time.Minute); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -291,12 +296,12 @@ func TestProactiveRefresh_MissingRefreshToken(t *testing.T) {
 		TokenURL:    "url",
 		Expiry:      time.Now().Add(30 * time.Second),
 	}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
 	// Within window but no refresh token — should skip.
-	if err := ProactiveRefresh(dir, 2*time.Minute); err != nil {
+	if err := ProactiveRefresh(dir, testKey, 2*time.Minute); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
@@ -313,11 +318,11 @@ func TestWriteReadCredentials_RoundTrip(t *testing.T) {
 		TokenURL:     "https://auth.example.com/token",
 	}
 
-	if err := writeCredentials(dir, original); err != nil {
+	if err := writeCredentials(dir, testKey, original); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
-	got, err := readCredentials(dir)
+	got, err := readCredentials(dir, testKey)
 	if err != nil {
 		t.Fatalf("readCredentials: %v", err)
 	}
@@ -340,7 +345,7 @@ func TestReadCredentials_InvalidJSON(t *testing.T) {
 		t.Fatalf("WriteFile: %v", err)
 	}
 
-	_, err := readCredentials(dir)
+	_, err := readCredentials(dir, testKey)
 	if err == nil {
 		t.Fatal("expected error for invalid JSON")
 	}
@@ -353,7 +358,7 @@ func TestWriteCredentials_JSONFormat(t *testing.T) {
 		AccessToken: "tok",
 		ClientID:    "cid",
 	}
-	if err := writeCredentials(dir, creds); err != nil {
+	if err := writeCredentials(dir, testKey, creds); err != nil {
 		t.Fatalf("writeCredentials: %v", err)
 	}
 
@@ -367,12 +372,14 @@ func TestWriteCredentials_JSONFormat(t *testing.T) {
 		t.Fatal("credentials file is not valid JSON")
 	}
 
-	var parsed map[string]any
+	var parsed struct {
+		Sessions map[string]map[string]any `json:"sessions"`
+	}
 	if err := json.Unmarshal(data, &parsed); err != nil {
 		t.Fatalf("Unmarshal: %v", err)
 	}
-	if parsed["access_token"] != "tok" {
-		t.Fatalf("expected access_token=tok, got %v", parsed["access_token"])
+	if got := parsed.Sessions[testKey]["access_token"]; got != "tok" {
+		t.Fatalf("expected access_token=tok, got %v", got)
 	}
 }
 
@@ -404,6 +411,254 @@ func TestCheckFilePermissions_MissingFile(t *testing.T) {
 	checkFilePermissions(filepath.Join(t.TempDir(), "nonexistent"))
 }
 
+func TestSessions_AreIndependent(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv(EnvToken)
+
+	prod := &oauth2.Token{AccessToken: "prod-token", Expiry: time.Now().Add(time.Hour)}
+	staging := &oauth2.Token{AccessToken: "staging-token", Expiry: time.Now().Add(time.Hour)}
+	require.NoError(t, SaveToken(dir, "prod:443", prod, "https://auth.prod", "cid", "url"))
+	require.NoError(t, SaveToken(dir, "staging:443", staging, "https://auth.staging", "cid", "url"))
+
+	got, err := ResolveToken(context.Background(), dir, "prod:443", nil)
+	require.NoError(t, err)
+	require.Equal(t, "prod-token", got.Token)
+
+	got, err = ResolveToken(context.Background(), dir, "staging:443", nil)
+	require.NoError(t, err)
+	require.Equal(t, "staging-token", got.Token)
+
+	// Deleting one session leaves the other intact.
+	require.NoError(t, DeleteToken(dir, "staging:443", ""))
+	_, err = ResolveToken(context.Background(), dir, "staging:443", nil)
+	require.ErrorContains(t, err, "not logged in")
+
+	got, err = ResolveToken(context.Background(), dir, "prod:443", nil)
+	require.NoError(t, err)
+	require.Equal(t, "prod-token", got.Token)
+}
+
+func TestDeleteToken_LastSessionRemovesFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok"}, "iss", "cid", "url"))
+
+	require.NoError(t, DeleteToken(dir, testKey, ""))
+
+	_, err := os.Stat(filepath.Join(dir, credentialsFile))
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDeleteAllTokens(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, SaveToken(dir, "a:443", &oauth2.Token{AccessToken: "a"}, "iss", "cid", "url"))
+	require.NoError(t, SaveToken(dir, "b:443", &oauth2.Token{AccessToken: "b"}, "iss", "cid", "url"))
+
+	require.NoError(t, DeleteAllTokens(dir))
+
+	sessions, err := ListSessions(dir)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestListSessions(t *testing.T) {
+	dir := t.TempDir()
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, SaveToken(dir, "staging:443", &oauth2.Token{AccessToken: "s", RefreshToken: "rt", Expiry: expiry}, "https://auth.staging", "cid", "url"))
+	require.NoError(t, SaveToken(dir, "prod:443", &oauth2.Token{AccessToken: "p"}, "https://auth.prod", "cid", "url"))
+
+	sessions, err := ListSessions(dir)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	require.Equal(t, "prod:443", sessions[0].Key)
+	require.False(t, sessions[0].Refreshable)
+
+	require.Equal(t, "staging:443", sessions[1].Key)
+	require.Equal(t, "https://auth.staging", sessions[1].Issuer)
+	require.True(t, sessions[1].Expiry.Equal(expiry))
+	require.True(t, sessions[1].Refreshable)
+}
+
+func TestListSessions_NoFile(t *testing.T) {
+	sessions, err := ListSessions(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestLegacyCredentials_AdoptedOnFirstLookup(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv(EnvToken)
+
+	legacy := `{"access_token": "legacy-token", "issuer": "https://auth.example.com", "client_id": "cid", "token_url": "url"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialsFile), []byte(legacy), 0600))
+
+	got, err := ResolveToken(context.Background(), dir, testKey, &Federation{Issuer: "https://auth.example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "legacy-token", got.Token)
+
+	// The session is now stored under the key that adopted it.
+	sessions, err := ListSessions(dir)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, testKey, sessions[0].Key)
+}
+
+func TestLegacyCredentials_NotAdoptedByOtherIssuer(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv(EnvToken)
+
+	legacy := `{"access_token": "legacy-token", "issuer": "https://auth.prod", "refresh_token": "r", "token_url": "url"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialsFile), []byte(legacy), 0600))
+
+	_, err := ResolveToken(context.Background(), dir, "staging:443", &Federation{Issuer: "https://auth.staging"})
+	require.ErrorContains(t, err, "not logged in")
+	_, err = ResolveToken(context.Background(), dir, "staging:443", nil)
+	require.ErrorContains(t, err, "not logged in")
+	require.Error(t, ProactiveRefresh(dir, "staging:443", time.Hour))
+
+	got, err := ResolveToken(context.Background(), dir, "prod:443", &Federation{Issuer: "https://auth.prod"})
+	require.NoError(t, err)
+	require.Equal(t, "legacy-token", got.Token)
+}
+
+func TestLegacyCredentials_SurviveOtherLogin(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv(EnvToken)
+
+	legacy := `{"access_token": "legacy-token", "issuer": "iss"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialsFile), []byte(legacy), 0600))
+
+	require.NoError(t, SaveToken(dir, "staging:443", &oauth2.Token{AccessToken: "staging-token"}, "iss", "cid", "url"))
+
+	got, err := ResolveToken(context.Background(), dir, "prod:443", &Federation{Issuer: "iss"})
+	require.NoError(t, err)
+	require.Equal(t, "legacy-token", got.Token)
+}
+
+func TestLegacyCredentials_DeletedOnLogout(t *testing.T) {
+	dir := t.TempDir()
+	os.Unsetenv(EnvToken)
+
+	legacy := `{"access_token": "legacy-token", "issuer": "iss"}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialsFile), []byte(legacy), 0600))
+
+	// Logging out of a server with another issuer leaves it for its owner.
+	require.NoError(t, DeleteToken(dir, "other:443", "other-iss"))
+	got, err := ResolveToken(context.Background(), dir, testKey, &Federation{Issuer: "iss"})
+	require.NoError(t, err)
+	require.Equal(t, "legacy-token", got.Token)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialsFile), []byte(legacy), 0600))
+	require.NoError(t, DeleteToken(dir, testKey, "iss"))
+
+	_, err = ResolveToken(context.Background(), dir, testKey, &Federation{Issuer: "iss"})
+	require.ErrorContains(t, err, "not logged in")
+}
+
+func TestResolveToken_RemintsClientCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+
+	var grants int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		require.Equal(t, "admiral.api", r.FormValue("scope"))
+		grants++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "reminted", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer srv.Close()
+
+	expired := &oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(-time.Minute)}
+	require.NoError(t, SaveClientCredentials(dir, testKey, expired, "iss", "svc", "secret", srv.URL, []string{"admiral.api"}))
+	t.Setenv(EnvClientSecret, "secret")
+
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, "reminted", got.Token)
+	require.Equal(t, 1, grants)
+
+	// The new token is persisted; the secret is never written to the file.
+	creds, err := readCredentials(dir, testKey)
+	require.NoError(t, err)
+	require.Equal(t, "reminted", creds.AccessToken)
+	require.Empty(t, creds.ClientSecret)
+	require.Equal(t, GrantClientCredentials, creds.GrantType)
+	require.True(t, creds.Expiry.After(time.Now()))
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFile))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "secret")
+}
+
+func TestSaveClientCredentials_FileStoreNeedsSecretEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvClientSecret, "")
+
+	expired := &oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(-time.Minute)}
+	require.NoError(t, SaveClientCredentials(dir, testKey, expired, "iss", "svc", "secret", "http://127.0.0.1:0", nil))
+
+	_, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.ErrorContains(t, err, "expired")
+}
+
+func TestSaveClientCredentials_KeepsSecretOutsideFileStore(t *testing.T) {
+	dir := t.TempDir()
+	useMemoryStore(t)
+
+	tok := &oauth2.Token{AccessToken: "at", Expiry: time.Now().Add(time.Hour)}
+	require.NoError(t, SaveClientCredentials(dir, testKey, tok, "iss", "svc", "secret", "url", nil))
+
+	creds, err := readCredentials(dir, testKey)
+	require.NoError(t, err)
+	require.Equal(t, "secret", creds.ClientSecret)
+}
+
+func TestProactiveRefresh_RemintsClientCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "reminted", "expires_in": 3600}`))
+	}))
+	defer srv.Close()
+
+	expiring := &oauth2.Token{AccessToken: "old", Expiry: time.Now().Add(30 * time.Second)}
+	require.NoError(t, SaveClientCredentials(dir, testKey, expiring, "iss", "svc", "secret", srv.URL, nil))
+	t.Setenv(EnvClientSecret, "secret")
+
+	require.NoError(t, ProactiveRefresh(dir, testKey, time.Minute))
+
+	tok, err := GetToken(dir, testKey)
+	require.NoError(t, err)
+	require.Equal(t, "reminted", tok.AccessToken)
+}
+
+func TestCredentials_Renewable(t *testing.T) {
+	tests := []struct {
+		name  string
+		creds credentials
+		want  bool
+	}{
+		{name: "refresh token", creds: credentials{RefreshToken: "rt", TokenURL: "url"}, want: true},
+		{name: "refresh token without token URL", creds: credentials{RefreshToken: "rt"}, want: false},
+		{name: "nothing", creds: credentials{TokenURL: "url"}, want: false},
+		{name: "client credentials", creds: credentials{GrantType: GrantClientCredentials, ClientSecret: "s", TokenURL: "url"}, want: true},
+		{name: "client credentials without secret", creds: credentials{GrantType: GrantClientCredentials, TokenURL: "url"}, want: false},
+	}
+	t.Setenv(EnvClientSecret, "")
+	t.Setenv(EnvFederatedToken, "")
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.creds.renewable())
+		})
+	}
+}
+
 func containsStr(s, substr string) bool {
 	return len(s) >= len(substr) && searchStr(s, substr)
 }
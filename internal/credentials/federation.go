@@ -0,0 +1,233 @@
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+
+	"go.admiral.io/sdk/client"
+)
+
+const (
+	// EnvFederatedToken is the environment variable for providing a CI-issued
+	// OIDC token to exchange for an Admiral access token.
+	EnvFederatedToken = "ADMIRAL_FEDERATED_TOKEN"
+
+	// EnvFederatedTokenFile is the environment variable for a file holding a
+	// CI-issued OIDC token. The file is re-read on every exchange, so tokens
+	// rotated on disk by the runner are picked up.
+	EnvFederatedTokenFile = "ADMIRAL_FEDERATED_TOKEN_FILE"
+
+	// GrantTokenExchange marks sessions obtained with the OAuth 2.0 token
+	// exchange grant (RFC 8693). They are renewed by exchanging the
+	// federated token again.
+	GrantTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	tokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// Federation identifies the issuer and client a federated token is exchanged
+// with.
+type Federation struct {
+	Issuer   string
+	ClientID string
+
+	// TokenFile holds the federated token. Empty falls back to
+	// $ADMIRAL_FEDERATED_TOKEN_FILE, then $ADMIRAL_FEDERATED_TOKEN.
+	TokenFile string
+}
+
+// FederatedTokenFromEnv reports whether a federated token is supplied via
+// $ADMIRAL_FEDERATED_TOKEN or $ADMIRAL_FEDERATED_TOKEN_FILE.
+func FederatedTokenFromEnv() bool {
+	return os.Getenv(EnvFederatedToken) != "" || os.Getenv(EnvFederatedTokenFile) != ""
+}
+
+// Federate exchanges a federated token for an Admiral access token at the
+// issuer's token endpoint and stores the session under key. The session is
+// re-exchanged automatically when it expires.
+func Federate(ctx context.Context, configDir, key string, fed Federation) (*TokenResult, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	provider, err := oidc.NewProvider(oidc.ClientContext(ctx, httpClient), fed.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OIDC provider %q: %w", fed.Issuer, err)
+	}
+
+	tokenFile, err := fed.tokenFile()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := &credentials{
+		Issuer:           fed.Issuer,
+		ClientID:         fed.ClientID,
+		TokenURL:         provider.Endpoint().TokenURL,
+		GrantType:        GrantTokenExchange,
+		SubjectTokenFile: tokenFile,
+	}
+
+	creds, err = exchangeCredentials(ctx, configDir, key, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenResult{Token: creds.AccessToken, AuthScheme: client.AuthSchemeBearer}, nil
+}
+
+// tokenFile returns the absolute path of the file holding the federated
+// token: fed.TokenFile, then $ADMIRAL_FEDERATED_TOKEN_FILE. Empty means the
+// token is read from $ADMIRAL_FEDERATED_TOKEN.
+func (fed Federation) tokenFile() (string, error) {
+	path := fed.TokenFile
+	if path == "" {
+		path = os.Getenv(EnvFederatedTokenFile)
+	}
+	if path == "" {
+		return "", nil
+	}
+	return filepath.Abs(path)
+}
+
+// FederatedKey returns the key under which tokens exchanged for a federated
+// token from the environment are stored for the server keyed by key, so they
+// never replace a session the user logged in to interactively.
+func FederatedKey(key string) string {
+	return key + "#federated"
+}
+
+// resolveFederated returns the stored exchanged token under key while it is
+// valid and was exchanged for the federated token now in the environment,
+// and exchanges that token otherwise. Matching on the federated token keeps
+// a job from reusing a token exchanged for another identity.
+func resolveFederated(ctx context.Context, configDir, key string, fed Federation) (*TokenResult, error) {
+	tokenFile, err := fed.tokenFile()
+	if err != nil {
+		return nil, err
+	}
+	subject, err := (&credentials{SubjectTokenFile: tokenFile}).subjectToken()
+	if err != nil {
+		return nil, fmt.Errorf("federated token exchange failed: %w", err)
+	}
+
+	if creds, err := readCredentials(configDir, key); err == nil &&
+		creds.GrantType == GrantTokenExchange && creds.AccessToken != "" &&
+		creds.SubjectTokenHash == hashSubjectToken(subject) &&
+		(creds.Expiry.IsZero() || time.Until(creds.Expiry) > refreshWindow) {
+		return &TokenResult{Token: creds.AccessToken, AuthScheme: client.AuthSchemeBearer}, nil
+	}
+
+	result, err := Federate(ctx, configDir, key, fed)
+	if err != nil {
+		return nil, fmt.Errorf("federated token exchange failed: %w", err)
+	}
+	return result, nil
+}
+
+// hashSubjectToken returns the SHA-256 of a federated token, stored with the
+// session instead of the token itself.
+func hashSubjectToken(subject string) string {
+	sum := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(sum[:])
+}
+
+// subjectToken reads the federated token to exchange: the session's token
+// file if it has one, then $ADMIRAL_FEDERATED_TOKEN.
+func (c *credentials) subjectToken() (string, error) {
+	if c.SubjectTokenFile != "" {
+		data, err := os.ReadFile(c.SubjectTokenFile) //nolint:gosec // path is supplied by the user on purpose
+		if err != nil {
+			return "", fmt.Errorf("failed to read federated token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if t := os.Getenv(EnvFederatedToken); t != "" {
+		return t, nil
+	}
+
+	return "", fmt.Errorf("no federated token: set %s or %s", EnvFederatedToken, EnvFederatedTokenFile)
+}
+
+// exchangeCredentials performs the token exchange for creds and persists the
+// resulting access token under key.
+func exchangeCredentials(ctx context.Context, configDir, key string, creds *credentials) (*credentials, error) {
+	subject, err := creds.subjectToken()
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		return nil, errors.New("federated token is empty")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", GrantTokenExchange)
+	data.Set("subject_token", subject)
+	data.Set("subject_token_type", tokenTypeJWT)
+	data.Set("requested_token_type", tokenTypeAccessToken)
+	if creds.ClientID != "" {
+		data.Set("client_id", creds.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, creds.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort cleanup
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("invalid token exchange response (HTTP %d)", resp.StatusCode)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("token exchange rejected: %s: %s", res.Error, res.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || res.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange failed (HTTP %d)", resp.StatusCode)
+	}
+
+	newCreds := *creds
+	newCreds.AccessToken = res.AccessToken
+	newCreds.TokenType = res.TokenType
+	newCreds.SubjectTokenHash = hashSubjectToken(subject)
+	newCreds.Expiry = time.Time{}
+	if res.ExpiresIn > 0 {
+		newCreds.Expiry = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+
+	if err := writeCredentials(configDir, key, &newCreds); err != nil {
+		return nil, err
+	}
+
+	return &newCreds, nil
+}
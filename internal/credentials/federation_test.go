@@ -0,0 +1,199 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newExchangeIdP serves OIDC discovery and an RFC 8693 token endpoint that
+// trades subject token "ci-<n>" for access token "admiral-<n>".
+func newExchangeIdP(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var exchanges atomic.Int32
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, GrantTokenExchange, r.FormValue("grant_type"))
+		require.Equal(t, tokenTypeJWT, r.FormValue("subject_token_type"))
+		require.Equal(t, tokenTypeAccessToken, r.FormValue("requested_token_type"))
+		require.Equal(t, "cid", r.FormValue("client_id"))
+		exchanges.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+		subject := r.FormValue("subject_token")
+		if len(subject) < 3 || subject[:3] != "ci-" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant", "error_description": "untrusted issuer"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":      "admiral-" + subject[3:],
+			"issued_token_type": tokenTypeAccessToken,
+			"token_type":        "Bearer",
+			"expires_in":        expiresIn,
+		})
+	})
+
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &exchanges
+}
+
+func TestResolveToken_FederatedFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	t.Setenv(EnvFederatedToken, "ci-1")
+	srv, exchanges := newExchangeIdP(t, 3600)
+	fed := &Federation{Issuer: srv.URL, ClientID: "cid"}
+
+	got, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-1", got.Token)
+
+	// The exchanged token is cached until it nears expiry.
+	got, err = ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-1", got.Token)
+	require.Equal(t, int32(1), exchanges.Load())
+}
+
+func TestResolveToken_FederatedReexchangesOnExpiry(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	t.Setenv(EnvFederatedToken, "ci-1")
+	srv, exchanges := newExchangeIdP(t, 10) // inside the refresh window
+	fed := &Federation{Issuer: srv.URL, ClientID: "cid"}
+
+	_, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+
+	got, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-1", got.Token)
+	require.Equal(t, int32(2), exchanges.Load())
+}
+
+func TestResolveToken_FederatedCacheKeyedOnSubject(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	t.Setenv(EnvFederatedToken, "ci-1")
+	srv, exchanges := newExchangeIdP(t, 3600)
+	fed := &Federation{Issuer: srv.URL, ClientID: "cid"}
+
+	_, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+
+	// Another job's identity doesn't reuse the token cached for the first.
+	t.Setenv(EnvFederatedToken, "ci-2")
+	got, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-2", got.Token)
+	require.Equal(t, int32(2), exchanges.Load())
+
+	data, err := os.ReadFile(filepath.Join(dir, credentialsFile))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "ci-2")
+}
+
+func TestResolveToken_FederatedKeepsInteractiveSession(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	t.Setenv(EnvFederatedToken, "")
+	srv, _ := newExchangeIdP(t, 3600)
+	fed := &Federation{Issuer: srv.URL, ClientID: "cid"}
+
+	token := &oauth2.Token{AccessToken: "interactive", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	require.NoError(t, SaveToken(dir, testKey, token, srv.URL, "cid", srv.URL+"/token"))
+
+	t.Setenv(EnvFederatedToken, "ci-1")
+	got, err := ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-1", got.Token)
+
+	// The interactive login, refresh token included, is still there once
+	// the federated token is gone from the environment.
+	t.Setenv(EnvFederatedToken, "")
+	got, err = ResolveToken(context.Background(), dir, testKey, fed)
+	require.NoError(t, err)
+	require.Equal(t, "interactive", got.Token)
+
+	creds, err := readCredentials(dir, testKey)
+	require.NoError(t, err)
+	require.Equal(t, "refresh", creds.RefreshToken)
+}
+
+func TestResolveToken_FederatedIgnoredWithoutFederation(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedToken, "ci-1")
+
+	_, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.ErrorContains(t, err, "not logged in")
+}
+
+func TestResolveToken_FederatedRejected(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	t.Setenv(EnvFederatedToken, "forged")
+	srv, _ := newExchangeIdP(t, 3600)
+
+	_, err := ResolveToken(context.Background(), dir, testKey, &Federation{Issuer: srv.URL, ClientID: "cid"})
+	require.ErrorContains(t, err, "federated token exchange failed")
+	require.ErrorContains(t, err, "untrusted issuer")
+}
+
+func TestFederate_TokenFileReadOnRenewal(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvFederatedToken, "")
+	t.Setenv(EnvFederatedTokenFile, "")
+	srv, _ := newExchangeIdP(t, 3600)
+
+	tokenFile := filepath.Join(t.TempDir(), "ci-token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("ci-1\n"), 0600))
+
+	got, err := Federate(context.Background(), dir, testKey, Federation{Issuer: srv.URL, ClientID: "cid", TokenFile: tokenFile})
+	require.NoError(t, err)
+	require.Equal(t, "admiral-1", got.Token)
+
+	sessions, err := ListSessions(dir)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.True(t, sessions[0].Refreshable)
+
+	// Expire the session and rotate the token on disk, as a CI runner would.
+	creds, err := readCredentials(dir, testKey)
+	require.NoError(t, err)
+	creds.Expiry = time.Now().Add(-time.Minute)
+	require.NoError(t, writeCredentials(dir, testKey, creds))
+	require.NoError(t, os.WriteFile(tokenFile, []byte("ci-2\n"), 0600))
+
+	result, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, "admiral-2", result.Token)
+}
@@ -0,0 +1,231 @@
+//go:build linux
+
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	secretServiceName         = "org.freedesktop.secrets"
+	secretServicePath         = dbus.ObjectPath("/org/freedesktop/secrets")
+	secretServiceInterface    = "org.freedesktop.Secret.Service"
+	secretCollectionInterface = "org.freedesktop.Secret.Collection"
+	secretItemInterface       = "org.freedesktop.Secret.Item"
+	secretPromptInterface     = "org.freedesktop.Secret.Prompt"
+	secretSessionInterface    = "org.freedesktop.Secret.Session"
+
+	// noPrompt is returned in place of a prompt path when none is needed.
+	noPrompt = dbus.ObjectPath("/")
+)
+
+// secret mirrors the Secret Service (oayays) secret struct.
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// secretServiceStore keeps the credentials for one config directory as a
+// single item in the default Secret Service collection.
+type secretServiceStore struct {
+	conn       *dbus.Conn
+	session    dbus.ObjectPath
+	label      string
+	attributes map[string]string
+}
+
+func newSecretServiceStore(configDir string) (Store, error) {
+	dir, err := filepath.Abs(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the session bus: %w", err)
+	}
+
+	// The "plain" algorithm sends secrets unencrypted over the session bus,
+	// which is only reachable by the current user.
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = conn.Object(secretServiceName, secretServicePath).
+		Call(secretServiceInterface+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&output, &session)
+	if err != nil {
+		return nil, fmt.Errorf("secret service unavailable: %w", err)
+	}
+
+	return &secretServiceStore{
+		conn:    conn,
+		session: session,
+		label:   fmt.Sprintf("Admiral CLI credentials (%s)", dir),
+		attributes: map[string]string{
+			"application": "admiral",
+			"config-dir":  dir,
+		},
+	}, nil
+}
+
+func (s *secretServiceStore) Read() ([]byte, error) {
+	item, err := s.find()
+	if err != nil {
+		return nil, err
+	}
+
+	var sec secret
+	err = s.conn.Object(secretServiceName, item).
+		Call(secretItemInterface+".GetSecret", 0, s.session).
+		Store(&sec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials from secret service: %w", err)
+	}
+
+	return sec.Value, nil
+}
+
+func (s *secretServiceStore) Write(data []byte) error {
+	var collection dbus.ObjectPath
+	err := s.service().Call(secretServiceInterface+".ReadAlias", 0, "default").Store(&collection)
+	if err != nil {
+		return fmt.Errorf("failed to find default secret service collection: %w", err)
+	}
+	if collection == noPrompt {
+		return errors.New("secret service has no default collection")
+	}
+	if err := s.unlock(collection); err != nil {
+		return err
+	}
+
+	props := map[string]dbus.Variant{
+		secretItemInterface + ".Label":      dbus.MakeVariant(s.label),
+		secretItemInterface + ".Attributes": dbus.MakeVariant(s.attributes),
+	}
+	sec := secret{
+		Session:     s.session,
+		Parameters:  []byte{},
+		Value:       data,
+		ContentType: "application/json",
+	}
+
+	var item, prompt dbus.ObjectPath
+	err = s.conn.Object(secretServiceName, collection).
+		Call(secretCollectionInterface+".CreateItem", 0, props, sec, true).
+		Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("failed to write credentials to secret service: %w", err)
+	}
+
+	return s.prompt(prompt)
+}
+
+func (s *secretServiceStore) Delete() error {
+	item, err := s.find()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var prompt dbus.ObjectPath
+	err = s.conn.Object(secretServiceName, item).
+		Call(secretItemInterface+".Delete", 0).
+		Store(&prompt)
+	if err != nil {
+		return fmt.Errorf("failed to delete credentials from secret service: %w", err)
+	}
+
+	return s.prompt(prompt)
+}
+
+// Close closes the Secret Service session. The session bus connection is
+// shared by the process and stays open.
+func (s *secretServiceStore) Close() error {
+	if err := s.conn.Object(secretServiceName, s.session).Call(secretSessionInterface+".Close", 0).Err; err != nil {
+		return fmt.Errorf("failed to close secret service session: %w", err)
+	}
+	return nil
+}
+
+func (s *secretServiceStore) service() dbus.BusObject {
+	return s.conn.Object(secretServiceName, secretServicePath)
+}
+
+// find returns the item holding the credentials for this config directory,
+// unlocking it first if necessary.
+func (s *secretServiceStore) find() (dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	err := s.service().Call(secretServiceInterface+".SearchItems", 0, s.attributes).Store(&unlocked, &locked)
+	if err != nil {
+		return "", fmt.Errorf("failed to search secret service: %w", err)
+	}
+
+	switch {
+	case len(unlocked) > 0:
+		return unlocked[0], nil
+	case len(locked) > 0:
+		if err := s.unlock(locked[0]); err != nil {
+			return "", err
+		}
+		return locked[0], nil
+	default:
+		return "", fmt.Errorf("no credentials in secret service: %w", os.ErrNotExist)
+	}
+}
+
+func (s *secretServiceStore) unlock(path dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := s.service().Call(secretServiceInterface+".Unlock", 0, []dbus.ObjectPath{path}).Store(&unlocked, &prompt)
+	if err != nil {
+		return fmt.Errorf("failed to unlock secret service: %w", err)
+	}
+
+	return s.prompt(prompt)
+}
+
+// prompt shows a Secret Service prompt (e.g. the keyring password dialog)
+// and waits for the user to complete or dismiss it.
+func (s *secretServiceStore) prompt(path dbus.ObjectPath) error {
+	if path == noPrompt || path == "" {
+		return nil
+	}
+
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface(secretPromptInterface),
+		dbus.WithMatchMember("Completed"),
+	}
+	if err := s.conn.AddMatchSignal(match...); err != nil {
+		return fmt.Errorf("failed to watch secret service prompt: %w", err)
+	}
+	defer s.conn.RemoveMatchSignal(match...) //nolint:errcheck // best-effort cleanup
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceName, path).Call(secretPromptInterface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("failed to show secret service prompt: %w", err)
+	}
+
+	for sig := range signals {
+		if sig.Path != path || sig.Name != secretPromptInterface+".Completed" || len(sig.Body) == 0 {
+			continue
+		}
+		if dismissed, _ := sig.Body[0].(bool); dismissed {
+			return errors.New("secret service prompt was dismissed")
+		}
+		return nil
+	}
+
+	return errors.New("session bus closed while waiting for secret service prompt")
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package credentials
+
+import "errors"
+
+func newSecretServiceStore(string) (Store, error) {
+	return nil, errors.New("the secret-service credential store is only supported on Linux")
+}
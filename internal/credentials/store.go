@@ -0,0 +1,215 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.admiral.io/cli/internal/config"
+)
+
+const (
+	// EnvCredentialStore is the environment variable for selecting the
+	// credential store backend. It overrides credential-store in config.yaml.
+	EnvCredentialStore = "ADMIRAL_CREDENTIAL_STORE"
+
+	// StoreFile keeps credentials in a 0600 JSON file in the config directory.
+	StoreFile = "file"
+
+	// StoreSecretService keeps credentials in the Linux Secret Service
+	// (GNOME Keyring, KWallet and compatible daemons) over D-Bus.
+	StoreSecretService = "secret-service"
+)
+
+// Store persists the serialized credentials for a single config directory.
+// Implementations only move bytes; session bookkeeping happens in this package.
+type Store interface {
+	// Read returns the stored data, or an error wrapping os.ErrNotExist if
+	// nothing has been stored yet.
+	Read() ([]byte, error)
+
+	// Write replaces the stored data.
+	Write(data []byte) error
+
+	// Delete removes the stored data. Deleting an empty store is not an error.
+	Delete() error
+
+	// Close releases the resources held by the store, such as a D-Bus
+	// session.
+	Close() error
+}
+
+// stores maps backend names to their constructors. Tests register fakes here.
+var stores = map[string]func(configDir string) (Store, error){
+	StoreFile:          newFileStore,
+	StoreSecretService: newSecretServiceStore,
+}
+
+// StoreNames returns the names of the available credential store backends.
+func StoreNames() []string {
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// openedStore identifies a store opened by openStore.
+type openedStore struct {
+	configDir string
+	name      string
+}
+
+var (
+	openedMu sync.Mutex
+	opened   = map[openedStore]Store{}
+)
+
+// openStore returns the credential store selected for configDir, opening it
+// once per process. When a store other than the file store is first opened,
+// an existing credentials file is moved into it so plaintext tokens don't
+// linger on disk.
+func openStore(configDir string) (Store, error) {
+	name, err := storeName(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	openedMu.Lock()
+	defer openedMu.Unlock()
+
+	key := openedStore{configDir: configDir, name: name}
+	if store, ok := opened[key]; ok {
+		return store, nil
+	}
+
+	open, ok := stores[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown credential store %q: must be one of %s", name, strings.Join(StoreNames(), ", "))
+	}
+
+	store, err := open(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s credential store: %w", name, err)
+	}
+
+	if name != StoreFile {
+		migrateFileStore(configDir, name, store)
+	}
+
+	opened[key] = store
+	return store, nil
+}
+
+// CloseStores closes the credential stores opened so far. It is called once
+// the command has finished; a later lookup opens the store again.
+func CloseStores() {
+	openedMu.Lock()
+	defer openedMu.Unlock()
+
+	for key, store := range opened {
+		if err := store.Close(); err != nil {
+			slog.Debug("failed to close credential store", "store", key.name, "error", err)
+		}
+		delete(opened, key)
+	}
+}
+
+// storeName resolves the backend name: $ADMIRAL_CREDENTIAL_STORE first, then
+// credential-store in config.yaml, then the file store.
+func storeName(configDir string) (string, error) {
+	if name := os.Getenv(EnvCredentialStore); name != "" {
+		return name, nil
+	}
+
+	cfg, err := config.Load(configDir)
+	if err != nil {
+		return "", err
+	}
+	if cfg.CredentialStore != "" {
+		return cfg.CredentialStore, nil
+	}
+
+	return StoreFile, nil
+}
+
+// migrateFileStore moves the plaintext credentials file into dst. If dst
+// already holds credentials the file is left alone rather than overwriting
+// them. This is best-effort — failures are logged and the file is kept.
+func migrateFileStore(configDir, name string, dst Store) {
+	src := &fileStore{path: filepath.Join(configDir, credentialsFile)}
+
+	data, err := src.Read()
+	if err != nil {
+		return // nothing to migrate
+	}
+
+	switch _, err := dst.Read(); {
+	case err == nil:
+		slog.Warn("credentials file not migrated: credential store already has credentials",
+			"store", name,
+			"path", src.path,
+			"hint", fmt.Sprintf("remove %s once you have confirmed your sessions", src.path),
+		)
+		return
+	case !errors.Is(err, os.ErrNotExist):
+		slog.Warn("credentials file not migrated", "store", name, "error", err)
+		return
+	}
+
+	if err := dst.Write(data); err != nil {
+		slog.Warn("credentials file not migrated", "store", name, "error", err)
+		return
+	}
+	if err := src.Delete(); err != nil {
+		slog.Warn("failed to remove migrated credentials file", "path", src.path, "error", err)
+		return
+	}
+
+	slog.Info("moved credentials file into credential store", "store", name)
+}
+
+// fileStore keeps credentials as a JSON file readable only by the owner.
+type fileStore struct {
+	path string
+}
+
+func newFileStore(configDir string) (Store, error) {
+	return &fileStore{path: filepath.Join(configDir, credentialsFile)}, nil
+}
+
+func (s *fileStore) Read() ([]byte, error) {
+	checkFilePermissions(s.path)
+
+	return os.ReadFile(s.path) //nolint:gosec // path is constructed from configDir + constant filename
+}
+
+func (s *fileStore) Write(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+func (s *fileStore) Delete() error {
+	err := os.Remove(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
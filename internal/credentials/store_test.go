@@ -0,0 +1,193 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"go.admiral.io/cli/internal/config"
+)
+
+// memoryStore is an in-process Store used to exercise non-file backends.
+type memoryStore struct {
+	data []byte
+	// readErr, if set, is returned by Read, as by a locked collection.
+	readErr error
+	// opens counts the times the store is open.
+	opens int
+}
+
+func (s *memoryStore) Read() ([]byte, error) {
+	if s.readErr != nil {
+		return nil, s.readErr
+	}
+	if s.data == nil {
+		return nil, fmt.Errorf("empty store: %w", os.ErrNotExist)
+	}
+	return s.data, nil
+}
+
+func (s *memoryStore) Write(data []byte) error {
+	s.data = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memoryStore) Delete() error {
+	s.data = nil
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	s.opens--
+	return nil
+}
+
+// useMemoryStore registers a fresh memoryStore as the "memory" backend and
+// selects it via the environment for the duration of the test.
+func useMemoryStore(t *testing.T) *memoryStore {
+	t.Helper()
+
+	store := &memoryStore{}
+	stores["memory"] = func(string) (Store, error) {
+		store.opens++
+		return store, nil
+	}
+	t.Cleanup(func() {
+		CloseStores()
+		delete(stores, "memory")
+	})
+	t.Setenv(EnvCredentialStore, "memory")
+
+	return store
+}
+
+func TestStore_DefaultsToFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvCredentialStore, "")
+
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok"}, "iss", "cid", "url"))
+
+	_, err := os.Stat(filepath.Join(dir, credentialsFile))
+	require.NoError(t, err)
+}
+
+func TestStore_SelectedByEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	store := useMemoryStore(t)
+
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok", Expiry: time.Now().Add(time.Hour)}, "iss", "cid", "url"))
+	require.NotEmpty(t, store.data)
+
+	_, err := os.Stat(filepath.Join(dir, credentialsFile))
+	require.True(t, os.IsNotExist(err), "file store should not be written")
+
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, "tok", got.Token)
+
+	require.NoError(t, DeleteAllTokens(dir))
+	require.Nil(t, store.data)
+}
+
+func TestStore_SelectedByConfig(t *testing.T) {
+	dir := t.TempDir()
+	store := useMemoryStore(t)
+	t.Setenv(EnvCredentialStore, "")
+
+	require.NoError(t, config.Save(dir, &config.Config{CredentialStore: "memory"}))
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok"}, "iss", "cid", "url"))
+	require.NotEmpty(t, store.data)
+}
+
+func TestStore_Unknown(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvCredentialStore, "vault")
+
+	err := SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok"}, "iss", "cid", "url")
+	require.ErrorContains(t, err, `unknown credential store "vault"`)
+	require.ErrorContains(t, err, StoreFile)
+}
+
+func TestStore_MigratesFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+
+	t.Setenv(EnvCredentialStore, StoreFile)
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "file-token"}, "iss", "cid", "url"))
+
+	store := useMemoryStore(t)
+
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, "file-token", got.Token)
+	require.NotEmpty(t, store.data)
+
+	_, err = os.Stat(filepath.Join(dir, credentialsFile))
+	require.True(t, os.IsNotExist(err), "credentials file should be removed after migration")
+}
+
+func TestStore_MigrationKeepsExistingCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+
+	t.Setenv(EnvCredentialStore, StoreFile)
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "file-token"}, "iss", "cid", "url"))
+
+	store := useMemoryStore(t)
+	require.NoError(t, store.Write([]byte(`{"sessions": {"api.example.com:443": {"access_token": "store-token"}}}`)))
+
+	got, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, "store-token", got.Token)
+
+	_, err = os.Stat(filepath.Join(dir, credentialsFile))
+	require.NoError(t, err, "credentials file should be kept when the store already has credentials")
+}
+
+func TestStore_OpenedOncePerProcess(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvToken, "")
+	store := useMemoryStore(t)
+
+	require.NoError(t, SaveToken(dir, testKey, &oauth2.Token{AccessToken: "tok"}, "iss", "cid", "url"))
+	_, err := ResolveToken(context.Background(), dir, testKey, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.opens)
+
+	CloseStores()
+	require.Equal(t, 0, store.opens)
+}
+
+func TestStoreNames(t *testing.T) {
+	require.Equal(t, []string{StoreFile, StoreSecretService}, StoreNames())
+}
+
+func TestStore_UnreadableKeepsOtherSessions(t *testing.T) {
+	dir := t.TempDir()
+	store := useMemoryStore(t)
+
+	tok := &oauth2.Token{AccessToken: "prod-token"}
+	require.NoError(t, SaveToken(dir, "prod:443", tok, "iss", "cid", "url"))
+	saved := append([]byte(nil), store.data...)
+
+	// A store that can't be read for now isn't overwritten with one session.
+	store.readErr = errors.New("collection is locked")
+	err := SaveToken(dir, "staging:443", tok, "iss", "cid", "url")
+	require.ErrorContains(t, err, "collection is locked")
+	require.Equal(t, saved, store.data)
+
+	// A corrupt store is reported rather than replaced.
+	store.readErr = nil
+	store.data = []byte("not json")
+	err = SaveToken(dir, "staging:443", tok, "iss", "cid", "url")
+	require.ErrorContains(t, err, "logout --all")
+	require.Equal(t, []byte("not json"), store.data)
+}
@@ -0,0 +1,228 @@
+// Package diff compares flattened configuration documents and renders the
+// differences as a unified diff or a JSON Patch.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"go.admiral.io/cli/internal/output"
+)
+
+// Op is a JSON Patch operation type.
+type Op string
+
+const (
+	OpAdd     Op = "add"
+	OpRemove  Op = "remove"
+	OpReplace Op = "replace"
+)
+
+// Operation is a single JSON Patch (RFC 6902) operation.
+type Operation struct {
+	Op    Op     `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	Value any    `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Document is a flattened configuration document: scalar values keyed by
+// JSON pointer (RFC 6901).
+type Document map[string]any
+
+// Pointer builds a JSON pointer from reference tokens, escaping "~" and "/".
+func Pointer(tokens ...string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		t = strings.ReplaceAll(t, "~", "~0")
+		b.WriteString(strings.ReplaceAll(t, "/", "~1"))
+	}
+	return b.String()
+}
+
+// Compare returns the patch that turns from into to, ordered by path. The
+// patch is never nil, so it encodes as an empty array when there are no
+// differences.
+func Compare(from, to Document) []Operation {
+	ops := []Operation{}
+	for _, path := range paths(from, to) {
+		oldVal, inFrom := from[path]
+		newVal, inTo := to[path]
+		switch {
+		case !inTo:
+			ops = append(ops, Operation{Op: OpRemove, Path: path})
+		case !inFrom:
+			ops = append(ops, Operation{Op: OpAdd, Path: path, Value: newVal})
+		case !reflect.DeepEqual(oldVal, newVal):
+			ops = append(ops, Operation{Op: OpReplace, Path: path, Value: newVal})
+		}
+	}
+	return ops
+}
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+type line struct {
+	kind byte // ' ', '-' or '+'
+	text string
+	// fromLine and toLine are the 1-based line numbers in from and to at
+	// which this line sits.
+	fromLine int
+	toLine   int
+}
+
+// WriteUnified writes a unified diff of from and to, rendering each field as
+// a "path: value" line. It writes nothing when the documents are equal.
+// With color set, headers and changed lines are colored with ANSI escapes.
+func WriteUnified(w io.Writer, fromName, toName string, from, to Document, color bool) {
+	lines := diffLines(from, to)
+
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+
+	headerDone := false
+	for _, h := range hunks(lines) {
+		if !headerDone {
+			output.Writeln(w, paint(colorBold, "--- "+fromName))
+			output.Writeln(w, paint(colorBold, "+++ "+toName))
+			headerDone = true
+		}
+
+		hunk := lines[h[0]:h[1]]
+		output.Writeln(w, paint(colorCyan, hunkHeader(hunk)))
+		for _, l := range hunk {
+			switch l.kind {
+			case '-':
+				output.Writeln(w, paint(colorRed, "-"+l.text))
+			case '+':
+				output.Writeln(w, paint(colorGreen, "+"+l.text))
+			default:
+				output.Writeln(w, " "+l.text)
+			}
+		}
+	}
+}
+
+// diffLines merges from and to into a single sequence of lines. Paths are
+// sorted on both sides, so merging by path yields a minimal line diff.
+func diffLines(from, to Document) []line {
+	var lines []line
+	fromLine, toLine := 1, 1
+	add := func(kind byte, path string, v any) {
+		lines = append(lines, line{kind: kind, text: formatLine(path, v), fromLine: fromLine, toLine: toLine})
+		if kind != '+' {
+			fromLine++
+		}
+		if kind != '-' {
+			toLine++
+		}
+	}
+
+	for _, path := range paths(from, to) {
+		oldVal, inFrom := from[path]
+		newVal, inTo := to[path]
+		switch {
+		case inFrom && inTo && reflect.DeepEqual(oldVal, newVal):
+			add(' ', path, oldVal)
+		default:
+			if inFrom {
+				add('-', path, oldVal)
+			}
+			if inTo {
+				add('+', path, newVal)
+			}
+		}
+	}
+	return lines
+}
+
+// hunks returns the [start, end) ranges of lines to print: every changed
+// line with up to contextLines unchanged lines around it, merging ranges
+// that touch.
+func hunks(lines []line) [][2]int {
+	var ranges [][2]int
+	for i, l := range lines {
+		if l.kind == ' ' {
+			continue
+		}
+		start := max(i-contextLines, 0)
+		end := min(i+1+contextLines, len(lines))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// hunkHeader formats the "@@ -l,s +l,s @@" header for a hunk.
+func hunkHeader(hunk []line) string {
+	var fromCount, toCount int
+	for _, l := range hunk {
+		if l.kind != '+' {
+			fromCount++
+		}
+		if l.kind != '-' {
+			toCount++
+		}
+	}
+
+	// An empty side is numbered by the line before it, as in GNU diff.
+	fromStart, toStart := hunk[0].fromLine, hunk[0].toLine
+	if fromCount == 0 {
+		fromStart--
+	}
+	if toCount == 0 {
+		toStart--
+	}
+
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", fromStart, fromCount, toStart, toCount)
+}
+
+// formatLine renders a field as "a.b.c: value".
+func formatLine(path string, v any) string {
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		tokens[i] = strings.ReplaceAll(t, "~0", "~")
+	}
+
+	value := fmt.Sprint(v)
+	if s, ok := v.(string); ok && (s == "" || strings.ContainsAny(s, "\n\r\t") || strings.TrimSpace(s) != s) {
+		value = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(tokens, ".") + ": " + value
+}
+
+// paths returns the union of the paths in a and b, sorted.
+func paths(a, b Document) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var result []string
+	for _, d := range []Document{a, b} {
+		for p := range d {
+			if !seen[p] {
+				seen[p] = true
+				result = append(result, p)
+			}
+		}
+	}
+	slices.Sort(result)
+	return result
+}
@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointer(t *testing.T) {
+	require.Equal(t, "/variables/DATABASE_URL", Pointer("variables", "DATABASE_URL"))
+	require.Equal(t, "/labels/a~1b~0c", Pointer("labels", "a/b~c"))
+}
+
+func TestCompare(t *testing.T) {
+	from := Document{
+		"/cluster":          "staging-1",
+		"/promotion_order":  int32(1),
+		"/variables/DEBUG":  "true",
+		"/variables/REGION": "us-east-1",
+	}
+	to := Document{
+		"/cluster":            "prod-1",
+		"/promotion_order":    int32(1),
+		"/variables/REGION":   "us-east-1",
+		"/variables/REPLICAS": "3",
+	}
+
+	require.Equal(t, []Operation{
+		{Op: OpReplace, Path: "/cluster", Value: "prod-1"},
+		{Op: OpRemove, Path: "/variables/DEBUG"},
+		{Op: OpAdd, Path: "/variables/REPLICAS", Value: "3"},
+	}, Compare(from, to))
+
+	require.Empty(t, Compare(from, from))
+	require.NotNil(t, Compare(from, from))
+}
+
+func TestWriteUnified(t *testing.T) {
+	t.Run("equal documents", func(t *testing.T) {
+		var buf bytes.Buffer
+		doc := Document{"/cluster": "prod-1"}
+		WriteUnified(&buf, "staging", "production", doc, doc, false)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("changes with context", func(t *testing.T) {
+		from := Document{
+			"/a": "1", "/b": "2", "/c": "3", "/d": "4", "/e": "5",
+			"/f": "6", "/g": "7", "/h": "8", "/i": "9", "/j": "old",
+		}
+		to := Document{
+			"/a": "changed", "/b": "2", "/c": "3", "/d": "4", "/e": "5",
+			"/f": "6", "/g": "7", "/h": "8", "/i": "9",
+		}
+
+		var buf bytes.Buffer
+		WriteUnified(&buf, "staging", "production", from, to, false)
+		require.Equal(t, `--- staging
++++ production
+@@ -1,4 +1,4 @@
+-a: 1
++a: changed
+ b: 2
+ c: 3
+ d: 4
+@@ -7,4 +7,3 @@
+ g: 7
+ h: 8
+ i: 9
+-j: old
+`, buf.String())
+	})
+
+	t.Run("color", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteUnified(&buf, "a", "b", Document{"/x": "1"}, Document{"/x": "2"}, true)
+		require.Contains(t, buf.String(), colorRed+"-x: 1"+colorReset)
+		require.Contains(t, buf.String(), colorGreen+"+x: 2"+colorReset)
+	})
+
+	t.Run("quotes ambiguous strings", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteUnified(&buf, "a", "b", Document{"/x": ""}, Document{"/x": "two\nlines"}, false)
+		require.Contains(t, buf.String(), "-x: \"\"\n")
+		require.Contains(t, buf.String(), "+x: \"two\\nlines\"\n")
+	})
+}
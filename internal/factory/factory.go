@@ -12,6 +12,7 @@ import (
 
 // Options hold the configuration shared across all commands.
 type Options struct {
+	Profile      string
 	ServerAddr   string
 	Insecure     bool
 	PlainText    bool
@@ -25,9 +26,21 @@ type Options struct {
 	Scopes   []string
 }
 
+// CredentialKey returns the key under which the session for the target
+// server is stored, so each server keeps its own login.
+func (o *Options) CredentialKey() string {
+	return o.ServerAddr
+}
+
+// Federation returns the issuer settings used to exchange a federated CI
+// token supplied via the environment.
+func (o *Options) Federation() *credentials.Federation {
+	return &credentials.Federation{Issuer: o.Issuer, ClientID: o.ClientID}
+}
+
 // CreateClient creates a new AdmiralClient using the SDK.
-func CreateClient(_ context.Context, opts *Options) (client.AdmiralClient, error) {
-	result, err := credentials.ResolveToken(opts.ConfigDir)
+func CreateClient(ctx context.Context, opts *Options) (client.AdmiralClient, error) {
+	result, err := credentials.ResolveToken(ctx, opts.ConfigDir, opts.CredentialKey(), opts.Federation())
 	if err != nil {
 		return nil, err
 	}
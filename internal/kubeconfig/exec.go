@@ -0,0 +1,206 @@
+package kubeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// cacheDir is where exec credentials are cached, relative to the config
+	// directory.
+	cacheDir = "cache/exec-credentials"
+
+	// CacheRefreshWindow is how long before expiry a cached credential is
+	// replaced.
+	CacheRefreshWindow = 5 * time.Minute
+)
+
+// ExecCredential is what an exec credential plugin prints for client-go.
+type ExecCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Status     *ExecCredentialStatus `json:"status"`
+}
+
+// ExecCredentialStatus holds the credential and when client-go should ask
+// for a new one.
+type ExecCredentialStatus struct {
+	ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	Token               string    `json:"token"`
+}
+
+// NewExecCredential returns the credential for a bearer token that expires
+// at expires.
+func NewExecCredential(token string, expires time.Time) *ExecCredential {
+	return &ExecCredential{
+		APIVersion: ExecAPIVersion,
+		Kind:       "ExecCredential",
+		Status: &ExecCredentialStatus{
+			ExpirationTimestamp: expires.UTC().Truncate(time.Second),
+			Token:               token,
+		},
+	}
+}
+
+// Encode writes the credential as JSON.
+func (c *ExecCredential) Encode(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode credential: %w", err)
+	}
+	return nil
+}
+
+// CachedCredential is a cached exec credential and the cluster token it
+// holds, so the token can be revoked when the credential is replaced or the
+// user logs out of Server.
+type CachedCredential struct {
+	Server     string          `json:"server,omitempty"`
+	ClusterID  string          `json:"clusterId"`
+	TokenID    string          `json:"tokenId"`
+	Credential *ExecCredential `json:"credential"`
+
+	// Path is the cache file the credential was read from by ReadCaches.
+	Path string `json:"-"`
+}
+
+// Fresh reports whether the credential is still valid for longer than
+// CacheRefreshWindow at now.
+func (c *CachedCredential) Fresh(now time.Time) bool {
+	return c.Credential != nil && c.Credential.Status != nil &&
+		c.Credential.Status.ExpirationTimestamp.After(now.Add(CacheRefreshWindow))
+}
+
+// CachePath returns the cache file of the credential for a cluster token
+// name, obtained from the Admiral server server.
+func CachePath(configDir, server, cluster, tokenName string) string {
+	return filepath.Join(configDir, cacheDir, hash(server), hash(cluster+"\x00"+tokenName)+".json")
+}
+
+// ReadCache reads the cached credential at path. A missing or unreadable
+// cache file is a miss and returns nil.
+func ReadCache(path string) *CachedCredential {
+	data, err := os.ReadFile(path) //nolint:gosec // path is built by CachePath
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Debug("failed to read cached credential", "error", err)
+		}
+		return nil
+	}
+
+	var c CachedCredential
+	if err := json.Unmarshal(data, &c); err != nil {
+		slog.Debug("discarding unreadable cached credential", "error", err)
+		return nil
+	}
+	return &c
+}
+
+// WriteCache writes the cached credential to path, readable only by the
+// user. The file is replaced atomically, so a concurrent ReadCache never
+// sees it half-written.
+func WriteCache(path string, c *CachedCredential) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create credential cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached credential: %w", err)
+	}
+
+	// CreateTemp creates the file with mode 0600.
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cached credential: %w", err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // gone once renamed
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write cached credential: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write cached credential: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cached credential: %w", err)
+	}
+	return nil
+}
+
+// LockCache takes an exclusive lock on the cache file at path, waiting for
+// any other process holding it, and returns the function that releases it.
+// kubectl may run the credential plugin in parallel; holding the lock while
+// refreshing keeps them from each creating a token.
+func LockCache(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create credential cache directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600) //nolint:gosec // path is built by CachePath
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock cached credential: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to lock cached credential: %w", err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// ReadCaches reads the credentials cached for the Admiral server server, or
+// for every server if server is empty. Unreadable cache files are skipped.
+func ReadCaches(configDir, server string) []*CachedCredential {
+	pattern := filepath.Join(configDir, cacheDir, "*", "*.json")
+	if server != "" {
+		pattern = filepath.Join(configDir, cacheDir, hash(server), "*.json")
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var cached []*CachedCredential
+	for _, path := range paths {
+		c := ReadCache(path)
+		if c == nil {
+			continue
+		}
+		if c.Server == "" {
+			c.Server = server
+		}
+		c.Path = path
+		cached = append(cached, c)
+	}
+	return cached
+}
+
+// RemoveCache removes the cached credential at path. A missing cache file
+// is not an error.
+func RemoveCache(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove cached credential: %w", err)
+	}
+	return nil
+}
+
+// hash names a cache path element after s, which may hold characters that
+// aren't valid in file names, such as the ":" of host:port.
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
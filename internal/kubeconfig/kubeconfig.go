@@ -0,0 +1,275 @@
+// Package kubeconfig reads and writes kubeconfig files, preserving the
+// entries and fields it doesn't manage.
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExecAPIVersion is the client.authentication.k8s.io version used by exec
+// credential plugins.
+const ExecAPIVersion = "client.authentication.k8s.io/v1"
+
+// TokenExtension names the user extension that records the ID of the
+// cluster token in the user's token field.
+const TokenExtension = "admiral.io/cluster-token"
+
+// Config is a kubeconfig file. Clusters, users and contexts are kept as
+// generic entries, so entries written by other tools round-trip unchanged.
+type Config struct {
+	APIVersion     string  `yaml:"apiVersion"`
+	Kind           string  `yaml:"kind"`
+	Clusters       []Entry `yaml:"clusters"`
+	Contexts       []Entry `yaml:"contexts"`
+	CurrentContext string  `yaml:"current-context"`
+	Users          []Entry `yaml:"users"`
+
+	// Rest holds the remaining top-level fields, such as preferences.
+	Rest map[string]any `yaml:",inline"`
+}
+
+// Entry is a named cluster, user or context. Its fields are kept as is:
+// e.g. a cluster entry holds "cluster" mapped to the cluster's fields.
+type Entry struct {
+	Name string         `yaml:"name"`
+	Rest map[string]any `yaml:",inline"`
+}
+
+// Cluster is how to reach a Kubernetes API server.
+type Cluster struct {
+	Server string `yaml:"server"`
+	// CertificateAuthorityData is the base64-encoded PEM of the CA that
+	// signed the server's certificate.
+	CertificateAuthorityData string `yaml:"certificate-authority-data,omitempty"`
+}
+
+// User is how to authenticate to a cluster: a static bearer token or an
+// exec credential plugin.
+type User struct {
+	Token string      `yaml:"token,omitempty"`
+	Exec  *ExecConfig `yaml:"exec,omitempty"`
+
+	// TokenID is the ID of the cluster token in Token. It is kept in the
+	// TokenExtension user extension.
+	TokenID string `yaml:"-"`
+}
+
+// namedExtension is an entry of the extensions list of a cluster, user or
+// context.
+type namedExtension struct {
+	Name      string         `yaml:"name"`
+	Extension map[string]any `yaml:"extension"`
+}
+
+// ExecConfig runs a command that prints an ExecCredential.
+type ExecConfig struct {
+	APIVersion      string   `yaml:"apiVersion"`
+	Command         string   `yaml:"command"`
+	Args            []string `yaml:"args,omitempty"`
+	InteractiveMode string   `yaml:"interactiveMode"`
+	InstallHint     string   `yaml:"installHint,omitempty"`
+}
+
+// Context pairs a cluster with a user.
+type Context struct {
+	Cluster string `yaml:"cluster"`
+	User    string `yaml:"user"`
+}
+
+// New returns an empty kubeconfig.
+func New() *Config {
+	return &Config{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Rest:       map[string]any{"preferences": map[string]any{}},
+	}
+}
+
+// Load reads the kubeconfig at path. Returns an empty kubeconfig (not an
+// error) if the file does not exist.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is chosen by the user
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+
+	cfg := New()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the kubeconfig to path, readable only by the user since it
+// may hold tokens. It is written to a temporary file that then replaces
+// path, so an interrupted write never leaves a truncated kubeconfig.
+func (c *Config) Save(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal kubeconfig: %w", err)
+	}
+
+	// CreateTemp creates the file with mode 0600.
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck // gone once renamed
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// Encode writes the kubeconfig as YAML.
+func (c *Config) Encode(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return enc.Close()
+}
+
+// Cluster returns the cluster entry called name.
+func (c *Config) Cluster(name string) (*Cluster, bool) {
+	var cl Cluster
+	return &cl, get(c.Clusters, name, "cluster", &cl)
+}
+
+// User returns the user entry called name.
+func (c *Config) User(name string) (*User, bool) {
+	var u struct {
+		User       `yaml:",inline"`
+		Extensions []namedExtension `yaml:"extensions"`
+	}
+	ok := get(c.Users, name, "user", &u)
+	for _, e := range u.Extensions {
+		if e.Name == TokenExtension {
+			u.TokenID, _ = e.Extension["id"].(string)
+		}
+	}
+	return &u.User, ok
+}
+
+// SetCluster adds the cluster entry called name, or updates the fields it
+// manages, keeping others such as proxy-url. A CA file is dropped when CA
+// data is set, since kubectl rejects both.
+func (c *Config) SetCluster(name string, cl *Cluster) {
+	managed := []string{"server", "certificate-authority-data"}
+	if cl.CertificateAuthorityData != "" {
+		managed = append(managed, "certificate-authority")
+	}
+	c.Clusters, _ = set(c.Clusters, name, "cluster", cl, managed...)
+}
+
+// SetUser adds the user entry called name, or updates its token and exec
+// plugin, keeping other fields and extensions. u.TokenID is recorded in the
+// TokenExtension extension, which is removed when it is empty.
+func (c *Config) SetUser(name string, u *User) {
+	var fields map[string]any
+	c.Users, fields = set(c.Users, name, "user", u, "token", "exec")
+
+	var exts []any
+	if cur, ok := fields["extensions"].([]any); ok {
+		for _, e := range cur {
+			if m, ok := e.(map[string]any); ok && m["name"] == TokenExtension {
+				continue
+			}
+			exts = append(exts, e)
+		}
+	}
+	if u.TokenID != "" {
+		exts = append(exts, map[string]any{
+			"name":      TokenExtension,
+			"extension": map[string]any{"id": u.TokenID},
+		})
+	}
+	if len(exts) == 0 {
+		delete(fields, "extensions")
+	} else {
+		fields["extensions"] = exts
+	}
+}
+
+// SetContext adds the context called name, or updates its cluster and
+// user, keeping other fields such as namespace.
+func (c *Config) SetContext(name string, ctx *Context) {
+	c.Contexts, _ = set(c.Contexts, name, "context", ctx, "cluster", "user")
+}
+
+// get decodes the key field of the entry called name into out, reporting
+// whether the entry exists.
+func get(entries []Entry, name, key string, out any) bool {
+	for _, e := range entries {
+		if e.Name != name {
+			continue
+		}
+		// Round-trip through YAML to decode the generic fields.
+		data, err := yaml.Marshal(e.Rest[key])
+		if err != nil {
+			return false
+		}
+		return yaml.Unmarshal(data, out) == nil
+	}
+	return false
+}
+
+// set overlays v onto the key field of the entry called name, or appends an
+// entry holding v. The managed keys of the field are removed first, so
+// those v leaves empty are cleared; other keys are kept. It returns the
+// entries and the field's merged map.
+func set(entries []Entry, name, key string, v any, managed ...string) ([]Entry, map[string]any) {
+	fields := map[string]any{}
+	// Round-trip through YAML to get v's fields by their kubeconfig names.
+	if data, err := yaml.Marshal(v); err == nil {
+		_ = yaml.Unmarshal(data, &fields)
+	}
+
+	for i := range entries {
+		if entries[i].Name != name {
+			continue
+		}
+		cur, _ := entries[i].Rest[key].(map[string]any)
+		merged := maps.Clone(cur)
+		if merged == nil {
+			merged = map[string]any{}
+		}
+		for _, k := range managed {
+			delete(merged, k)
+		}
+		maps.Copy(merged, fields)
+
+		rest := maps.Clone(entries[i].Rest)
+		if rest == nil {
+			rest = map[string]any{}
+		}
+		rest[key] = merged
+		entries[i].Rest = rest
+		return entries, merged
+	}
+	return append(entries, Entry{Name: name, Rest: map[string]any{key: fields}}), fields
+}
@@ -0,0 +1,231 @@
+package kubeconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const existing = `apiVersion: v1
+kind: Config
+clusters:
+  - name: kind-dev
+    cluster:
+      server: https://127.0.0.1:6443
+      insecure-skip-tls-verify: true
+contexts:
+  - name: kind-dev
+    context:
+      cluster: kind-dev
+      user: kind-dev
+      namespace: default
+current-context: kind-dev
+users:
+  - name: kind-dev
+    user:
+      client-certificate-data: Y2VydA==
+preferences:
+  colors: true
+`
+
+func TestLoad_Missing(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config"))
+	require.NoError(t, err)
+	require.Equal(t, "Config", cfg.Kind)
+	require.Empty(t, cfg.Contexts)
+}
+
+func TestMerge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(existing), 0o600))
+
+	merge := func() []byte {
+		cfg, err := Load(path)
+		require.NoError(t, err)
+		cfg.SetCluster("admiral-prod", &Cluster{Server: "https://10.0.0.1:6443", CertificateAuthorityData: "Y2E="})
+		cfg.SetUser("admiral-prod", &User{Token: "adm_cluster_x"})
+		cfg.SetContext("admiral-prod", &Context{Cluster: "admiral-prod", User: "admiral-prod"})
+		require.NoError(t, cfg.Save(path))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return data
+	}
+
+	first := merge()
+	require.Equal(t, string(first), string(merge()), "merging again changes nothing")
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, "kind-dev", cfg.CurrentContext)
+	require.Len(t, cfg.Contexts, 2)
+	require.Equal(t, map[string]any{"colors": true}, cfg.Rest["preferences"])
+
+	dev, ok := cfg.Cluster("kind-dev")
+	require.True(t, ok)
+	require.Equal(t, "https://127.0.0.1:6443", dev.Server)
+	require.Contains(t, string(first), "insecure-skip-tls-verify: true")
+	require.Contains(t, string(first), "namespace: default")
+
+	prod, ok := cfg.Cluster("admiral-prod")
+	require.True(t, ok)
+	require.Equal(t, &Cluster{Server: "https://10.0.0.1:6443", CertificateAuthorityData: "Y2E="}, prod)
+	user, ok := cfg.User("admiral-prod")
+	require.True(t, ok)
+	require.Equal(t, "adm_cluster_x", user.Token)
+
+	_, ok = cfg.User("missing")
+	require.False(t, ok)
+}
+
+func TestMerge_KeepsUnmanagedFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+clusters:
+  - name: admiral-prod
+    cluster:
+      server: https://old:6443
+      certificate-authority: /etc/ca.crt
+      proxy-url: http://proxy:3128
+contexts:
+  - name: admiral-prod
+    context:
+      cluster: admiral-prod
+      user: admiral-prod
+      namespace: billing
+users:
+  - name: admiral-prod
+    user:
+      token: old
+      extensions:
+        - name: example.com/owner
+          extension: {team: payments}
+`), 0o600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	cfg.SetCluster("admiral-prod", &Cluster{Server: "https://10.0.0.1:6443", CertificateAuthorityData: "Y2E="})
+	cfg.SetUser("admiral-prod", &User{Token: "adm_cluster_x", TokenID: "tok-1"})
+	cfg.SetContext("admiral-prod", &Context{Cluster: "admiral-prod", User: "admiral-prod"})
+	require.NoError(t, cfg.Save(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "proxy-url: http://proxy:3128")
+	require.Contains(t, string(data), "namespace: billing")
+	require.Contains(t, string(data), "example.com/owner")
+	require.NotContains(t, string(data), "certificate-authority: /etc/ca.crt", "CA file is replaced by CA data")
+
+	cfg, err = Load(path)
+	require.NoError(t, err)
+	user, ok := cfg.User("admiral-prod")
+	require.True(t, ok)
+	require.Equal(t, &User{Token: "adm_cluster_x", TokenID: "tok-1"}, user)
+
+	// Switching to an exec plugin clears the token and its ID, but not
+	// other extensions.
+	cfg.SetUser("admiral-prod", &User{Exec: &ExecConfig{APIVersion: ExecAPIVersion, Command: "admiral"}})
+	user, _ = cfg.User("admiral-prod")
+	require.Empty(t, user.Token)
+	require.Empty(t, user.TokenID)
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Encode(&buf))
+	require.Contains(t, buf.String(), "example.com/owner")
+	require.NotContains(t, buf.String(), TokenExtension)
+}
+
+func TestSave_ReplacesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(path, []byte(existing), 0o644))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Save(path))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary file is left behind")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestExecCredential(t *testing.T) {
+	expires := time.Date(2026, 10, 16, 12, 0, 0, 500, time.FixedZone("CEST", 2*60*60))
+	var buf bytes.Buffer
+	require.NoError(t, NewExecCredential("adm_cluster_x", expires).Encode(&buf))
+	require.JSONEq(t, `{
+		"apiVersion": "client.authentication.k8s.io/v1",
+		"kind": "ExecCredential",
+		"status": {"expirationTimestamp": "2026-10-16T10:00:00Z", "token": "adm_cluster_x"}
+	}`, buf.String())
+}
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	path := CachePath(dir, "api.admiral.io:443", "prod", "kubeconfig")
+	require.NotEqual(t, path, CachePath(dir, "api.admiral.io:443", "prod", "other"))
+	require.Nil(t, ReadCache(path))
+
+	cached := &CachedCredential{ClusterID: "prod", TokenID: "tok-1", Credential: NewExecCredential("x", now.Add(time.Hour))}
+	require.NoError(t, WriteCache(path, cached))
+	got := ReadCache(path)
+	require.NotNil(t, got)
+	require.Equal(t, "tok-1", got.TokenID)
+	require.True(t, got.Fresh(now))
+	require.False(t, got.Fresh(now.Add(time.Hour-CacheRefreshWindow)))
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	require.Nil(t, ReadCache(path))
+
+	require.NoError(t, WriteCache(path, cached))
+	other := &CachedCredential{Server: "other:443", ClusterID: "dev", TokenID: "tok-2", Credential: NewExecCredential("y", now.Add(time.Hour))}
+	require.NoError(t, WriteCache(CachePath(dir, "other:443", "dev", "kubeconfig"), other))
+	require.Len(t, ReadCaches(dir, ""), 2)
+	caches := ReadCaches(dir, "api.admiral.io:443")
+	require.Len(t, caches, 1)
+	require.Equal(t, "tok-1", caches[0].TokenID)
+	require.Equal(t, "api.admiral.io:443", caches[0].Server, "the server defaults to the one asked for")
+	require.Equal(t, path, caches[0].Path)
+
+	require.NoError(t, RemoveCache(caches[0].Path))
+	require.Nil(t, ReadCache(path))
+	require.NoError(t, RemoveCache(path), "a missing cache file is no error")
+	require.Len(t, ReadCaches(dir, ""), 1)
+}
+
+func TestLockCache(t *testing.T) {
+	dir := t.TempDir()
+	path := CachePath(dir, "api.admiral.io:443", "prod", "kubeconfig")
+
+	unlock, err := LockCache(path)
+	require.NoError(t, err)
+
+	// A second lock waits until the first is released.
+	locked := make(chan struct{})
+	go func() {
+		unlock, err := LockCache(path)
+		if err == nil {
+			unlock()
+		}
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lock taken while held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	<-locked
+
+	// Locking leaves the cache itself alone.
+	require.Nil(t, ReadCache(path))
+	require.Empty(t, ReadCaches(dir, ""))
+}
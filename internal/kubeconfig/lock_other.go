@@ -0,0 +1,12 @@
+//go:build !unix && !windows
+
+package kubeconfig
+
+import "os"
+
+// Platforms without file locks run the credential plugin unlocked; parallel
+// runs may then each create a token.
+
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
@@ -0,0 +1,17 @@
+//go:build unix
+
+package kubeconfig
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func lockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_EX) //nolint:gosec // file descriptors fit in an int
+}
+
+func unlockFile(f *os.File) error {
+	return unix.Flock(int(f.Fd()), unix.LOCK_UN) //nolint:gosec // file descriptors fit in an int
+}
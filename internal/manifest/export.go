@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// ExportOptions selects what ExportApplication includes besides the
+// application itself.
+type ExportOptions struct {
+	// Environments includes the environments of the application.
+	Environments bool
+	// Variables includes the variables of the application and, with
+	// Environments, of each environment.
+	Variables bool
+}
+
+// ExportApplication returns the manifests of a live application, ready to
+// be applied again.
+func ExportApplication(ctx context.Context, c client.AdmiralClient, app string, opts ExportOptions) ([]*Manifest, error) {
+	resp, err := c.Application().GetApplication(ctx, &applicationv1.GetApplicationRequest{ApplicationId: app})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get application %q: %w", app, err)
+	}
+	manifests := []*Manifest{FromApplication(resp.Application)}
+
+	// Environments and variables are fetched by the name the application is
+	// exported as, which is what the manifests refer to it by.
+	name := resp.Application.Name
+	var envs []string
+	if opts.Environments {
+		live, err := listEnvironments(ctx, c, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range live {
+			manifests = append(manifests, FromEnvironment(name, e))
+			envs = append(envs, e.Name)
+		}
+	}
+
+	if opts.Variables {
+		for _, env := range append([]string{""}, envs...) {
+			md := Metadata{Application: name, Environment: env}
+			vars, err := variables.ListAll(ctx, c.Variable(), scopeRequest(md))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list variables: %w", err)
+			}
+			if len(vars) > 0 {
+				manifests = append(manifests, FromVariables(name, env, vars))
+			}
+		}
+	}
+
+	return exported(manifests), nil
+}
+
+// ExportCluster returns the manifest of a live cluster.
+func ExportCluster(ctx context.Context, c client.AdmiralClient, cluster string) (*Manifest, error) {
+	resp, err := c.Cluster().GetCluster(ctx, &clusterv1.GetClusterRequest{ClusterId: cluster})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %q: %w", cluster, err)
+	}
+	return exported([]*Manifest{FromCluster(resp.Cluster)})[0], nil
+}
+
+// ExportVariables returns the variable set of the global scope.
+func ExportVariables(ctx context.Context, c client.AdmiralClient) (*Manifest, error) {
+	vars, err := variables.ListAll(ctx, c.Variable(), &variablev1.ListVariablesRequest{
+		Scope: variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variables: %w", err)
+	}
+	return exported([]*Manifest{FromVariables("", "", vars)})[0], nil
+}
+
+// exported prepares live manifests for export, sorted in apply order: the
+// manifest set label is dropped, since apply sets it, and sensitive values
+// are replaced with references to environment variables named by
+// SecretEnv.
+func exported(manifests []*Manifest) []*Manifest {
+	for _, m := range manifests {
+		if _, ok := m.Metadata.Labels[ManagedByLabel]; ok {
+			m.Metadata.Labels = maps.Clone(m.Metadata.Labels)
+			delete(m.Metadata.Labels, ManagedByLabel)
+		}
+
+		spec, ok := m.Spec.(*VariableSetSpec)
+		if !ok {
+			continue
+		}
+		for i := range spec.Variables {
+			v := &spec.Variables[i]
+			if v.Sensitive {
+				v.Value = ""
+				v.ValueFrom = &ValueSource{Env: SecretEnv(m.Metadata.Application, m.Metadata.Environment, v.Key)}
+			}
+		}
+	}
+	sortManifests(manifests)
+	return manifests
+}
+
+// SecretEnv returns the environment variable a sensitive variable is
+// exported as a reference to: its app, environment and key, upper-cased,
+// joined by "_", with other characters replaced by "_", e.g.
+// BILLING_API_STAGING_DB_PASSWORD.
+func SecretEnv(app, env, key string) string {
+	var parts []string
+	for _, p := range []string{app, env, key} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, strings.Join(parts, "_"))
+}
+
+// Encode writes manifests as a stream of YAML documents that Decode reads
+// back.
+func Encode(w io.Writer, manifests []*Manifest) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, m := range manifests {
+		if err := enc.Encode(m); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", m.ID(), err)
+		}
+	}
+	return enc.Close()
+}
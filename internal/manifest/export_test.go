@@ -0,0 +1,141 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/diff"
+	"go.admiral.io/cli/internal/variables"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestSecretEnv(t *testing.T) {
+	require.Equal(t, "BILLING_API_STAGING_DB_PASSWORD", SecretEnv("billing-api", "staging", "DB_PASSWORD"))
+	require.Equal(t, "BILLING_API_TOKEN", SecretEnv("billing-api", "", "token"))
+	require.Equal(t, "API_KEY", SecretEnv("", "", "api.key"))
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	env := FromEnvironment("billing-api", &environmentv1.Environment{
+		Name:      "preview",
+		Cluster:   "us-east-1",
+		Lifecycle: environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_EPHEMERAL,
+		Labels:    map[string]string{ManagedByLabel: "platform", "tier": "web"},
+	})
+	env.Spec.(*EnvironmentSpec).TTL = 24 * time.Hour
+	vars := FromVariables("billing-api", "preview", []*variablev1.Variable{
+		{Key: "DB_PASSWORD", Value: "s3cr3t", Sensitive: true},
+		{Key: "PORT", Value: "8080"},
+	})
+	ms := exported([]*Manifest{vars, env})
+
+	require.Equal(t, "Environment/billing-api/preview", ms[0].ID())
+	require.Equal(t, map[string]string{"tier": "web"}, ms[0].Metadata.Labels)
+	require.Equal(t, []Variable{
+		{Key: "DB_PASSWORD", Sensitive: true, ValueFrom: &ValueSource{Env: "BILLING_API_PREVIEW_DB_PASSWORD"}},
+		{Key: "PORT", Value: "8080"},
+	}, ms[1].Spec.(*VariableSetSpec).Variables)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, ms))
+	require.NotContains(t, buf.String(), "s3cr3t")
+	require.Contains(t, buf.String(), "ttl: 24h0m0s")
+
+	back := decode(t, buf.String())
+	require.Len(t, back, 2)
+	for i := range ms {
+		require.Equal(t, ms[i].ID(), back[i].ID())
+		require.Equal(t, ms[i].Document(), back[i].Document())
+	}
+}
+
+func TestExport_JSONRoundTrip(t *testing.T) {
+	env := FromEnvironment("billing-api", &environmentv1.Environment{
+		Name:      "preview",
+		Cluster:   "us-east-1",
+		Lifecycle: environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_EPHEMERAL,
+	})
+	env.Spec.(*EnvironmentSpec).TTL = time.Hour
+
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"ttl":"1h0m0s"`)
+
+	// JSON is YAML, so the output can be applied again.
+	back := decode(t, string(data))
+	require.Len(t, back, 1)
+	require.Equal(t, env.Document(), back[0].Document())
+}
+
+func TestResolveValues(t *testing.T) {
+	src := `apiVersion: admiral.io/v1
+kind: VariableSet
+metadata:
+  application: billing-api
+spec:
+  variables:
+    - key: DB_PASSWORD
+      sensitive: true
+      valueFrom: {env: DB_PASSWORD}
+    - key: PORT
+      type: int
+      valueFrom: {env: PORT}
+`
+	env := map[string]string{"DB_PASSWORD": "s3cr3t"}
+	lookup := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	ms := decode(t, src)
+	require.NoError(t, ResolveValues(ms, lookup))
+	vs := ms[0].Spec.(*VariableSetSpec).Variables
+	require.Equal(t, "s3cr3t", vs[0].Value)
+	require.True(t, vs[1].unresolved())
+
+	env["PORT"] = "eighty"
+	require.ErrorContains(t, ResolveValues(decode(t, src), lookup), "PORT")
+}
+
+func TestNewPlan_UnresolvedValue(t *testing.T) {
+	src := `apiVersion: admiral.io/v1
+kind: VariableSet
+metadata: {}
+spec:
+  variables:
+    - key: TOKEN
+      sensitive: true
+      valueFrom: {env: ADMIRAL_TEST_TOKEN}
+`
+	live := Live{}
+	vs := FromVariables("", "", []*variablev1.Variable{{Key: "TOKEN", Value: "s3cr3t", Sensitive: true}})
+	live[vs.ID()] = vs
+
+	// The live value is kept while the reference is unresolved.
+	plan, err := NewPlan(decode(t, src), live, Options{})
+	require.NoError(t, err)
+	require.Equal(t, ActionUnchanged, plan.Changes[0].Action)
+
+	_, err = NewPlan(decode(t, src), Live{}, Options{})
+	require.ErrorContains(t, err, "$ADMIRAL_TEST_TOKEN is not set")
+
+	// A diff without the secret reports the change with a masked value.
+	plan, err = NewPlan(decode(t, src), Live{}, Options{Redact: true})
+	require.NoError(t, err)
+	require.Equal(t, ActionCreate, plan.Changes[0].Action)
+	require.Contains(t, plan.Changes[0].Patch, diff.Operation{Op: diff.OpAdd, Path: "/variables/TOKEN/value", Value: variables.MaskedValue})
+
+	typed := strings.Replace(src, "sensitive: true", "sensitive: true\n      type: int", 1)
+	_, err = NewPlan(decode(t, typed), live, Options{})
+	require.ErrorContains(t, err, "set $ADMIRAL_TEST_TOKEN to change the variable")
+
+	plan, err = NewPlan(decode(t, typed), live, Options{Redact: true})
+	require.NoError(t, err)
+	require.Equal(t, ActionUpdate, plan.Changes[0].Action)
+}
@@ -0,0 +1,237 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// listPageSize is the page size used when fetching every resource of a kind.
+const listPageSize = 100
+
+// FromCluster returns the manifest of a live cluster.
+func FromCluster(c *clusterv1.Cluster) *Manifest {
+	return &Manifest{
+		APIVersion: APIVersion,
+		Kind:       KindCluster,
+		Metadata:   Metadata{Name: c.Name, Labels: c.Labels},
+		Spec:       &ClusterSpec{},
+	}
+}
+
+// FromApplication returns the manifest of a live application.
+func FromApplication(a *applicationv1.Application) *Manifest {
+	return &Manifest{
+		APIVersion: APIVersion,
+		Kind:       KindApplication,
+		Metadata:   Metadata{Name: a.Name, Labels: a.Labels},
+		Spec:       &ApplicationSpec{Description: a.Description},
+	}
+}
+
+// FromEnvironment returns the manifest of a live environment of app.
+func FromEnvironment(app string, e *environmentv1.Environment) *Manifest {
+	spec := &EnvironmentSpec{
+		Cluster:        e.Cluster,
+		PromotionOrder: e.PromotionOrder,
+		Lifecycle:      formatLifecycle(e.Lifecycle),
+		Parent:         e.Parent,
+		SourceRef:      e.SourceRef,
+	}
+	if e.Ttl != nil {
+		spec.TTL = e.Ttl.AsDuration()
+	}
+	return &Manifest{
+		APIVersion: APIVersion,
+		Kind:       KindEnvironment,
+		Metadata:   Metadata{Name: e.Name, Application: app, Labels: e.Labels},
+		Spec:       spec,
+	}
+}
+
+// FromVariables returns the variable set of the scope of app and env, where
+// empty app is the global scope and empty env the app scope. Sensitive
+// values are replaced with variables.MaskedValue. Variables are sorted by
+// key.
+func FromVariables(app, env string, vars []*variablev1.Variable) *Manifest {
+	spec := &VariableSetSpec{Variables: make([]Variable, 0, len(vars))}
+	for _, v := range vars {
+		spec.Variables = append(spec.Variables, Variable{
+			Key:       v.Key,
+			Value:     variables.DisplayValue(v, false),
+			Sensitive: v.Sensitive,
+			Type:      formatType(v.Type),
+			Pattern:   v.Pattern,
+		})
+	}
+	slices.SortFunc(spec.Variables, func(a, b Variable) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return &Manifest{
+		APIVersion: APIVersion,
+		Kind:       KindVariableSet,
+		Metadata:   Metadata{Application: app, Environment: env},
+		Spec:       spec,
+	}
+}
+
+// Live maps the IDs of live resources to their manifests.
+type Live map[string]*Manifest
+
+// FetchLive fetches the live resources manifests are planned against: every
+// cluster and application, the environments of the applications manifests
+// refer to, and the variables of each VariableSet's scope. With a set, the
+// environments of applications labelled with it are fetched too, so they
+// can be pruned.
+func FetchLive(ctx context.Context, c client.AdmiralClient, manifests []*Manifest, set string) (Live, error) {
+	live := Live{}
+
+	clusters, err := listClusters(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, cl := range clusters {
+		m := FromCluster(cl)
+		live[m.ID()] = m
+	}
+
+	apps, err := listApplications(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	envApps := map[string]bool{}
+	for _, a := range apps {
+		m := FromApplication(a)
+		live[m.ID()] = m
+		if set != "" && a.Labels[ManagedByLabel] == set {
+			envApps[a.Name] = true
+		}
+	}
+	for _, m := range manifests {
+		if m.Metadata.Application != "" {
+			envApps[m.Metadata.Application] = true
+		}
+		if m.Kind == KindApplication {
+			envApps[m.Metadata.Name] = true
+		}
+	}
+
+	for _, app := range slices.Sorted(maps.Keys(envApps)) {
+		if live[applicationID(app)] == nil {
+			continue // Not created yet, so it has no environments.
+		}
+		envs, err := listEnvironments(ctx, c, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range envs {
+			m := FromEnvironment(app, e)
+			live[m.ID()] = m
+		}
+	}
+
+	for _, m := range manifests {
+		if m.Kind != KindVariableSet || !scopeExists(live, m.Metadata) {
+			continue
+		}
+		vars, err := variables.ListAll(ctx, c.Variable(), scopeRequest(m.Metadata))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables of %s: %w", m.ID(), err)
+		}
+		vs := FromVariables(m.Metadata.Application, m.Metadata.Environment, vars)
+		live[vs.ID()] = vs
+	}
+
+	return live, nil
+}
+
+// scopeExists reports whether the application and environment of a
+// VariableSet exist.
+func scopeExists(live Live, md Metadata) bool {
+	if md.Application != "" && live[applicationID(md.Application)] == nil {
+		return false
+	}
+	if md.Environment != "" && live[environmentID(md.Application, md.Environment)] == nil {
+		return false
+	}
+	return true
+}
+
+// scopeRequest returns the request listing the variables in the scope of a
+// VariableSet.
+func scopeRequest(md Metadata) *variablev1.ListVariablesRequest {
+	return &variablev1.ListVariablesRequest{
+		Scope:         variableScope(md),
+		ApplicationId: md.Application,
+		EnvironmentId: md.Environment,
+	}
+}
+
+// variableScope returns the API scope of a VariableSet.
+func variableScope(md Metadata) variablev1.VariableScope {
+	switch {
+	case md.Application == "":
+		return variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL
+	case md.Environment == "":
+		return variablev1.VariableScope_VARIABLE_SCOPE_APP
+	default:
+		return variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV
+	}
+}
+
+func listClusters(ctx context.Context, c client.AdmiralClient) ([]*clusterv1.Cluster, error) {
+	var all []*clusterv1.Cluster
+	req := &clusterv1.ListClustersRequest{PageSize: listPageSize}
+	for {
+		resp, err := c.Cluster().ListClusters(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list clusters: %w", err)
+		}
+		all = append(all, resp.Clusters...)
+		if resp.NextPageToken == "" {
+			return all, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+func listApplications(ctx context.Context, c client.AdmiralClient) ([]*applicationv1.Application, error) {
+	var all []*applicationv1.Application
+	req := &applicationv1.ListApplicationsRequest{PageSize: listPageSize}
+	for {
+		resp, err := c.Application().ListApplications(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list applications: %w", err)
+		}
+		all = append(all, resp.Applications...)
+		if resp.NextPageToken == "" {
+			return all, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+func listEnvironments(ctx context.Context, c client.AdmiralClient, app string) ([]*environmentv1.Environment, error) {
+	var all []*environmentv1.Environment
+	req := &environmentv1.ListEnvironmentsRequest{ApplicationId: app, PageSize: listPageSize}
+	for {
+		resp, err := c.Environment().ListEnvironments(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environments of %s: %w", app, err)
+		}
+		all = append(all, resp.Environments...)
+		if resp.NextPageToken == "" {
+			return all, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
@@ -0,0 +1,541 @@
+// Package manifest reads declarative resource manifests and plans the API
+// calls that make live state match them.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"go.admiral.io/cli/internal/diff"
+	"go.admiral.io/cli/internal/variables"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// APIVersion is the apiVersion of every manifest.
+const APIVersion = "admiral.io/v1"
+
+// ManagedByLabel is set on applications, clusters and environments applied
+// with a manifest set, so 'apply --prune' can find the ones removed from it.
+const ManagedByLabel = "admiral.io/manifest-set"
+
+// Kind is the type of resource a manifest declares.
+type Kind string
+
+const (
+	KindCluster     Kind = "Cluster"
+	KindApplication Kind = "Application"
+	KindEnvironment Kind = "Environment"
+	KindVariableSet Kind = "VariableSet"
+)
+
+// kindOrder is the order resources are created and updated in, so that
+// everything a resource refers to exists first. Deletes run in reverse.
+var kindOrder = []Kind{KindCluster, KindApplication, KindEnvironment, KindVariableSet}
+
+// Metadata identifies the resource a manifest declares.
+type Metadata struct {
+	// Name is the name of a cluster, application or environment.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Application is the application of an environment or variable set.
+	Application string `json:"application,omitempty" yaml:"application,omitempty"`
+	// Environment is the environment of a variable set.
+	Environment string            `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// ApplicationSpec is the spec of an Application.
+type ApplicationSpec struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// ClusterSpec is the spec of a Cluster. Clusters have no settings besides
+// their labels.
+type ClusterSpec struct{}
+
+// EnvironmentSpec is the spec of an Environment.
+type EnvironmentSpec struct {
+	Cluster        string `json:"cluster" yaml:"cluster"`
+	PromotionOrder int32  `json:"promotionOrder,omitempty" yaml:"promotionOrder,omitempty"`
+	// Lifecycle is permanent or ephemeral. Empty leaves it to the server.
+	Lifecycle string        `json:"lifecycle,omitempty" yaml:"lifecycle,omitempty"`
+	Parent    string        `json:"parent,omitempty" yaml:"parent,omitempty"`
+	SourceRef string        `json:"sourceRef,omitempty" yaml:"sourceRef,omitempty"`
+	TTL       time.Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// MarshalJSON writes TTL as a duration string such as "24h0m0s", as the
+// YAML encoding does, rather than in nanoseconds, so JSON output can be
+// applied again.
+func (s EnvironmentSpec) MarshalJSON() ([]byte, error) {
+	type spec EnvironmentSpec
+	var ttl string
+	if s.TTL != 0 {
+		ttl = s.TTL.String()
+	}
+	return json.Marshal(struct {
+		spec
+		TTL string `json:"ttl,omitempty"`
+	}{spec(s), ttl})
+}
+
+// VariableSetSpec is the spec of a VariableSet: the variables of one scope.
+type VariableSetSpec struct {
+	Variables []Variable `json:"variables" yaml:"variables"`
+}
+
+// Variable is a variable in a VariableSet.
+type Variable struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// ValueFrom reads the value from elsewhere at apply time instead, so
+	// sensitive values can be kept out of manifests.
+	ValueFrom *ValueSource `json:"valueFrom,omitempty" yaml:"valueFrom,omitempty"`
+	Sensitive bool         `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+	// Type is string, int, bool, duration, url or json. Empty is string.
+	Type    string `json:"type,omitempty" yaml:"type,omitempty"`
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// resolved is set once Value holds the value ValueFrom refers to.
+	resolved bool
+}
+
+// ValueSource refers to a variable value kept outside the manifest.
+type ValueSource struct {
+	// Env is the environment variable holding the value. If it is unset,
+	// the live value is kept.
+	Env string `json:"env" yaml:"env"`
+}
+
+// unresolved reports whether v refers to a value that wasn't read, so the
+// live value is kept.
+func (v *Variable) unresolved() bool {
+	return v.ValueFrom != nil && !v.resolved
+}
+
+// validateValue checks the value of v against its type and pattern.
+func (v *Variable) validateValue() error {
+	typ, err := parseType(v.Type)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.Key, err)
+	}
+	return variables.Validate(&variablev1.Variable{
+		Key:       v.Key,
+		Value:     v.Value,
+		Sensitive: v.Sensitive,
+		Type:      typ,
+		Pattern:   v.Pattern,
+	})
+}
+
+// Manifest declares a single resource. Spec holds the *ApplicationSpec,
+// *ClusterSpec, *EnvironmentSpec or *VariableSetSpec matching Kind.
+type Manifest struct {
+	APIVersion string   `json:"apiVersion" yaml:"apiVersion"`
+	Kind       Kind     `json:"kind" yaml:"kind"`
+	Metadata   Metadata `json:"metadata" yaml:"metadata"`
+	Spec       any      `json:"spec,omitempty" yaml:"spec,omitempty"`
+
+	// Source is where the manifest was read from, e.g. "apps.yaml#2".
+	Source string `json:"-" yaml:"-"`
+}
+
+// ID returns the display name of the resource, unique across a manifest
+// set, e.g. "Environment/billing-api/staging". A VariableSet is named after
+// its scope: "VariableSet/global", "VariableSet/APP" or
+// "VariableSet/APP/ENV".
+func (m *Manifest) ID() string {
+	switch m.Kind {
+	case KindEnvironment:
+		return environmentID(m.Metadata.Application, m.Metadata.Name)
+	case KindVariableSet:
+		switch {
+		case m.Metadata.Application == "":
+			return string(m.Kind) + "/global"
+		case m.Metadata.Environment == "":
+			return fmt.Sprintf("%s/%s", m.Kind, m.Metadata.Application)
+		default:
+			return fmt.Sprintf("%s/%s/%s", m.Kind, m.Metadata.Application, m.Metadata.Environment)
+		}
+	default:
+		return fmt.Sprintf("%s/%s", m.Kind, m.Metadata.Name)
+	}
+}
+
+func applicationID(name string) string {
+	return fmt.Sprintf("%s/%s", KindApplication, name)
+}
+
+func environmentID(app, name string) string {
+	return fmt.Sprintf("%s/%s/%s", KindEnvironment, app, name)
+}
+
+// Document flattens the fields of the resource for comparison, keyed by
+// their API field names. Unset fields are left out.
+func (m *Manifest) Document() diff.Document {
+	doc := diff.Document{}
+	set := func(v any, tokens ...string) {
+		switch v := v.(type) {
+		case string:
+			if v == "" {
+				return
+			}
+		case int32:
+			if v == 0 {
+				return
+			}
+		case bool:
+			if !v {
+				return
+			}
+		}
+		doc[diff.Pointer(tokens...)] = v
+	}
+
+	for k, v := range m.Metadata.Labels {
+		doc[diff.Pointer("labels", k)] = v
+	}
+
+	switch spec := m.Spec.(type) {
+	case *ApplicationSpec:
+		set(spec.Description, "description")
+	case *EnvironmentSpec:
+		set(spec.Cluster, "cluster")
+		set(spec.PromotionOrder, "promotion_order")
+		set(spec.Lifecycle, "lifecycle")
+		set(spec.Parent, "parent")
+		set(spec.SourceRef, "source_ref")
+		if spec.TTL != 0 {
+			set(spec.TTL.String(), "ttl")
+		}
+	case *VariableSetSpec:
+		for _, v := range spec.Variables {
+			// Always set, so an empty variable still shows up, unless the
+			// live value is kept.
+			if !v.unresolved() {
+				doc[diff.Pointer("variables", v.Key, "value")] = v.Value
+			}
+			set(v.Sensitive, "variables", v.Key, "sensitive")
+			if v.Type != "string" {
+				set(v.Type, "variables", v.Key, "type")
+			}
+			set(v.Pattern, "variables", v.Key, "pattern")
+		}
+	}
+	return doc
+}
+
+// rawManifest is a manifest as decoded, before its spec is decoded by kind.
+type rawManifest struct {
+	APIVersion string    `yaml:"apiVersion"`
+	Kind       Kind      `yaml:"kind"`
+	Metadata   Metadata  `yaml:"metadata"`
+	Spec       yaml.Node `yaml:"spec"`
+}
+
+// Decode reads the YAML documents in r, each a single manifest. Unknown
+// fields are rejected so typos don't silently drop settings. source names r
+// in errors.
+func Decode(r io.Reader, source string) ([]*Manifest, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var manifests []*Manifest
+	for i := 1; ; i++ {
+		var raw rawManifest
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return manifests, nil
+		}
+		where := source + "#" + strconv.Itoa(i)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		if raw.Kind == "" && raw.APIVersion == "" && raw.Spec.Kind == 0 && raw.Metadata.Name == "" {
+			// An empty document, e.g. after a trailing "---".
+			continue
+		}
+
+		m, err := raw.manifest()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", where, err)
+		}
+		m.Source = where
+		manifests = append(manifests, m)
+	}
+}
+
+func (raw *rawManifest) manifest() (*Manifest, error) {
+	if raw.APIVersion != APIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q: must be %s", raw.APIVersion, APIVersion)
+	}
+
+	m := &Manifest{APIVersion: raw.APIVersion, Kind: raw.Kind, Metadata: raw.Metadata}
+	switch raw.Kind {
+	case KindApplication:
+		m.Spec = &ApplicationSpec{}
+	case KindCluster:
+		m.Spec = &ClusterSpec{}
+	case KindEnvironment:
+		m.Spec = &EnvironmentSpec{}
+	case KindVariableSet:
+		m.Spec = &VariableSetSpec{}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q: must be one of Application, Cluster, Environment, VariableSet", raw.Kind)
+	}
+
+	if raw.Spec.Kind != 0 {
+		// Re-encode the spec so it is decoded with KnownFields too.
+		b, err := yaml.Marshal(&raw.Spec)
+		if err != nil {
+			return nil, err
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true)
+		if err := dec.Decode(m.Spec); err != nil {
+			return nil, fmt.Errorf("invalid spec: %w", err)
+		}
+	}
+
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", m.ID(), err)
+	}
+	return m, nil
+}
+
+func (m *Manifest) validate() error {
+	md := m.Metadata
+	switch m.Kind {
+	case KindApplication, KindCluster:
+		if md.Name == "" {
+			return errors.New("metadata.name is required")
+		}
+		if md.Application != "" || md.Environment != "" {
+			return errors.New("metadata.application and metadata.environment are not allowed")
+		}
+	case KindEnvironment:
+		if md.Name == "" || md.Application == "" {
+			return errors.New("metadata.name and metadata.application are required")
+		}
+		if md.Environment != "" {
+			return errors.New("metadata.environment is not allowed")
+		}
+		spec := m.Spec.(*EnvironmentSpec)
+		if spec.Cluster == "" {
+			return errors.New("spec.cluster is required")
+		}
+		if _, err := parseLifecycle(spec.Lifecycle); err != nil {
+			return err
+		}
+		if spec.TTL != 0 && spec.Lifecycle != "ephemeral" {
+			return errors.New("spec.ttl can only be used with lifecycle ephemeral")
+		}
+	case KindVariableSet:
+		if md.Name != "" || len(md.Labels) > 0 {
+			return errors.New("metadata.name and metadata.labels are not allowed; a variable set is named by its scope")
+		}
+		if md.Environment != "" && md.Application == "" {
+			return errors.New("metadata.environment requires metadata.application")
+		}
+		return m.Spec.(*VariableSetSpec).validate()
+	}
+	return nil
+}
+
+// Load reads the manifests in paths. A directory is read recursively for
+// .yaml and .yml files in lexical order, and "-" reads stdin. Each resource
+// may be declared only once.
+func Load(stdin io.Reader, paths []string) ([]*Manifest, error) {
+	var manifests []*Manifest
+	for _, path := range paths {
+		files, err := expand(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			ms, err := loadFile(stdin, file)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, ms...)
+		}
+	}
+
+	seen := map[string]string{}
+	for _, m := range manifests {
+		if prev, ok := seen[m.ID()]; ok {
+			return nil, fmt.Errorf("%s: %s is already declared in %s", m.Source, m.ID(), prev)
+		}
+		seen[m.ID()] = m.Source
+	}
+	return manifests, nil
+}
+
+// expand returns the manifest files under path.
+func expand(path string) ([]string, error) {
+	if path == "-" {
+		return []string{path}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := filepath.Ext(p); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func loadFile(stdin io.Reader, path string) ([]*Manifest, error) {
+	if path == "-" {
+		return Decode(stdin, "<stdin>")
+	}
+	f, err := os.Open(path) //nolint:gosec // path is supplied by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	return Decode(f, path)
+}
+
+// sortManifests orders manifests by kindOrder, then by ID.
+func sortManifests(manifests []*Manifest) {
+	slices.SortStableFunc(manifests, func(a, b *Manifest) int {
+		if c := slices.Index(kindOrder, a.Kind) - slices.Index(kindOrder, b.Kind); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID(), b.ID())
+	})
+}
+
+func (s *VariableSetSpec) validate() error {
+	seen := map[string]bool{}
+	var errs []error
+	for _, v := range s.Variables {
+		if v.Key == "" {
+			errs = append(errs, errors.New("variable key is required"))
+			continue
+		}
+		if seen[v.Key] {
+			errs = append(errs, fmt.Errorf("variable %s is declared more than once", v.Key))
+			continue
+		}
+		seen[v.Key] = true
+
+		if v.ValueFrom != nil {
+			switch {
+			case v.Value != "":
+				errs = append(errs, fmt.Errorf("%s: value and valueFrom are mutually exclusive", v.Key))
+			case v.ValueFrom.Env == "":
+				errs = append(errs, fmt.Errorf("%s: valueFrom.env is required", v.Key))
+			default:
+				// The value is checked once it is resolved.
+				if _, err := parseType(v.Type); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", v.Key, err))
+				}
+			}
+			continue
+		}
+		if err := v.validateValue(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ResolveValues reads the values variables refer to with valueFrom, using
+// lookup to read environment variables, and checks them against their type
+// and pattern. References to unset environment variables are left
+// unresolved, which keeps the live value.
+func ResolveValues(manifests []*Manifest, lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, m := range manifests {
+		spec, ok := m.Spec.(*VariableSetSpec)
+		if !ok {
+			continue
+		}
+		for i := range spec.Variables {
+			v := &spec.Variables[i]
+			if v.ValueFrom == nil {
+				continue
+			}
+			value, ok := lookup(v.ValueFrom.Env)
+			if !ok {
+				continue
+			}
+			v.Value, v.resolved = value, true
+			if err := v.validateValue(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", m.ID(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseType converts a variable type name to its API enum. Empty is
+// untyped.
+func parseType(s string) (variablev1.VariableType, error) {
+	if s == "" {
+		return variablev1.VariableType_VARIABLE_TYPE_UNSPECIFIED, nil
+	}
+	return variables.ParseType(s)
+}
+
+// parseLifecycle converts a lifecycle name to its API enum. Empty leaves the
+// lifecycle to the server default.
+func parseLifecycle(s string) (environmentv1.EnvironmentLifecycle, error) {
+	switch s {
+	case "":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_UNSPECIFIED, nil
+	case "permanent":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_PERMANENT, nil
+	case "ephemeral":
+		return environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_EPHEMERAL, nil
+	default:
+		return 0, fmt.Errorf("invalid lifecycle %q: must be permanent or ephemeral", s)
+	}
+}
+
+// formatType returns the manifest name of an API variable type. Untyped and
+// string variables are both left empty.
+func formatType(t variablev1.VariableType) string {
+	if name := variables.FormatType(t); name != "string" {
+		return name
+	}
+	return ""
+}
+
+// formatLifecycle returns the manifest name of an API lifecycle.
+func formatLifecycle(l environmentv1.EnvironmentLifecycle) string {
+	if l == environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(l.String(), "ENVIRONMENT_LIFECYCLE_"))
+}
@@ -0,0 +1,326 @@
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.admiral.io/cli/internal/diff"
+	"go.admiral.io/cli/internal/variables"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+const platformYAML = `apiVersion: admiral.io/v1
+kind: VariableSet
+metadata:
+  application: billing-api
+  environment: staging
+spec:
+  variables:
+    - key: PORT
+      value: "8080"
+      type: int
+---
+apiVersion: admiral.io/v1
+kind: Environment
+metadata:
+  name: staging
+  application: billing-api
+spec:
+  cluster: us-east-1
+  promotionOrder: 10
+  sourceRef: main
+---
+apiVersion: admiral.io/v1
+kind: Application
+metadata:
+  name: billing-api
+  labels:
+    team: payments
+spec:
+  description: Handles billing
+---
+apiVersion: admiral.io/v1
+kind: Cluster
+metadata:
+  name: us-east-1
+---
+`
+
+func decode(t *testing.T, src string) []*Manifest {
+	t.Helper()
+	ms, err := Decode(strings.NewReader(src), "test.yaml")
+	require.NoError(t, err)
+	return ms
+}
+
+func TestDecode(t *testing.T) {
+	ms := decode(t, platformYAML)
+	require.Len(t, ms, 4)
+
+	require.Equal(t, "VariableSet/billing-api/staging", ms[0].ID())
+	require.Equal(t, "test.yaml#1", ms[0].Source)
+	require.Equal(t, []Variable{{Key: "PORT", Value: "8080", Type: "int"}}, ms[0].Spec.(*VariableSetSpec).Variables)
+
+	require.Equal(t, "Environment/billing-api/staging", ms[1].ID())
+	require.Equal(t, &EnvironmentSpec{Cluster: "us-east-1", PromotionOrder: 10, SourceRef: "main"}, ms[1].Spec)
+
+	require.Equal(t, "Application/billing-api", ms[2].ID())
+	require.Equal(t, "Handles billing", ms[2].Spec.(*ApplicationSpec).Description)
+
+	require.Equal(t, "Cluster/us-east-1", ms[3].ID())
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"missing apiVersion", "kind: Cluster\nmetadata: {name: a}\n", "unsupported apiVersion"},
+		{"unknown kind", "apiVersion: admiral.io/v1\nkind: Pod\nmetadata: {name: a}\n", `unsupported kind "Pod"`},
+		{"unknown field", "apiVersion: admiral.io/v1\nkind: Cluster\nmetdata: {name: a}\n", "metdata"},
+		{"unknown spec field", "apiVersion: admiral.io/v1\nkind: Application\nmetadata: {name: a}\nspec: {descripton: x}\n", "descripton"},
+		{"missing name", "apiVersion: admiral.io/v1\nkind: Application\n", "metadata.name is required"},
+		{"env without cluster", "apiVersion: admiral.io/v1\nkind: Environment\nmetadata: {name: a, application: b}\n", "spec.cluster is required"},
+		{"bad lifecycle", "apiVersion: admiral.io/v1\nkind: Environment\nmetadata: {name: a, application: b}\nspec: {cluster: c, lifecycle: forever}\n", "invalid lifecycle"},
+		{"ttl without ephemeral", "apiVersion: admiral.io/v1\nkind: Environment\nmetadata: {name: a, application: b}\nspec: {cluster: c, ttl: 24h}\n", "spec.ttl"},
+		{"env scope without app", "apiVersion: admiral.io/v1\nkind: VariableSet\nmetadata: {environment: a}\n", "requires metadata.application"},
+		{"duplicate key", "apiVersion: admiral.io/v1\nkind: VariableSet\nspec: {variables: [{key: A, value: x}, {key: A, value: y}]}\n", "more than once"},
+		{"invalid value", "apiVersion: admiral.io/v1\nkind: VariableSet\nspec: {variables: [{key: PORT, value: http, type: int}]}\n", "is not an int"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tc.src), "test.yaml")
+			require.ErrorContains(t, err, tc.want)
+			require.ErrorContains(t, err, "test.yaml#1")
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "apps"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "apps", "billing.yaml"), []byte(platformYAML), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600))
+
+	stdin := strings.NewReader("apiVersion: admiral.io/v1\nkind: Cluster\nmetadata: {name: eu-west-1}\n")
+	ms, err := Load(stdin, []string{dir, "-"})
+	require.NoError(t, err)
+	require.Len(t, ms, 5)
+	require.Equal(t, "Cluster/eu-west-1", ms[4].ID())
+
+	_, err = Load(nil, []string{dir, filepath.Join(dir, "apps", "billing.yaml")})
+	require.ErrorContains(t, err, "VariableSet/billing-api/staging is already declared in")
+}
+
+func TestNewPlan(t *testing.T) {
+	ms := decode(t, platformYAML)
+
+	live := Live{}
+	for _, m := range []*Manifest{
+		{Kind: KindCluster, Metadata: Metadata{Name: "us-east-1"}, Spec: &ClusterSpec{}},
+		{Kind: KindApplication, Metadata: Metadata{Name: "billing-api"}, Spec: &ApplicationSpec{Description: "Handles billing"}},
+		FromEnvironment("billing-api", &environmentv1.Environment{
+			Name:           "staging",
+			Cluster:        "us-east-1",
+			PromotionOrder: 20,
+			SourceRef:      "main",
+			Lifecycle:      environmentv1.EnvironmentLifecycle_ENVIRONMENT_LIFECYCLE_PERMANENT,
+		}),
+	} {
+		live[m.ID()] = m
+	}
+
+	plan, err := NewPlan(ms, live, Options{})
+	require.NoError(t, err)
+
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, string(c.Action)+" "+c.ID+" "+strings.Join(c.Fields, ","))
+	}
+	require.Equal(t, []string{
+		"unchanged Cluster/us-east-1 ",
+		"update Application/billing-api labels",
+		// The unset lifecycle is left to the server.
+		"update Environment/billing-api/staging promotion_order",
+		"create VariableSet/billing-api/staging PORT",
+	}, got)
+
+	var buf bytes.Buffer
+	PrintPlan(&buf, plan)
+	require.Equal(t, `~ Application/billing-api (labels)
+~ Environment/billing-api/staging (promotion_order)
++ VariableSet/billing-api/staging
+
+1 to create, 2 to update, 0 to delete, 1 unchanged.
+`, buf.String())
+}
+
+func TestNewPlan_ImmutableField(t *testing.T) {
+	ms := decode(t, platformYAML)
+	live := Live{}
+	env := FromEnvironment("billing-api", &environmentv1.Environment{Name: "staging", Cluster: "eu-west-1", PromotionOrder: 10, SourceRef: "main"})
+	live[env.ID()] = env
+
+	_, err := NewPlan(ms, live, Options{})
+	require.ErrorContains(t, err, "Environment/billing-api/staging: cluster cannot be changed")
+}
+
+func TestNewPlan_SetAndPrune(t *testing.T) {
+	ms := decode(t, platformYAML)
+	labels := map[string]string{ManagedByLabel: "platform"}
+
+	live := Live{}
+	for _, m := range []*Manifest{
+		{Kind: KindCluster, Metadata: Metadata{Name: "us-east-1", Labels: labels}, Spec: &ClusterSpec{}},
+		{Kind: KindCluster, Metadata: Metadata{Name: "old", Labels: labels}, Spec: &ClusterSpec{}},
+		{Kind: KindCluster, Metadata: Metadata{Name: "unmanaged"}, Spec: &ClusterSpec{}},
+		{Kind: KindApplication, Metadata: Metadata{Name: "retired", Labels: labels}, Spec: &ApplicationSpec{}},
+		FromEnvironment("retired", &environmentv1.Environment{Name: "prod", Cluster: "old", Labels: labels}),
+		FromVariables("billing-api", "staging", []*variablev1.Variable{
+			{Key: "PORT", Value: "8080", Type: variablev1.VariableType_VARIABLE_TYPE_INT},
+			{Key: "DEBUG", Value: "true"},
+		}),
+	} {
+		live[m.ID()] = m
+	}
+
+	_, err := NewPlan(ms, live, Options{Prune: true})
+	require.ErrorContains(t, err, "requires a manifest set")
+
+	plan, err := NewPlan(ms, live, Options{Set: "platform"})
+	require.NoError(t, err)
+	require.Equal(t, ActionUnchanged, plan.Changes[0].Action, "cluster already labelled")
+	require.Equal(t, "platform", plan.Changes[1].Desired.Metadata.Labels[ManagedByLabel])
+	require.Empty(t, ms[2].Metadata.Labels[ManagedByLabel], "manifests are not modified")
+	require.Equal(t, ActionUnchanged, plan.Changes[3].Action, "undeclared variables are kept")
+	require.Zero(t, plan.Count(ActionDelete))
+
+	plan, err = NewPlan(ms, live, Options{Set: "platform", Prune: true})
+	require.NoError(t, err)
+	var deletes []string
+	for _, c := range plan.Changes {
+		if c.Action == ActionDelete {
+			deletes = append(deletes, c.ID)
+		}
+	}
+	require.Equal(t, []string{"Environment/retired/prod", "Application/retired", "Cluster/old"}, deletes)
+
+	vs := plan.Changes[3]
+	require.Equal(t, ActionUpdate, vs.Action)
+	require.Equal(t, []string{"DEBUG"}, vs.Fields)
+	require.Equal(t, []diff.Operation{{Op: diff.OpRemove, Path: "/variables/DEBUG/value"}}, vs.Patch)
+	require.Equal(t, 1, plan.RemovedVariables())
+}
+
+func TestFromVariables(t *testing.T) {
+	m := FromVariables("", "", []*variablev1.Variable{
+		{Key: "TOKEN", Value: "s3cr3t", Sensitive: true},
+		{Key: "PORT", Value: "8080", Type: variablev1.VariableType_VARIABLE_TYPE_STRING},
+	})
+	require.Equal(t, "VariableSet/global", m.ID())
+	require.Equal(t, []Variable{
+		{Key: "PORT", Value: "8080"},
+		{Key: "TOKEN", Value: "<sensitive>", Sensitive: true},
+	}, m.Spec.(*VariableSetSpec).Variables)
+}
+
+func TestDocument(t *testing.T) {
+	m := &Manifest{
+		Kind:     KindEnvironment,
+		Metadata: Metadata{Name: "preview", Application: "a", Labels: map[string]string{"app.io/tier": "web"}},
+		Spec:     &EnvironmentSpec{Cluster: "c", Lifecycle: "ephemeral", TTL: 24 * time.Hour},
+	}
+	require.Equal(t, diff.Document{
+		"/labels/app.io~1tier": "web",
+		"/cluster":             "c",
+		"/lifecycle":           "ephemeral",
+		"/ttl":                 "24h0m0s",
+	}, m.Document())
+}
+
+func TestWriteDiff(t *testing.T) {
+	ms := decode(t, platformYAML)
+	live := Live{}
+	app := &Manifest{Kind: KindApplication, Metadata: Metadata{Name: "billing-api"}, Spec: &ApplicationSpec{Description: "Bills"}}
+	live[app.ID()] = app
+
+	plan, err := NewPlan(ms, live, Options{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	WriteDiff(&buf, plan, false)
+	require.Equal(t, `--- /dev/null
++++ manifest/Cluster/us-east-1
+--- live/Application/billing-api
++++ manifest/Application/billing-api
+@@ -1,1 +1,2 @@
+-description: Bills
++description: Handles billing
++labels.team: payments
+--- /dev/null
++++ manifest/Environment/billing-api/staging
+@@ -0,0 +1,3 @@
++cluster: us-east-1
++promotion_order: 10
++source_ref: main
+--- /dev/null
++++ manifest/VariableSet/billing-api/staging
+@@ -0,0 +1,2 @@
++variables.PORT.type: int
++variables.PORT.value: 8080
+`, buf.String())
+}
+
+func TestNewPlan_Redact(t *testing.T) {
+	ms := decode(t, `apiVersion: admiral.io/v1
+kind: VariableSet
+metadata: {}
+spec:
+  variables:
+    - {key: TOKEN, value: s3cr3t, sensitive: true}
+`)
+	live := Live{}
+	vs := FromVariables("", "", []*variablev1.Variable{{Key: "TOKEN", Value: "0ld", Sensitive: true}})
+	live[vs.ID()] = vs
+
+	plan, err := NewPlan(ms, live, Options{})
+	require.NoError(t, err)
+	require.Equal(t, ActionUpdate, plan.Changes[0].Action)
+
+	plan, err = NewPlan(ms, live, Options{Redact: true})
+	require.NoError(t, err)
+	require.Equal(t, ActionUnchanged, plan.Changes[0].Action)
+}
+
+func TestNewPlan_SensitiveValuesNeverShown(t *testing.T) {
+	ms := decode(t, `apiVersion: admiral.io/v1
+kind: VariableSet
+metadata: {}
+spec:
+  variables:
+    - {key: TOKEN, value: s3cr3t, sensitive: true}
+    - {key: PORT, value: "8080"}
+`)
+
+	plan, err := NewPlan(ms, Live{}, Options{})
+	require.NoError(t, err)
+	c := plan.Changes[0]
+	require.Equal(t, ActionCreate, c.Action)
+	require.Contains(t, c.Patch, diff.Operation{Op: diff.OpAdd, Path: "/variables/TOKEN/value", Value: variables.MaskedValue})
+
+	var buf bytes.Buffer
+	WriteDiff(&buf, plan, false)
+	require.Contains(t, buf.String(), "variables.TOKEN.value: "+variables.MaskedValue)
+	require.NotContains(t, buf.String(), "s3cr3t")
+
+	// Apply still sets the declared value.
+	require.Equal(t, "s3cr3t", c.Desired.Spec.(*VariableSetSpec).Variables[0].Value)
+}
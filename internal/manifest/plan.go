@@ -0,0 +1,568 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"go.admiral.io/cli/internal/diff"
+	"go.admiral.io/cli/internal/output"
+	"go.admiral.io/cli/internal/variables"
+	"go.admiral.io/sdk/client"
+	applicationv1 "go.admiral.io/sdk/proto/admiral/api/application/v1"
+	clusterv1 "go.admiral.io/sdk/proto/admiral/api/cluster/v1"
+	environmentv1 "go.admiral.io/sdk/proto/admiral/api/environment/v1"
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// Action is what a plan does to a resource.
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the planned change to a single resource.
+type Change struct {
+	Action Action `json:"action" yaml:"action"`
+	Kind   Kind   `json:"kind" yaml:"kind"`
+	ID     string `json:"id" yaml:"id"`
+	// Fields are the API fields an update sets, or for a VariableSet the
+	// keys it sets or deletes.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Patch turns the live resource into the declared one (RFC 6902).
+	Patch []diff.Operation `json:"patch,omitempty" yaml:"patch,omitempty"`
+
+	// Desired is the declared resource; nil for a delete.
+	Desired *Manifest `json:"-" yaml:"-"`
+	// Live is the live resource; nil for a create.
+	Live *Manifest `json:"-" yaml:"-"`
+
+	// from and to are the documents Patch was computed from.
+	from, to diff.Document
+}
+
+// Plan lists the changes that make live state match a set of manifests:
+// creates and updates in dependency order, then deletes in reverse.
+type Plan struct {
+	Changes []*Change `json:"changes" yaml:"changes"`
+}
+
+// Count returns the number of changes with action a.
+func (p *Plan) Count(a Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// RemovedVariables returns the number of variables the plan deletes from
+// declared VariableSets when pruning.
+func (p *Plan) RemovedVariables() int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action != ActionUpdate || c.Kind != KindVariableSet {
+			continue
+		}
+		declared := map[string]bool{}
+		for _, v := range c.Desired.Spec.(*VariableSetSpec).Variables {
+			declared[v.Key] = true
+		}
+		for _, key := range c.Fields {
+			if !declared[key] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Empty reports whether the plan changes nothing.
+func (p *Plan) Empty() bool {
+	return p.Count(ActionUnchanged) == len(p.Changes)
+}
+
+// Options controls how a plan is made.
+type Options struct {
+	// Set names the manifest set. Applications, clusters and environments
+	// are labelled with it using ManagedByLabel.
+	Set string
+	// Prune deletes resources labelled with Set that are no longer
+	// declared, and variables missing from a declared VariableSet.
+	Prune bool
+	// Redact masks declared sensitive values before comparing them. The
+	// API never returns sensitive values, so they are then not compared.
+	// Either way, Patch and the diff show them masked; only Desired holds
+	// them. Unresolved valueFrom references are no error when redacting: a
+	// variable that doesn't exist yet is planned with a masked value, so a
+	// plan can be made without the secrets at hand.
+	Redact bool
+}
+
+// serverDefaulted are the environment fields the server fills in when they
+// are left unset, so an unset field in a manifest leaves them alone.
+var serverDefaulted = []string{diff.Pointer("lifecycle"), diff.Pointer("ttl")}
+
+// immutable are the environment fields that can't be updated.
+var immutable = []string{"cluster", "lifecycle", "parent"}
+
+// NewPlan compares manifests with live and returns the changes that make
+// live match them.
+func NewPlan(manifests []*Manifest, live Live, opts Options) (*Plan, error) {
+	if opts.Prune && opts.Set == "" {
+		return nil, errors.New("pruning requires a manifest set")
+	}
+
+	desired := make([]*Manifest, 0, len(manifests))
+	declared := map[string]bool{}
+	for _, m := range manifests {
+		m = labelled(m, opts.Set)
+		desired = append(desired, m)
+		declared[m.ID()] = true
+	}
+	sortManifests(desired)
+
+	plan := &Plan{}
+	var errs []error
+	for _, m := range desired {
+		cur := live[m.ID()]
+		c := &Change{Kind: m.Kind, ID: m.ID(), Desired: m, Live: cur}
+
+		to := m.Document()
+		from := diff.Document{}
+		if cur != nil {
+			from = cur.Document()
+		}
+		switch m.Kind {
+		case KindEnvironment:
+			for _, path := range serverDefaulted {
+				if _, ok := to[path]; !ok {
+					delete(from, path)
+				}
+			}
+		case KindVariableSet:
+			missing := trimVariables(from, m, cur, opts.Prune)
+			if opts.Redact {
+				redact(to, m)
+				for _, v := range missing {
+					to[diff.Pointer("variables", v.Key, "value")] = variables.MaskedValue
+				}
+			} else {
+				for _, v := range missing {
+					errs = append(errs, fmt.Errorf("%s: %s: $%s is not set and the variable doesn't exist yet", m.ID(), v.Key, v.ValueFrom.Env))
+				}
+			}
+		}
+
+		c.Patch = diff.Compare(from, to)
+		if m.Kind == KindVariableSet && !opts.Redact {
+			redact(to, m)
+			redactPatch(c.Patch, m)
+		}
+		c.from, c.to = from, to
+		switch {
+		case cur == nil:
+			c.Action = ActionCreate
+		case len(c.Patch) == 0:
+			c.Action = ActionUnchanged
+		default:
+			c.Action = ActionUpdate
+		}
+		if c.Action != ActionUnchanged {
+			c.Fields = changedFields(m.Kind, c.Patch)
+		}
+
+		if c.Action == ActionUpdate && m.Kind == KindVariableSet && !opts.Redact {
+			for _, v := range m.Spec.(*VariableSetSpec).Variables {
+				if v.unresolved() && slices.Contains(c.Fields, v.Key) {
+					errs = append(errs, fmt.Errorf("%s: %s: set $%s to change the variable", c.ID, v.Key, v.ValueFrom.Env))
+				}
+			}
+		}
+		if c.Action == ActionUpdate && m.Kind == KindEnvironment {
+			for _, f := range immutable {
+				if slices.Contains(c.Fields, f) {
+					errs = append(errs, fmt.Errorf("%s: %s cannot be changed; delete and re-create the environment instead", c.ID, f))
+				}
+			}
+		}
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	if opts.Prune {
+		var pruned []*Manifest
+		for _, id := range slices.Sorted(maps.Keys(live)) {
+			m := live[id]
+			if !declared[id] && m.Kind != KindVariableSet && m.Metadata.Labels[ManagedByLabel] == opts.Set {
+				pruned = append(pruned, m)
+			}
+		}
+		sortManifests(pruned)
+		slices.Reverse(pruned)
+		for _, m := range pruned {
+			plan.Changes = append(plan.Changes, &Change{
+				Action: ActionDelete,
+				Kind:   m.Kind,
+				ID:     m.ID(),
+				Live:   m,
+				from:   m.Document(),
+				to:     diff.Document{},
+			})
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// labelled returns a copy of m with ManagedByLabel set to set. Variable sets
+// have no labels and are returned as is, as is m when set is empty.
+func labelled(m *Manifest, set string) *Manifest {
+	if set == "" || m.Kind == KindVariableSet {
+		return m
+	}
+	cp := *m
+	cp.Metadata.Labels = maps.Clone(m.Metadata.Labels)
+	if cp.Metadata.Labels == nil {
+		cp.Metadata.Labels = map[string]string{}
+	}
+	cp.Metadata.Labels[ManagedByLabel] = set
+	return &cp
+}
+
+// variableField splits the path of a variable field, e.g.
+// /variables/PORT/type, into the key and field name.
+func variableField(path string) (key, field string) {
+	tokens := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	return unescape(tokens[1]), tokens[2]
+}
+
+// trimVariables removes the variables a plan leaves alone from from, the
+// live document of the VariableSet m: undeclared variables unless pruning,
+// and the values of variables whose valueFrom is unresolved. It returns the
+// unresolved variables that don't exist yet, which have no value to keep.
+func trimVariables(from diff.Document, m, live *Manifest, prune bool) []Variable {
+	declared := map[string]*Variable{}
+	spec := m.Spec.(*VariableSetSpec)
+	for i := range spec.Variables {
+		declared[spec.Variables[i].Key] = &spec.Variables[i]
+	}
+
+	existing := map[string]bool{}
+	if live != nil {
+		for _, v := range live.Spec.(*VariableSetSpec).Variables {
+			existing[v.Key] = true
+		}
+	}
+
+	for path := range from {
+		key, field := variableField(path)
+		v, ok := declared[key]
+		if (!ok && !prune) || (ok && field == "value" && v.unresolved()) {
+			delete(from, path)
+		}
+	}
+
+	var missing []Variable
+	for _, v := range spec.Variables {
+		if v.unresolved() && !existing[v.Key] {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+// redact replaces the sensitive values in to, the document of the
+// VariableSet m, with variables.MaskedValue.
+func redact(to diff.Document, m *Manifest) {
+	for _, v := range m.Spec.(*VariableSetSpec).Variables {
+		path := diff.Pointer("variables", v.Key, "value")
+		if _, ok := to[path]; ok && v.Sensitive {
+			to[path] = variables.MaskedValue
+		}
+	}
+}
+
+// redactPatch replaces the sensitive values patch sets in the VariableSet m
+// with variables.MaskedValue.
+func redactPatch(patch []diff.Operation, m *Manifest) {
+	for _, v := range m.Spec.(*VariableSetSpec).Variables {
+		if !v.Sensitive {
+			continue
+		}
+		path := diff.Pointer("variables", v.Key, "value")
+		for i := range patch {
+			if patch[i].Path == path && patch[i].Value != nil {
+				patch[i].Value = variables.MaskedValue
+			}
+		}
+	}
+}
+
+// changedFields returns the top-level fields patch touches, or for a
+// VariableSet the variable keys, in order.
+func changedFields(kind Kind, patch []diff.Operation) []string {
+	var fields []string
+	for _, op := range patch {
+		tokens := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+		f := tokens[0]
+		if kind == KindVariableSet {
+			f = unescape(tokens[1])
+		}
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// unescape decodes a JSON pointer reference token.
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// PrintPlan writes a line per change followed by a summary, e.g.
+//
+//   - Cluster/us-east-1
+//     ~ Application/billing-api (description, labels)
+//
+//   - Environment/billing-api/preview-42
+//
+//     1 to create, 1 to update, 1 to delete, 3 unchanged.
+func PrintPlan(w io.Writer, plan *Plan) {
+	if plan.Empty() {
+		output.Writef(w, "No changes. %d unchanged.\n", plan.Count(ActionUnchanged))
+		return
+	}
+
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case ActionCreate:
+			output.Writef(w, "+ %s\n", c.ID)
+		case ActionUpdate:
+			output.Writef(w, "~ %s (%s)\n", c.ID, strings.Join(c.Fields, ", "))
+		case ActionDelete:
+			output.Writef(w, "- %s\n", c.ID)
+		}
+	}
+	output.Writef(w, "\n%d to create, %d to update, %d to delete, %d unchanged.\n",
+		plan.Count(ActionCreate), plan.Count(ActionUpdate), plan.Count(ActionDelete), plan.Count(ActionUnchanged))
+}
+
+// WriteDiff writes a unified diff per changed resource, from its live state
+// to its manifest. Created resources are diffed from /dev/null and deleted
+// ones to it; those without fields, such as an unlabelled cluster, print
+// only the file headers.
+func WriteDiff(w io.Writer, plan *Plan, color bool) {
+	for _, c := range plan.Changes {
+		fromName, toName := "live/"+c.ID, "manifest/"+c.ID
+		switch c.Action {
+		case ActionUnchanged:
+			continue
+		case ActionCreate:
+			fromName = "/dev/null"
+		case ActionDelete:
+			toName = "/dev/null"
+		}
+		if len(c.from) == 0 && len(c.to) == 0 {
+			output.Writef(w, "--- %s\n+++ %s\n", fromName, toName)
+			continue
+		}
+		diff.WriteUnified(w, fromName, toName, c.from, c.to, color)
+	}
+}
+
+// Apply makes the changes in plan, in order, stopping at the first error.
+// Tokens of created clusters are written to stderr.
+func Apply(ctx context.Context, c client.AdmiralClient, plan *Plan, stderr io.Writer) error {
+	for _, ch := range plan.Changes {
+		var err error
+		switch ch.Action {
+		case ActionUnchanged:
+			continue
+		case ActionDelete:
+			err = applyDelete(ctx, c, ch.Live)
+		default:
+			switch ch.Kind {
+			case KindCluster:
+				err = applyCluster(ctx, c, ch, stderr)
+			case KindApplication:
+				err = applyApplication(ctx, c, ch)
+			case KindEnvironment:
+				err = applyEnvironment(ctx, c, ch)
+			case KindVariableSet:
+				err = applyVariableSet(ctx, c, ch)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s %s: %w", ch.Action, ch.ID, err)
+		}
+	}
+	return nil
+}
+
+func applyCluster(ctx context.Context, c client.AdmiralClient, ch *Change, stderr io.Writer) error {
+	md := ch.Desired.Metadata
+	if ch.Action == ActionCreate {
+		resp, err := c.Cluster().CreateCluster(ctx, &clusterv1.CreateClusterRequest{
+			Name:   md.Name,
+			Labels: md.Labels,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.PlainTextToken != "" {
+			output.Writef(stderr, "Cluster %s created.\n", md.Name)
+			output.PrintToken(stderr, resp.PlainTextToken)
+		}
+		return nil
+	}
+
+	_, err := c.Cluster().UpdateCluster(ctx, &clusterv1.UpdateClusterRequest{
+		Cluster:    &clusterv1.Cluster{Id: md.Name, Labels: md.Labels},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: ch.Fields},
+	})
+	return err
+}
+
+func applyApplication(ctx context.Context, c client.AdmiralClient, ch *Change) error {
+	md := ch.Desired.Metadata
+	spec := ch.Desired.Spec.(*ApplicationSpec)
+	if ch.Action == ActionCreate {
+		req := &applicationv1.CreateApplicationRequest{Name: md.Name, Labels: md.Labels}
+		if spec.Description != "" {
+			req.Description = &spec.Description
+		}
+		_, err := c.Application().CreateApplication(ctx, req)
+		return err
+	}
+
+	_, err := c.Application().UpdateApplication(ctx, &applicationv1.UpdateApplicationRequest{
+		Application: &applicationv1.Application{
+			Id:          md.Name,
+			Description: spec.Description,
+			Labels:      md.Labels,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: ch.Fields},
+	})
+	return err
+}
+
+func applyEnvironment(ctx context.Context, c client.AdmiralClient, ch *Change) error {
+	md := ch.Desired.Metadata
+	spec := ch.Desired.Spec.(*EnvironmentSpec)
+	var ttl *durationpb.Duration
+	if spec.TTL != 0 {
+		ttl = durationpb.New(spec.TTL)
+	}
+
+	if ch.Action == ActionCreate {
+		lc, err := parseLifecycle(spec.Lifecycle)
+		if err != nil {
+			return err
+		}
+		_, err = c.Environment().CreateEnvironment(ctx, &environmentv1.CreateEnvironmentRequest{
+			ApplicationId:  md.Application,
+			Name:           md.Name,
+			Cluster:        spec.Cluster,
+			PromotionOrder: spec.PromotionOrder,
+			Lifecycle:      lc,
+			Parent:         spec.Parent,
+			SourceRef:      spec.SourceRef,
+			Ttl:            ttl,
+			Labels:         md.Labels,
+		})
+		return err
+	}
+
+	_, err := c.Environment().UpdateEnvironment(ctx, &environmentv1.UpdateEnvironmentRequest{
+		Environment: &environmentv1.Environment{
+			Id:             md.Name,
+			ApplicationId:  md.Application,
+			PromotionOrder: spec.PromotionOrder,
+			SourceRef:      spec.SourceRef,
+			Ttl:            ttl,
+			Labels:         md.Labels,
+		},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: ch.Fields},
+	})
+	return err
+}
+
+// applyVariableSet sets the variables of the set that changed and deletes
+// the changed keys it no longer declares.
+func applyVariableSet(ctx context.Context, c client.AdmiralClient, ch *Change) error {
+	md := ch.Desired.Metadata
+	declared := map[string]Variable{}
+	for _, v := range ch.Desired.Spec.(*VariableSetSpec).Variables {
+		declared[v.Key] = v
+	}
+
+	for _, key := range ch.Fields {
+		v, ok := declared[key]
+		if !ok {
+			_, err := c.Variable().DeleteVariable(ctx, &variablev1.DeleteVariableRequest{
+				Scope:         variableScope(md),
+				ApplicationId: md.Application,
+				EnvironmentId: md.Environment,
+				Key:           key,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete %s: %w", key, err)
+			}
+			continue
+		}
+
+		typ, err := parseType(v.Type)
+		if err != nil {
+			return err
+		}
+		_, err = c.Variable().SetVariable(ctx, &variablev1.SetVariableRequest{
+			Scope:         variableScope(md),
+			ApplicationId: md.Application,
+			EnvironmentId: md.Environment,
+			Key:           v.Key,
+			Value:         v.Value,
+			Sensitive:     v.Sensitive,
+			Type:          typ,
+			Pattern:       v.Pattern,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func applyDelete(ctx context.Context, c client.AdmiralClient, m *Manifest) error {
+	var err error
+	switch m.Kind {
+	case KindCluster:
+		_, err = c.Cluster().DeleteCluster(ctx, &clusterv1.DeleteClusterRequest{ClusterId: m.Metadata.Name})
+	case KindApplication:
+		_, err = c.Application().DeleteApplication(ctx, &applicationv1.DeleteApplicationRequest{ApplicationId: m.Metadata.Name})
+	case KindEnvironment:
+		_, err = c.Environment().DeleteEnvironment(ctx, &environmentv1.DeleteEnvironmentRequest{
+			ApplicationId: m.Metadata.Application,
+			EnvironmentId: m.Metadata.Name,
+		})
+	default:
+		err = fmt.Errorf("%s resources cannot be deleted", m.Kind)
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Column is a column of custom-columns output: a header and the JSONPath
+// evaluated against each resource for its cells.
+type Column struct {
+	Header string
+	Path   *JSONPath
+}
+
+// ParseCustomColumns parses a custom-columns spec such as
+// "NAME:.name,UID:.clusterUid,TEAM:.labels.team". Paths may be written bare
+// or in braces, e.g. "NAME:{.name}".
+func ParseCustomColumns(spec string) ([]Column, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, errors.New("custom-columns requires at least one HEADER:PATH column")
+	}
+
+	var cols []Column
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || strings.TrimSpace(header) == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid custom-columns column %q: expected HEADER:PATH", part)
+		}
+		col, err := newColumn(header, path)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// ParseCustomColumnsFile parses the contents of a custom-columns file: a line
+// of headers and a line of paths, separated by whitespace, e.g.
+//
+//	NAME    UID           TEAM
+//	.name   .clusterUid   .labels.team
+func ParseCustomColumnsFile(text string) ([]Column, error) {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 2 {
+		return nil, fmt.Errorf("custom-columns-file must have exactly 2 lines (headers and paths), got %d", len(lines))
+	}
+
+	headers, paths := strings.Fields(lines[0]), strings.Fields(lines[1])
+	if len(headers) != len(paths) {
+		return nil, fmt.Errorf("custom-columns-file has %d headers but %d paths", len(headers), len(paths))
+	}
+
+	cols := make([]Column, 0, len(headers))
+	for i := range headers {
+		col, err := newColumn(headers[i], paths[i])
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func newColumn(header, path string) (Column, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "{") {
+		if !strings.HasPrefix(path, ".") && !strings.HasPrefix(path, "$") {
+			path = "." + path
+		}
+		path = "{" + path + "}"
+	}
+	jp, err := ParseJSONPath(path)
+	if err != nil {
+		return Column{}, fmt.Errorf("invalid custom-columns column %q: %w", header, err)
+	}
+	return Column{Header: strings.TrimSpace(header), Path: jp}, nil
+}
+
+// readCustomColumnsFile reads and parses the custom-columns file at path.
+func readCustomColumnsFile(path string) ([]Column, error) {
+	text, err := os.ReadFile(path) //nolint:gosec // path is supplied by the user on purpose
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom-columns-file: %w", err)
+	}
+	return ParseCustomColumnsFile(string(text))
+}
+
+// printColumns writes a table with a row per resource in data. Cells whose
+// path matches nothing show <none>.
+func (p *Printer) printColumns(cols []Column, data any) error {
+	w := NewTableWriter(p.Out)
+
+	headers := make([]string, len(cols))
+	for i, col := range cols {
+		headers[i] = col.Header
+	}
+	Writeln(w, strings.Join(headers, "\t"))
+
+	for _, item := range resourceItems(data) {
+		cells := make([]string, len(cols))
+		for i, col := range cols {
+			var b strings.Builder
+			if err := col.Path.Execute(&b, item); err != nil {
+				return err
+			}
+			cells[i] = b.String()
+			if cells[i] == "" {
+				cells[i] = "<none>"
+			}
+		}
+		Writeln(w, strings.Join(cells, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// resourceItems returns the rows of custom-columns output for data: the
+// elements of the lists in a list response, or the resource of a single
+// resource response. Data with neither is a single row.
+func resourceItems(data any) []any {
+	if items, ok := findItems(data); ok {
+		return items
+	}
+	return []any{data}
+}
+
+// findItems searches data like resourceNames, except that the elements of a
+// list are items whether or not they have a name. It reports whether a list
+// or resource was found, so an empty list yields no rows.
+func findItems(data any) ([]any, bool) {
+	switch v := data.(type) {
+	case []any:
+		return v, true
+	case map[string]any:
+		for _, f := range nameFields {
+			if name, ok := v[f].(string); ok && name != "" {
+				return []any{v}, true
+			}
+		}
+		var (
+			items []any
+			found bool
+		)
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if el, ok := findItems(v[k]); ok {
+				items = append(items, el...)
+				found = true
+			}
+		}
+		return items, found
+	default:
+		return nil, false
+	}
+}
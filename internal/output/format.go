@@ -1,8 +1,13 @@
 package output
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
 
-// Format represents the output format for CLI commands.
+// Format represents the output format for CLI commands. Template formats
+// carry their argument after "=", e.g. "jsonpath={.name}".
 type Format string
 
 const (
@@ -10,18 +15,76 @@ const (
 	FormatJSON  Format = "json"
 	FormatYAML  Format = "yaml"
 	FormatWide  Format = "wide"
+	FormatName  Format = "name"
+
+	FormatJSONPath       Format = "jsonpath"
+	FormatGoTemplate     Format = "go-template"
+	FormatGoTemplateFile Format = "go-template-file"
+
+	FormatCustomColumns     Format = "custom-columns"
+	FormatCustomColumnsFile Format = "custom-columns-file"
+	// FormatView names a custom-columns spec saved in the config file. The
+	// root command resolves it to FormatCustomColumns before printing.
+	FormatView Format = "view"
 )
 
-// ParseFormat validates and returns a Format from a string.
+// FormatHelp lists the accepted output formats for flag help text.
+const FormatHelp = "table, json, yaml, wide, name, jsonpath=TEMPLATE, go-template=TEMPLATE, go-template-file=PATH, custom-columns=SPEC, custom-columns-file=PATH, view=NAME"
+
+// ParseFormat validates and returns a Format from a string. Templates are
+// parsed so syntax errors are reported before any request is made.
 func ParseFormat(s string) (Format, error) {
-	switch Format(s) {
-	case FormatTable, FormatJSON, FormatYAML, FormatWide:
-		return Format(s), nil
+	f := Format(s)
+	switch f.Kind() {
+	case FormatTable, FormatJSON, FormatYAML, FormatWide, FormatName:
+		if strings.Contains(s, "=") {
+			return "", fmt.Errorf("output format %q does not take an argument", f.Kind())
+		}
+		return f, nil
+	case FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile,
+		FormatCustomColumns, FormatCustomColumnsFile, FormatView:
+		if f.Arg() == "" {
+			return "", fmt.Errorf("output format %q requires an argument, e.g. %s=...", f.Kind(), f.Kind())
+		}
+		switch f.Kind() {
+		case FormatJSONPath:
+			if _, err := ParseJSONPath(f.Arg()); err != nil {
+				return "", err
+			}
+		case FormatGoTemplate:
+			if _, err := template.New("output").Parse(f.Arg()); err != nil {
+				return "", fmt.Errorf("invalid go-template: %w", err)
+			}
+		case FormatCustomColumns:
+			if _, err := ParseCustomColumns(f.Arg()); err != nil {
+				return "", err
+			}
+		}
+		return f, nil
 	default:
-		return "", fmt.Errorf("invalid output format %q: must be one of table, json, yaml, wide", s)
+		return "", fmt.Errorf("invalid output format %q: must be one of %s", s, FormatHelp)
 	}
 }
 
+// Kind returns the format without its argument, e.g. FormatJSONPath for
+// "jsonpath={.name}".
+func (f Format) Kind() Format {
+	kind, _, _ := strings.Cut(string(f), "=")
+	return Format(kind)
+}
+
+// Arg returns the argument of a template or column format, or "" if it has none.
+func (f Format) Arg() string {
+	_, arg, _ := strings.Cut(string(f), "=")
+	return arg
+}
+
+// IsTable reports whether f is a table format (table or wide), which
+// commands render themselves.
+func (f Format) IsTable() bool {
+	return f == FormatTable || f == FormatWide
+}
+
 // String returns the string representation of the Format.
 func (f Format) String() string {
 	return string(f)
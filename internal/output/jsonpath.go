@@ -0,0 +1,542 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed kubectl-style JSONPath template, such as
+// `{.clusters[*].name}` or `{range .clusters[*]}{.name}{"\n"}{end}`.
+//
+// The supported syntax is:
+//
+//	text            copied to the output as is
+//	{"\n"}          a double-quoted string literal, with Go escapes
+//	{.a.b}, {$.a}   a field of the current or root object
+//	{..name}        every "name" field at any depth
+//	{.a[*]}         every element of an array or value of an object
+//	{.a[0]}         an array element; negative indexes count from the end
+//	{.a[1:3]}       a slice of an array
+//	{.a['b-c']}     a field whose name isn't an identifier
+//	{.a[?(@.b=="x")]}  array elements where .b equals (or !=) a literal, or
+//	                   [?(@.b)] where .b is set
+//	{range .a[*]}...{end}  the enclosed template once per result, with
+//	                   the result as the current object
+//
+// Missing fields produce no output rather than an error. Multiple results are
+// separated by spaces; objects and arrays are printed as JSON.
+type JSONPath struct {
+	nodes []jpNode
+}
+
+type jpNodeKind int
+
+const (
+	jpText jpNodeKind = iota
+	jpPath
+	jpRange
+)
+
+type jpNode struct {
+	kind jpNodeKind
+	text string
+	path *jpPathExpr
+	body []jpNode
+}
+
+type jpSegmentKind int
+
+const (
+	jpField jpSegmentKind = iota
+	jpRecursive
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpFilter
+)
+
+type jpSegment struct {
+	kind       jpSegmentKind
+	name       string
+	index      int
+	start, end *int
+	filter     *jpFilterExpr
+}
+
+type jpPathExpr struct {
+	root     bool
+	segments []jpSegment
+}
+
+type jpFilterExpr struct {
+	path *jpPathExpr
+	// op is "==", "!=" or "" for an existence check.
+	op    string
+	value any
+}
+
+// ParseJSONPath parses a JSONPath template.
+func ParseJSONPath(tmpl string) (*JSONPath, error) {
+	nodes, rest, err := parseJPNodes(tmpl, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != len(tmpl) {
+		return nil, fmt.Errorf("jsonpath: unexpected {end} at offset %d", rest)
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseJPNodes parses nodes from tmpl[pos:] until the end of input or, if
+// inRange, a {end} action. It returns the offset after the last node read.
+func parseJPNodes(tmpl string, pos int, inRange bool) ([]jpNode, int, error) {
+	var nodes []jpNode
+	for pos < len(tmpl) {
+		open := strings.IndexByte(tmpl[pos:], '{')
+		if open < 0 {
+			nodes = append(nodes, jpNode{kind: jpText, text: tmpl[pos:]})
+			pos = len(tmpl)
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jpNode{kind: jpText, text: tmpl[pos : pos+open]})
+		}
+		start := pos + open
+		end, err := matchBrace(tmpl, start)
+		if err != nil {
+			return nil, 0, err
+		}
+		action := strings.TrimSpace(tmpl[start+1 : end])
+		next := end + 1
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nodes, start, nil
+			}
+			return nodes, next, nil
+		case strings.HasPrefix(action, "range "):
+			path, err := parseJPPath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, 0, err
+			}
+			body, after, err := parseJPNodes(tmpl, next, true)
+			if err != nil {
+				return nil, 0, err
+			}
+			nodes = append(nodes, jpNode{kind: jpRange, path: path, body: body})
+			next = after
+		case strings.HasPrefix(action, `"`):
+			s, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, 0, fmt.Errorf("jsonpath: invalid string literal %s at offset %d", action, start)
+			}
+			nodes = append(nodes, jpNode{kind: jpText, text: s})
+		default:
+			path, err := parseJPPath(action)
+			if err != nil {
+				return nil, 0, err
+			}
+			nodes = append(nodes, jpNode{kind: jpPath, path: path})
+		}
+		pos = next
+	}
+	if inRange {
+		return nil, 0, fmt.Errorf("jsonpath: {range} has no {end}")
+	}
+	return nodes, pos, nil
+}
+
+// matchBrace returns the offset of the "}" closing the "{" at start,
+// skipping quoted strings.
+func matchBrace(s string, start int) (int, error) {
+	var quote byte
+	for i := start + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed { at offset %d", start)
+}
+
+func isJPIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseJPPath parses a path expression such as $.a[*].b or @.b.
+func parseJPPath(expr string) (*jpPathExpr, error) {
+	p := &jpPathExpr{}
+	s := expr
+	switch {
+	case strings.HasPrefix(s, "$"):
+		p.root = true
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		s = s[1:]
+	case s != "" && s[0] != '.' && s[0] != '[':
+		s = "." + s
+	}
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			n := identLen(s[2:])
+			if n == 0 {
+				return nil, fmt.Errorf("jsonpath: expected a field name after .. in %q", expr)
+			}
+			p.segments = append(p.segments, jpSegment{kind: jpRecursive, name: s[2 : 2+n]})
+			s = s[2+n:]
+		case strings.HasPrefix(s, ".*"):
+			p.segments = append(p.segments, jpSegment{kind: jpWildcard})
+			s = s[2:]
+		case s[0] == '.':
+			n := identLen(s[1:])
+			if n == 0 {
+				if len(s) == 1 {
+					// A lone "." is the current object.
+					s = ""
+					continue
+				}
+				return nil, fmt.Errorf("jsonpath: expected a field name at %q in %q", s, expr)
+			}
+			p.segments = append(p.segments, jpSegment{kind: jpField, name: s[1 : 1+n]})
+			s = s[1+n:]
+		case s[0] == '[':
+			end, err := matchBracket(s)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %w in %q", err, expr)
+			}
+			seg, err := parseJPSubscript(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %w in %q", err, expr)
+			}
+			p.segments = append(p.segments, seg)
+			s = s[end+1:]
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected %q in %q", s, expr)
+		}
+	}
+	return p, nil
+}
+
+func identLen(s string) int {
+	n := 0
+	for n < len(s) && isJPIdentByte(s[n]) {
+		n++
+	}
+	return n
+}
+
+// matchBracket returns the offset of the "]" closing the "[" at s[0],
+// skipping quoted strings and nested brackets.
+func matchBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed [")
+}
+
+func parseJPSubscript(sub string) (jpSegment, error) {
+	switch {
+	case sub == "*":
+		return jpSegment{kind: jpWildcard}, nil
+	case strings.HasPrefix(sub, "?(") && strings.HasSuffix(sub, ")"):
+		f, err := parseJPFilter(strings.TrimSpace(sub[2 : len(sub)-1]))
+		if err != nil {
+			return jpSegment{}, err
+		}
+		return jpSegment{kind: jpFilter, filter: f}, nil
+	case strings.HasPrefix(sub, "'") || strings.HasPrefix(sub, `"`):
+		name, err := unquoteJP(sub)
+		if err != nil {
+			return jpSegment{}, err
+		}
+		return jpSegment{kind: jpField, name: name}, nil
+	case strings.Contains(sub, ":"):
+		lo, hi, _ := strings.Cut(sub, ":")
+		seg := jpSegment{kind: jpSlice}
+		for _, b := range []struct {
+			text string
+			dest **int
+		}{{lo, &seg.start}, {hi, &seg.end}} {
+			if t := strings.TrimSpace(b.text); t != "" {
+				n, err := strconv.Atoi(t)
+				if err != nil {
+					return jpSegment{}, fmt.Errorf("invalid slice [%s]", sub)
+				}
+				*b.dest = &n
+			}
+		}
+		return seg, nil
+	default:
+		n, err := strconv.Atoi(sub)
+		if err != nil {
+			return jpSegment{}, fmt.Errorf("invalid subscript [%s]", sub)
+		}
+		return jpSegment{kind: jpIndex, index: n}, nil
+	}
+}
+
+func parseJPFilter(expr string) (*jpFilterExpr, error) {
+	for _, op := range []string{"==", "!="} {
+		lhs, rhs, ok := strings.Cut(expr, op)
+		if !ok {
+			continue
+		}
+		path, err := parseJPPath(strings.TrimSpace(lhs))
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseJPLiteral(strings.TrimSpace(rhs))
+		if err != nil {
+			return nil, err
+		}
+		return &jpFilterExpr{path: path, op: op, value: value}, nil
+	}
+
+	path, err := parseJPPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &jpFilterExpr{path: path}, nil
+}
+
+// parseJPLiteral parses the right-hand side of a filter comparison: a quoted
+// string, number, boolean or null.
+func parseJPLiteral(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquoteJP(s)
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %s", s)
+	}
+	return f, nil
+}
+
+func unquoteJP(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	v, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return v, nil
+}
+
+// Execute writes the template evaluated against data, a value decoded from
+// JSON.
+func (j *JSONPath) Execute(w io.Writer, data any) error {
+	var b strings.Builder
+	if err := executeJPNodes(&b, j.nodes, data, data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func executeJPNodes(b *strings.Builder, nodes []jpNode, root, cur any) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case jpText:
+			b.WriteString(n.text)
+		case jpPath:
+			for i, v := range n.path.eval(root, cur) {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				s, err := formatJPValue(v)
+				if err != nil {
+					return err
+				}
+				b.WriteString(s)
+			}
+		case jpRange:
+			for _, v := range n.path.eval(root, cur) {
+				if err := executeJPNodes(b, n.body, root, v); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func formatJPValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("jsonpath: %w", err)
+		}
+		return string(b), nil
+	}
+}
+
+func (p *jpPathExpr) eval(root, cur any) []any {
+	values := []any{cur}
+	if p.root {
+		values = []any{root}
+	}
+	for _, seg := range p.segments {
+		var next []any
+		for _, v := range values {
+			next = append(next, seg.apply(root, v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s jpSegment) apply(root, v any) []any {
+	switch s.kind {
+	case jpField:
+		if m, ok := v.(map[string]any); ok {
+			if f, ok := m[s.name]; ok {
+				return []any{f}
+			}
+		}
+		return nil
+	case jpRecursive:
+		var out []any
+		collectJPField(v, s.name, &out)
+		return out
+	case jpWildcard:
+		return jpChildren(v)
+	case jpIndex:
+		a, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		i := s.index
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil
+		}
+		return []any{a[i]}
+	case jpSlice:
+		a, ok := v.([]any)
+		if !ok {
+			return nil
+		}
+		lo, hi := 0, len(a)
+		if s.start != nil {
+			lo = clampJPIndex(*s.start, len(a))
+		}
+		if s.end != nil {
+			hi = clampJPIndex(*s.end, len(a))
+		}
+		if lo >= hi {
+			return nil
+		}
+		return a[lo:hi]
+	case jpFilter:
+		var out []any
+		for _, el := range jpChildren(v) {
+			if s.filter.match(root, el) {
+				out = append(out, el)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func clampJPIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// jpChildren returns the elements of an array or the values of an object,
+// ordered by key.
+func jpChildren(v any) []any {
+	switch v := v.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		out := make([]any, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+func collectJPField(v any, name string, out *[]any) {
+	if m, ok := v.(map[string]any); ok {
+		if f, ok := m[name]; ok {
+			*out = append(*out, f)
+		}
+	}
+	for _, child := range jpChildren(v) {
+		collectJPField(child, name, out)
+	}
+}
+
+func (f *jpFilterExpr) match(root, el any) bool {
+	values := f.path.eval(root, el)
+	if f.op == "" {
+		return len(values) > 0 && values[0] != nil && values[0] != false && values[0] != ""
+	}
+
+	equal := len(values) > 0 && values[0] == f.value
+	if f.op == "!=" {
+		return !equal
+	}
+	return equal
+}
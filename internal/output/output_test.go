@@ -330,6 +330,25 @@ func TestFormatTimestamp(t *testing.T) {
 	})
 }
 
+func TestFormatExpiry(t *testing.T) {
+	t.Run("zero", func(t *testing.T) {
+		if got := FormatExpiry(time.Time{}); got != "<never>" {
+			t.Fatalf("want <never>, got %q", got)
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		if got := FormatExpiry(time.Now().Add(-time.Minute)); got != "expired" {
+			t.Fatalf("want expired, got %q", got)
+		}
+	})
+	t.Run("future", func(t *testing.T) {
+		got := FormatExpiry(time.Now().Add(90 * time.Minute))
+		if got != "1h" {
+			t.Fatalf("want 1h, got %q", got)
+		}
+	})
+}
+
 func TestFormatLabels(t *testing.T) {
 	t.Run("nil", func(t *testing.T) {
 		if got := FormatLabels(nil); got != "<none>" {
@@ -718,3 +737,280 @@ func TestFormatDuration_LargeDuration(t *testing.T) {
 	got := formatDuration(365 * 24 * time.Hour)
 	require.Equal(t, "365d", got)
 }
+
+// ---------------------------------------------------------------------------
+// PrintObject
+// ---------------------------------------------------------------------------
+
+type testObject struct {
+	Name  string `json:"name" yaml:"name"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+func TestPrintObject_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Printer{Format: FormatJSON, Out: &buf}
+
+	require.NoError(t, p.PrintObject(testObject{Name: "prod", Count: 2}, nil))
+
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	require.Equal(t, "prod", parsed["name"])
+	require.InDelta(t, 2, parsed["count"], 0)
+}
+
+func TestPrintObject_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Printer{Format: FormatYAML, Out: &buf}
+
+	require.NoError(t, p.PrintObject(testObject{Name: "prod"}, nil))
+	require.Contains(t, buf.String(), "name: prod")
+}
+
+func TestPrintObject_Table(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Printer{Format: FormatTable, Out: &buf}
+
+	err := p.PrintObject(testObject{Name: "prod"}, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "NAME\tCOUNT")
+		fmt.Fprintln(w, "prod\t0")
+	})
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "NAME")
+	require.Contains(t, buf.String(), "prod")
+}
+
+func TestPrintObject_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Printer{Format: "xml", Out: &buf}
+
+	err := p.PrintObject(testObject{}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported format")
+}
+
+// ---------------------------------------------------------------------------
+// Template formats
+// ---------------------------------------------------------------------------
+
+func TestParseFormat_Templates(t *testing.T) {
+	f, err := ParseFormat("jsonpath={.clusters[*].name}")
+	require.NoError(t, err)
+	require.Equal(t, FormatJSONPath, f.Kind())
+	require.Equal(t, "{.clusters[*].name}", f.Arg())
+
+	f, err = ParseFormat("go-template={{.name}}={{.id}}")
+	require.NoError(t, err)
+	require.Equal(t, FormatGoTemplate, f.Kind())
+	require.Equal(t, "{{.name}}={{.id}}", f.Arg())
+
+	_, err = ParseFormat("name")
+	require.NoError(t, err)
+
+	_, err = ParseFormat("jsonpath=")
+	require.ErrorContains(t, err, "requires an argument")
+	_, err = ParseFormat("jsonpath={.a")
+	require.ErrorContains(t, err, "unclosed {")
+	_, err = ParseFormat("go-template={{.a")
+	require.ErrorContains(t, err, "invalid go-template")
+	_, err = ParseFormat("json=x")
+	require.ErrorContains(t, err, "does not take an argument")
+}
+
+func TestFormat_IsTable(t *testing.T) {
+	require.True(t, FormatTable.IsTable())
+	require.True(t, FormatWide.IsTable())
+	require.False(t, FormatJSON.IsTable())
+	require.False(t, Format("jsonpath={.a}").IsTable())
+}
+
+func listData(t *testing.T) any {
+	t.Helper()
+	var data any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"clusters": [
+			{"name": "prod", "labels": {"env": "production"}, "nodes": 3, "ready": true},
+			{"name": "dev", "labels": {"env": "dev", "team": "core"}, "nodes": 1, "ready": false}
+		],
+		"nextPageToken": ""
+	}`), &data))
+	return data
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{"{.clusters[*].name}", "prod dev"},
+		{"{$.clusters[0].name}", "prod"},
+		{"{.clusters[-1].name}", "dev"},
+		{"{.clusters[0:1].name}", "prod"},
+		{"{.clusters[1].labels}", `{"env":"dev","team":"core"}`},
+		{"{.clusters[0].nodes} {.clusters[0].ready}", "3 true"},
+		{"{..env}", "production dev"},
+		{"{.clusters[?(@.labels.env==\"dev\")].name}", "dev"},
+		{"{.clusters[?(@.nodes != 1)].name}", "prod"},
+		{"{.clusters[?(@.labels.team)].name}", "dev"},
+		{"{.clusters[0]['name']}", "prod"},
+		{"{.clusters[0].missing}", ""},
+		{`{range .clusters[*]}{.name}{"\t"}{.nodes}{"\n"}{end}`, "prod\t3\ndev\t1\n"},
+		{`{range .clusters[*]}{range .labels.*}[{.}]{end};{end}`, "[production];[dev][core];"},
+		{"names: {.clusters[*].name}!", "names: prod dev!"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			jp, err := ParseJSONPath(tc.tmpl)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, jp.Execute(&buf, listData(t)))
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestParseJSONPath_Errors(t *testing.T) {
+	for _, tmpl := range []string{
+		"{.a",
+		"{range .a[*]}{.b}",
+		"{.a}{end}",
+		"{.a[x]}",
+		"{.a[0}",
+		`{"unterminated}`,
+		"{.a!b}",
+	} {
+		t.Run(tmpl, func(t *testing.T) {
+			_, err := ParseJSONPath(tmpl)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPrintResource_TemplateFormats(t *testing.T) {
+	msg, err := structpb.NewStruct(map[string]any{
+		"clusters": []any{
+			map[string]any{"name": "prod", "id": "c-1"},
+			map[string]any{"name": "dev", "id": "c-2"},
+		},
+		"nextPageToken": "",
+	})
+	require.NoError(t, err)
+
+	tmplFile := t.TempDir() + "/tmpl"
+	require.NoError(t, os.WriteFile(tmplFile, []byte(`{{range .clusters}}{{.id}} {{end}}`), 0o600))
+
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatName, "prod\ndev\n"},
+		{"jsonpath={.clusters[*].id}", "c-1 c-2"},
+		{`go-template={{range .clusters}}{{.name}},{{end}}`, "prod,dev,"},
+		{Format("go-template-file=" + tmplFile), "c-1 c-2 "},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.format.Kind()), func(t *testing.T) {
+			var buf bytes.Buffer
+			p := &Printer{Format: tc.format, Out: &buf}
+			require.NoError(t, p.PrintResource(msg, nil))
+			require.Equal(t, tc.want, buf.String())
+
+			buf.Reset()
+			require.NoError(t, p.PrintDetail(msg, nil))
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestPrintObject_TemplateFormats(t *testing.T) {
+	v := struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}{"PORT", "8080"}
+
+	var buf bytes.Buffer
+	p := &Printer{Format: FormatName, Out: &buf}
+	require.NoError(t, p.PrintObject(v, nil))
+	require.Equal(t, "PORT\n", buf.String())
+
+	buf.Reset()
+	p.Format = "jsonpath={.value}"
+	require.NoError(t, p.PrintObject(v, nil))
+	require.Equal(t, "8080", buf.String())
+}
+
+// ---------------------------------------------------------------------------
+// Custom columns
+// ---------------------------------------------------------------------------
+
+func TestParseCustomColumns(t *testing.T) {
+	cols, err := ParseCustomColumns("NAME:.name,UID:{.clusterUid},TEAM:labels.team")
+	require.NoError(t, err)
+	require.Len(t, cols, 3)
+	require.Equal(t, "TEAM", cols[2].Header)
+
+	for _, spec := range []string{"", "NAME", "NAME:", ":.name", "NAME:.name,", "NAME:{.name"} {
+		_, err := ParseCustomColumns(spec)
+		require.Error(t, err, spec)
+	}
+
+	_, err = ParseFormat("custom-columns=NAME")
+	require.ErrorContains(t, err, "expected HEADER:PATH")
+	_, err = ParseFormat("view=")
+	require.ErrorContains(t, err, "requires an argument")
+}
+
+func TestParseCustomColumnsFile(t *testing.T) {
+	cols, err := ParseCustomColumnsFile("NAME   TEAM\n.name  .labels.team\n")
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+
+	_, err = ParseCustomColumnsFile("NAME\n")
+	require.ErrorContains(t, err, "exactly 2 lines")
+	_, err = ParseCustomColumnsFile("NAME TEAM\n.name\n")
+	require.ErrorContains(t, err, "2 headers but 1 paths")
+}
+
+func TestPrintResource_CustomColumns(t *testing.T) {
+	list, err := structpb.NewStruct(map[string]any{
+		"clusters": []any{
+			map[string]any{"name": "prod", "clusterUid": "u-1", "labels": map[string]any{"team": "core"}},
+			map[string]any{"name": "dev", "clusterUid": "u-2", "labels": map[string]any{}},
+		},
+		"nextPageToken": "",
+	})
+	require.NoError(t, err)
+
+	colsFile := t.TempDir() + "/cols"
+	require.NoError(t, os.WriteFile(colsFile, []byte("NAME UID TEAM\n.name .clusterUid .labels.team\n"), 0o600))
+
+	want := "NAME   UID   TEAM\nprod   u-1   core\ndev    u-2   <none>\n"
+	for _, f := range []Format{
+		"custom-columns=NAME:.name,UID:.clusterUid,TEAM:.labels.team",
+		Format("custom-columns-file=" + colsFile),
+	} {
+		var buf bytes.Buffer
+		p := &Printer{Format: f, Out: &buf}
+		require.NoError(t, p.PrintResource(list, nil))
+		require.Equal(t, want, buf.String())
+	}
+
+	single, err := structpb.NewStruct(map[string]any{
+		"cluster": map[string]any{"name": "prod", "clusterUid": "u-1"},
+	})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	p := &Printer{Format: "custom-columns=NAME:.name,UID:.clusterUid", Out: &buf}
+	require.NoError(t, p.PrintDetail(single, nil))
+	require.Equal(t, "NAME   UID\nprod   u-1\n", buf.String())
+
+	empty, err := structpb.NewStruct(map[string]any{"clusters": []any{}})
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, p.PrintResource(empty, nil))
+	require.Equal(t, "NAME   UID\n", buf.String())
+
+	p.Format = "view=ops"
+	require.ErrorContains(t, p.PrintResource(single, nil), "not supported")
+}
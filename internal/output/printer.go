@@ -23,6 +23,25 @@ func Writeln(w io.Writer, a ...any) {
 	_, _ = fmt.Fprintln(w, a...)
 }
 
+// IsTerminal reports whether w is an interactive terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether ANSI colors should be written to w: it must
+// be a terminal and $NO_COLOR must be unset (https://no-color.org).
+func ColorEnabled(w io.Writer) bool {
+	return os.Getenv("NO_COLOR") == "" && IsTerminal(w)
+}
+
 // Printer handles output formatting for CLI commands.
 type Printer struct {
 	Format Format
@@ -40,14 +59,45 @@ func NewPrinter(format Format) *Printer {
 // PrintResource routes output based on the configured format.
 // For table/wide formats, it calls the provided tableFn.
 // For json/yaml, it marshals the proto message.
+// For name, template and column formats, it evaluates against the JSON form
+// of msg.
 func (p *Printer) PrintResource(msg proto.Message, tableFn func(w *tabwriter.Writer)) error {
-	switch p.Format {
+	switch p.Format.Kind() {
 	case FormatJSON:
 		return p.printProtoJSON(msg)
 	case FormatYAML:
 		return p.printProtoYAML(msg)
 	case FormatTable, FormatWide:
 		return p.printTable(tableFn)
+	case FormatName, FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile,
+		FormatCustomColumns, FormatCustomColumnsFile, FormatView:
+		return p.printProtoTemplate(msg)
+	default:
+		return fmt.Errorf("unsupported format: %s", p.Format)
+	}
+}
+
+// PrintObject routes output for values that are not proto messages, such as
+// local CLI state. For table/wide formats, it calls the provided tableFn.
+// For json/yaml, it marshals v using its struct tags, as do the name,
+// template and column formats.
+func (p *Printer) PrintObject(v any, tableFn func(w *tabwriter.Writer)) error {
+	switch p.Format.Kind() {
+	case FormatJSON:
+		enc := json.NewEncoder(p.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case FormatYAML:
+		return yaml.NewEncoder(p.Out).Encode(v)
+	case FormatTable, FormatWide:
+		return p.printTable(tableFn)
+	case FormatName, FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile,
+		FormatCustomColumns, FormatCustomColumnsFile, FormatView:
+		data, err := objectData(v)
+		if err != nil {
+			return err
+		}
+		return p.printTemplate(data)
 	default:
 		return fmt.Errorf("unsupported format: %s", p.Format)
 	}
@@ -68,6 +118,8 @@ type Section struct {
 // PrintDetail renders a single resource in kubectl-describe style.
 // For json/yaml, it marshals the proto message.
 // For table/wide, it renders key-value pairs grouped by section.
+// For name, template and column formats, it evaluates against the JSON form
+// of msg.
 //
 // Example output:
 //
@@ -80,20 +132,23 @@ type Section struct {
 //	  env=production
 //	  team=platform
 func (p *Printer) PrintDetail(msg proto.Message, sections []Section) error {
-	switch p.Format {
+	switch p.Format.Kind() {
 	case FormatJSON:
 		return p.printProtoJSON(msg)
 	case FormatYAML:
 		return p.printProtoYAML(msg)
 	case FormatTable, FormatWide:
 		return p.printDetail(sections)
+	case FormatName, FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile,
+		FormatCustomColumns, FormatCustomColumnsFile, FormatView:
+		return p.printProtoTemplate(msg)
 	default:
 		return fmt.Errorf("unsupported format: %s", p.Format)
 	}
 }
 
 func (p *Printer) printDetail(sections []Section) error {
-	w := tabwriter.NewWriter(p.Out, 0, 0, 3, ' ', 0)
+	w := NewTableWriter(p.Out)
 
 	for i, section := range sections {
 		if i > 0 {
@@ -160,8 +215,13 @@ func (p *Printer) printProtoYAML(msg proto.Message) error {
 	return err
 }
 
+// NewTableWriter returns a tabwriter with the padding used by every table.
+func NewTableWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+}
+
 func (p *Printer) printTable(fn func(w *tabwriter.Writer)) error {
-	w := tabwriter.NewWriter(p.Out, 0, 0, 3, ' ', 0)
+	w := NewTableWriter(p.Out)
 	fn(w)
 	return w.Flush()
 }
@@ -25,6 +25,19 @@ func FormatTimestamp(ts *timestamppb.Timestamp) string {
 	return ts.AsTime().Format(time.RFC3339)
 }
 
+// FormatExpiry returns a human-readable time remaining until t.
+// Example: "45m", "expired", "<never>"
+func FormatExpiry(t time.Time) string {
+	if t.IsZero() {
+		return "<never>"
+	}
+	d := time.Until(t)
+	if d <= 0 {
+		return "expired"
+	}
+	return formatDuration(d)
+}
+
 // FormatLabels returns a comma-separated key=value string from a label map.
 func FormatLabels(labels map[string]string) string {
 	if len(labels) == 0 {
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"text/template"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// nameFields are the fields -o name prints, in order of preference.
+var nameFields = []string{"name", "key", "id"}
+
+// printProtoTemplate evaluates the name, template or column format against
+// msg as printed by -o json.
+func (p *Printer) printProtoTemplate(msg proto.Message) error {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return fmt.Errorf("failed to parse json: %w", err)
+	}
+	return p.printTemplate(data)
+}
+
+// objectData converts v to the generic form encoding/json decodes it into.
+func objectData(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %w", err)
+	}
+	return data, nil
+}
+
+// printTemplate renders data, a value decoded from JSON, with the name,
+// template or column format.
+func (p *Printer) printTemplate(data any) error {
+	switch p.Format.Kind() {
+	case FormatName:
+		w := bufio.NewWriter(p.Out)
+		for _, name := range resourceNames(data) {
+			Writeln(w, name)
+		}
+		return w.Flush()
+	case FormatJSONPath:
+		jp, err := ParseJSONPath(p.Format.Arg())
+		if err != nil {
+			return err
+		}
+		return jp.Execute(p.Out, data)
+	case FormatGoTemplate:
+		return executeGoTemplate(p.Out, p.Format.Arg(), data)
+	case FormatGoTemplateFile:
+		text, err := os.ReadFile(p.Format.Arg())
+		if err != nil {
+			return fmt.Errorf("failed to read go-template-file: %w", err)
+		}
+		return executeGoTemplate(p.Out, string(text), data)
+	case FormatCustomColumns:
+		cols, err := ParseCustomColumns(p.Format.Arg())
+		if err != nil {
+			return err
+		}
+		return p.printColumns(cols, data)
+	case FormatCustomColumnsFile:
+		cols, err := readCustomColumnsFile(p.Format.Arg())
+		if err != nil {
+			return err
+		}
+		return p.printColumns(cols, data)
+	case FormatView:
+		return fmt.Errorf("output view %q is not supported by this command", p.Format.Arg())
+	default:
+		return fmt.Errorf("unsupported format: %s", p.Format)
+	}
+}
+
+func executeGoTemplate(w io.Writer, text string, data any) error {
+	t, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid go-template: %w", err)
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("go-template: %w", err)
+	}
+	return nil
+}
+
+// resourceNames returns the names of the resources in data. An object with a
+// name, key or id is a resource; otherwise its fields are searched in key
+// order, so both a single resource and a list response are handled.
+func resourceNames(data any) []string {
+	switch v := data.(type) {
+	case []any:
+		var names []string
+		for _, el := range v {
+			names = append(names, resourceNames(el)...)
+		}
+		return names
+	case map[string]any:
+		for _, f := range nameFields {
+			if name, ok := v[f].(string); ok && name != "" {
+				return []string{name}
+			}
+		}
+		var names []string
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			names = append(names, resourceNames(v[k])...)
+		}
+		return names
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+// Package variables resolves configuration variables across scopes.
+package variables
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// MaskedValue is displayed in place of sensitive values that weren't revealed.
+const MaskedValue = "<sensitive>"
+
+// listPageSize is the page size used when fetching every variable in a scope.
+const listPageSize = 100
+
+// Effective resolves the variables that apply to app in env. Global variables
+// are overridden by app variables, which are overridden by app+environment
+// variables. Each returned variable keeps the scope it was resolved from.
+// The result is sorted by key. An empty env resolves global and app scopes
+// only.
+func Effective(ctx context.Context, c variablev1.VariableAPIClient, app, env string, reveal bool) ([]*variablev1.Variable, error) {
+	scopes := []*variablev1.ListVariablesRequest{
+		{Scope: variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL},
+		{Scope: variablev1.VariableScope_VARIABLE_SCOPE_APP, ApplicationId: app},
+	}
+	if env != "" {
+		scopes = append(scopes, &variablev1.ListVariablesRequest{
+			Scope:         variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV,
+			ApplicationId: app,
+			EnvironmentId: env,
+		})
+	}
+
+	layers := make([][]*variablev1.Variable, 0, len(scopes))
+	for _, req := range scopes {
+		req.RevealSensitive = reveal
+		vars, err := ListAll(ctx, c, req)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, vars)
+	}
+
+	return merge(layers...), nil
+}
+
+// merge overlays layers in order, later layers overriding earlier ones by
+// key, and returns the result sorted by key.
+func merge(layers ...[]*variablev1.Variable) []*variablev1.Variable {
+	merged := map[string]*variablev1.Variable{}
+	for _, vars := range layers {
+		for _, v := range vars {
+			merged[v.Key] = v
+		}
+	}
+
+	result := make([]*variablev1.Variable, 0, len(merged))
+	for _, v := range merged {
+		result = append(result, v)
+	}
+	slices.SortFunc(result, func(a, b *variablev1.Variable) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
+}
+
+// ListAll fetches every page of variables matching req, advancing
+// req.PageToken as it goes.
+func ListAll(ctx context.Context, c variablev1.VariableAPIClient, req *variablev1.ListVariablesRequest) ([]*variablev1.Variable, error) {
+	if req.PageSize == 0 {
+		req.PageSize = listPageSize
+	}
+
+	var vars []*variablev1.Variable
+	for {
+		resp, err := c.ListVariables(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, resp.Variables...)
+
+		if resp.NextPageToken == "" {
+			return vars, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// DisplayValue returns v's value, masked if it is sensitive and reveal is
+// false.
+func DisplayValue(v *variablev1.Variable, reveal bool) string {
+	if v.Sensitive && !reveal {
+		return MaskedValue
+	}
+	return v.Value
+}
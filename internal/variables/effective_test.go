@@ -0,0 +1,43 @@
+package variables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestMerge(t *testing.T) {
+	global := []*variablev1.Variable{
+		{Key: "LOG_LEVEL", Value: "info", Scope: variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL},
+		{Key: "REGION", Value: "us-east-1", Scope: variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL},
+	}
+	app := []*variablev1.Variable{
+		{Key: "LOG_LEVEL", Value: "warn", Scope: variablev1.VariableScope_VARIABLE_SCOPE_APP},
+		{Key: "PORT", Value: "8080", Scope: variablev1.VariableScope_VARIABLE_SCOPE_APP},
+	}
+	appEnv := []*variablev1.Variable{
+		{Key: "LOG_LEVEL", Value: "debug", Scope: variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV},
+	}
+
+	got := merge(global, app, appEnv)
+
+	require.Len(t, got, 3)
+	require.Equal(t, "LOG_LEVEL", got[0].Key)
+	require.Equal(t, "debug", got[0].Value)
+	require.Equal(t, variablev1.VariableScope_VARIABLE_SCOPE_APP_ENV, got[0].Scope)
+	require.Equal(t, "PORT", got[1].Key)
+	require.Equal(t, variablev1.VariableScope_VARIABLE_SCOPE_APP, got[1].Scope)
+	require.Equal(t, "REGION", got[2].Key)
+	require.Equal(t, variablev1.VariableScope_VARIABLE_SCOPE_GLOBAL, got[2].Scope)
+}
+
+func TestDisplayValue(t *testing.T) {
+	secret := &variablev1.Variable{Key: "TOKEN", Value: "s3cr3t", Sensitive: true}
+	plain := &variablev1.Variable{Key: "PORT", Value: "8080"}
+
+	require.Equal(t, MaskedValue, DisplayValue(secret, false))
+	require.Equal(t, "s3cr3t", DisplayValue(secret, true))
+	require.Equal(t, "8080", DisplayValue(plain, false))
+}
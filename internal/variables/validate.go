@@ -0,0 +1,107 @@
+package variables
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+// CompilePattern compiles a variable's pattern. Patterns must match the
+// whole value, so they are anchored at both ends.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// Validate checks v.Value against v.Type and v.Pattern. Untyped variables
+// accept any value. The value is quoted in errors unless v is sensitive.
+func Validate(v *variablev1.Variable) error {
+	if msg := checkType(v.Value, v.Type); msg != "" {
+		return validationError(v, msg)
+	}
+
+	if v.Pattern == "" {
+		return nil
+	}
+	re, err := CompilePattern(v.Pattern)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.Key, err)
+	}
+	if !re.MatchString(v.Value) {
+		return validationError(v, fmt.Sprintf("does not match pattern %q", v.Pattern))
+	}
+	return nil
+}
+
+// ParseType converts a type name, as accepted by --type, to its API enum.
+func ParseType(s string) (variablev1.VariableType, error) {
+	switch s {
+	case "string":
+		return variablev1.VariableType_VARIABLE_TYPE_STRING, nil
+	case "int":
+		return variablev1.VariableType_VARIABLE_TYPE_INT, nil
+	case "bool":
+		return variablev1.VariableType_VARIABLE_TYPE_BOOL, nil
+	case "duration":
+		return variablev1.VariableType_VARIABLE_TYPE_DURATION, nil
+	case "url":
+		return variablev1.VariableType_VARIABLE_TYPE_URL, nil
+	case "json":
+		return variablev1.VariableType_VARIABLE_TYPE_JSON, nil
+	default:
+		return 0, fmt.Errorf("invalid type %q: must be one of string, int, bool, duration, url, json", s)
+	}
+}
+
+// FormatType returns the display name of a variable type. Untyped
+// variables are plain strings.
+func FormatType(t variablev1.VariableType) string {
+	if t == variablev1.VariableType_VARIABLE_TYPE_UNSPECIFIED {
+		return "string"
+	}
+	return strings.ToLower(strings.TrimPrefix(t.String(), "VARIABLE_TYPE_"))
+}
+
+// checkType returns why value isn't of type t, or "" if it is.
+func checkType(value string, t variablev1.VariableType) string {
+	switch t {
+	case variablev1.VariableType_VARIABLE_TYPE_INT:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "is not an int"
+		}
+	case variablev1.VariableType_VARIABLE_TYPE_BOOL:
+		// Only the spellings every consumer agrees on.
+		if value != "true" && value != "false" {
+			return "is not a bool (true or false)"
+		}
+	case variablev1.VariableType_VARIABLE_TYPE_DURATION:
+		if _, err := time.ParseDuration(value); err != nil {
+			return "is not a duration (e.g. 30s, 5m, 1h30m)"
+		}
+	case variablev1.VariableType_VARIABLE_TYPE_URL:
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return "is not an absolute URL"
+		}
+	case variablev1.VariableType_VARIABLE_TYPE_JSON:
+		if !json.Valid([]byte(value)) {
+			return "is not valid JSON"
+		}
+	}
+	return ""
+}
+
+func validationError(v *variablev1.Variable, msg string) error {
+	if v.Sensitive {
+		return fmt.Errorf("%s: value %s", v.Key, msg)
+	}
+	return fmt.Errorf("%s: %q %s", v.Key, v.Value, msg)
+}
@@ -0,0 +1,59 @@
+package variables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	variablev1 "go.admiral.io/sdk/proto/admiral/api/variable/v1"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       *variablev1.Variable
+		wantErr string
+	}{
+		{name: "untyped", v: &variablev1.Variable{Key: "A", Value: "anything"}},
+		{name: "int", v: &variablev1.Variable{Key: "REPLICAS", Value: "-3", Type: variablev1.VariableType_VARIABLE_TYPE_INT}},
+		{name: "bad int", v: &variablev1.Variable{Key: "REPLICAS", Value: "three", Type: variablev1.VariableType_VARIABLE_TYPE_INT}, wantErr: `REPLICAS: "three" is not an int`},
+		{name: "bool", v: &variablev1.Variable{Key: "DEBUG", Value: "false", Type: variablev1.VariableType_VARIABLE_TYPE_BOOL}},
+		{name: "bad bool", v: &variablev1.Variable{Key: "DEBUG", Value: "yes", Type: variablev1.VariableType_VARIABLE_TYPE_BOOL}, wantErr: "is not a bool"},
+		{name: "duration", v: &variablev1.Variable{Key: "TIMEOUT", Value: "1h30m", Type: variablev1.VariableType_VARIABLE_TYPE_DURATION}},
+		{name: "bad duration", v: &variablev1.Variable{Key: "TIMEOUT", Value: "30", Type: variablev1.VariableType_VARIABLE_TYPE_DURATION}, wantErr: "is not a duration"},
+		{name: "url", v: &variablev1.Variable{Key: "API", Value: "https://example.com/v1", Type: variablev1.VariableType_VARIABLE_TYPE_URL}},
+		{name: "relative url", v: &variablev1.Variable{Key: "API", Value: "/v1", Type: variablev1.VariableType_VARIABLE_TYPE_URL}, wantErr: "is not an absolute URL"},
+		{name: "json", v: &variablev1.Variable{Key: "CFG", Value: `{"a":[1,2]}`, Type: variablev1.VariableType_VARIABLE_TYPE_JSON}},
+		{name: "bad json", v: &variablev1.Variable{Key: "CFG", Value: `{a:1}`, Type: variablev1.VariableType_VARIABLE_TYPE_JSON}, wantErr: "is not valid JSON"},
+		{name: "pattern", v: &variablev1.Variable{Key: "LEVEL", Value: "warn", Pattern: "debug|info|warn"}},
+		{name: "pattern is anchored", v: &variablev1.Variable{Key: "LEVEL", Value: "warning", Pattern: "debug|info|warn"}, wantErr: `"warning" does not match pattern "debug|info|warn"`},
+		{name: "invalid pattern", v: &variablev1.Variable{Key: "LEVEL", Value: "x", Pattern: "("}, wantErr: `LEVEL: invalid pattern "("`},
+		{name: "sensitive value hidden", v: &variablev1.Variable{Key: "PIN", Value: "12a4", Type: variablev1.VariableType_VARIABLE_TYPE_INT, Sensitive: true}, wantErr: "PIN: value is not an int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.v)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+			if tt.v.Sensitive {
+				require.NotContains(t, err.Error(), tt.v.Value)
+			}
+		})
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for _, name := range []string{"string", "int", "bool", "duration", "url", "json"} {
+		typ, err := ParseType(name)
+		require.NoError(t, err)
+		require.Equal(t, name, FormatType(typ))
+	}
+
+	_, err := ParseType("float")
+	require.ErrorContains(t, err, `invalid type "float"`)
+
+	require.Equal(t, "string", FormatType(variablev1.VariableType_VARIABLE_TYPE_UNSPECIFIED))
+}